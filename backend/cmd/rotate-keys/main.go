@@ -0,0 +1,44 @@
+// Command rotate-keys re-encrypts FIR data keys and officer signing keys
+// under the current ENCRYPTION_KEY, recomputes the complainant blind
+// indexes, and encrypts complainant details stored before encryption was
+// enabled.
+//
+// To rotate, move the old secret to ENCRYPTION_OLD_KEYS under its ID, set
+// ENCRYPTION_KEY and ENCRYPTION_KEY_ID to the new one, restart the server
+// and run this command. Once it succeeds the old secret can be removed.
+// The command is safe to run again, and to run while the server is up.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	database.InitMongoDB(cfg.MongoURI)
+	defer database.CloseMongoDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	report, err := services.NewFIRService(cfg).RotateKeys(ctx)
+	if report != nil {
+		log.Printf("Encryption key %s: %d FIR data keys re-encrypted, %d FIRs encrypted, %d officer keys re-encrypted",
+			report.KeyID, report.RewrappedFIRs, report.EncryptedFIRs, report.OfficerKeys)
+	}
+	if err != nil {
+		log.Fatal("Failed to rotate keys: ", err)
+	}
+}
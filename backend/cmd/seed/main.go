@@ -0,0 +1,229 @@
+// Command seed loads the bundled legal corpus into MongoDB.
+//
+// The dataset is embedded in the binary and described by data/manifest.json,
+// which records the dataset version and a SHA-256 checksum for every file.
+// Records are upserted on their natural keys, so running seed again updates
+// the existing documents instead of duplicating them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/services"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:embed data
+var dataFS embed.FS
+
+// datasetID identifies the corpus in the dataset_versions collection.
+const datasetID = "legal_corpus"
+
+type manifest struct {
+	Version string         `json:"version"`
+	Files   []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "validate the dataset and report what would change without writing")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	m, err := loadManifest()
+	if err != nil {
+		log.Fatal("Invalid dataset: ", err)
+	}
+	files, err := verifyFiles(m)
+	if err != nil {
+		log.Fatal("Invalid dataset: ", err)
+	}
+	log.Printf("Dataset %s verified (%d files)", m.Version, len(m.Files))
+
+	database.InitMongoDB(cfg.MongoURI)
+	defer database.CloseMongoDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	legalService := services.NewLegalService()
+	if !*dryRun {
+		if err := legalService.EnsureIndexes(ctx); err != nil {
+			log.Fatal("Failed to create legal database indexes: ", err)
+		}
+	}
+
+	var results []*services.ImportResult
+	failed := 0
+	for _, file := range m.Files {
+		result, err := legalService.Import(ctx, file.Kind, services.ImportFormatJSONL, bytes.NewReader(files[file.Name]), *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", file.Name, err)
+		}
+		for _, rowErr := range result.Errors {
+			log.Printf("%s:%d: %v", file.Name, rowErr.Row, rowErr.Errors)
+		}
+		failed += result.Failed
+		results = append(results, result)
+	}
+
+	printSummary(m, results, *dryRun)
+
+	if failed > 0 {
+		log.Printf("%d records failed to import", failed)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		if err := recordVersion(ctx, m); err != nil {
+			log.Fatal("Failed to record dataset version: ", err)
+		}
+		indexEmbeddings(ctx, cfg, results)
+	}
+}
+
+// indexEmbeddings embeds the sections the import added or changed, when an
+// embedding model is configured.
+func indexEmbeddings(ctx context.Context, cfg *config.Config, results []*services.ImportResult) {
+	reembed := 0
+	for _, result := range results {
+		reembed += result.Reembed
+	}
+	if reembed == 0 {
+		return
+	}
+	indexed, err := services.NewAIService(cfg).IndexEmbeddings(ctx)
+	if err != nil {
+		log.Println("Failed to index legal section embeddings:", err)
+	} else if indexed > 0 {
+		log.Printf("Indexed embeddings for %d legal sections", indexed)
+	}
+}
+
+func loadManifest() (*manifest, error) {
+	data, err := dataFS.ReadFile("data/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifest.json: %w", err)
+	}
+	if m.Version == "" || len(m.Files) == 0 {
+		return nil, fmt.Errorf("manifest.json: version and files are required")
+	}
+	return &m, nil
+}
+
+// verifyFiles checks every data file against its manifest checksum and
+// rejects files the manifest does not list, so a dataset cannot be edited
+// without updating the manifest.
+func verifyFiles(m *manifest) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(m.Files))
+	for _, file := range m.Files {
+		data, err := dataFS.ReadFile(path.Join("data", file.Name))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if actual := hex.EncodeToString(sum[:]); actual != file.SHA256 {
+			return nil, fmt.Errorf("%s: checksum mismatch: manifest has %s, file has %s", file.Name, file.SHA256, actual)
+		}
+		if records := countRecords(data); records != file.Records {
+			return nil, fmt.Errorf("%s: manifest lists %d records, file has %d", file.Name, file.Records, records)
+		}
+		files[file.Name] = data
+	}
+
+	entries, err := fs.ReadDir(dataFS, "data")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if _, listed := files[entry.Name()]; !listed && entry.Name() != "manifest.json" {
+			return nil, fmt.Errorf("%s is not listed in manifest.json", entry.Name())
+		}
+	}
+	return files, nil
+}
+
+// countRecords counts the non-blank lines of a JSON Lines file.
+func countRecords(data []byte) int {
+	records := 0
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			records++
+		}
+	}
+	return records
+}
+
+func printSummary(m *manifest, results []*services.ImportResult, dryRun bool) {
+	mode := ""
+	if dryRun {
+		mode = " (dry run)"
+	}
+	fmt.Printf("\nLegal corpus %s%s\n\n", m.Version, mode)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tKIND\tRECORDS\tINSERTED\tUPDATED\tFAILED\t")
+	var total, inserted, updated, failed int
+	for i, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t\n", m.Files[i].Name, result.Kind, result.Total, result.Inserted, result.Updated, result.Failed)
+		total += result.Total
+		inserted += result.Inserted
+		updated += result.Updated
+		failed += result.Failed
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t%d\t%d\t\n", total, inserted, updated, failed)
+	w.Flush()
+	fmt.Println()
+}
+
+// recordVersion stores which dataset version was last applied so
+// environments can be checked against the bundled corpus.
+func recordVersion(ctx context.Context, m *manifest) error {
+	checksums := bson.M{}
+	for _, file := range m.Files {
+		checksums[file.Name] = file.SHA256
+	}
+
+	_, err := database.GetCollection("dataset_versions").UpdateOne(ctx,
+		bson.M{"_id": datasetID},
+		bson.M{"$set": bson.M{
+			"version":    m.Version,
+			"checksums":  checksums,
+			"applied_at": time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,72 @@
+// Command verify-ledger recomputes the FIR ledger hash chains and reports
+// every submitted FIR or ledger entry that has been altered since
+// submission.
+//
+// It exits with status 1 when any station's ledger fails verification, so
+// it can run from cron or a CI job against a production replica.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	station := flag.String("station", "", "verify only this station's ledger")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.Load()
+
+	database.InitMongoDB(cfg.MongoURI)
+	defer database.CloseMongoDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	reports, err := services.NewFIRService(cfg).VerifyLedger(ctx, *station)
+	if err != nil {
+		log.Fatal("Failed to verify ledger: ", err)
+	}
+
+	if !printReports(reports) {
+		os.Exit(1)
+	}
+}
+
+// printReports writes a summary line per station followed by every
+// problem found, and reports whether all ledgers verified.
+func printReports(reports []services.LedgerReport) bool {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATION\tENTRIES\tSTATUS\t")
+	valid := true
+	for _, report := range reports {
+		status := "ok"
+		if !report.Valid {
+			status = fmt.Sprintf("%d problems", len(report.Problems))
+			valid = false
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t\n", report.Station, report.Entries, status)
+	}
+	w.Flush()
+
+	for _, report := range reports {
+		for _, problem := range report.Problems {
+			fmt.Printf("%s #%d %s (%s): %s\n", report.Station, problem.Seq, problem.FIRNumber, problem.FIRID, problem.Problem)
+		}
+	}
+	return valid
+}
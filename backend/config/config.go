@@ -2,29 +2,109 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
-	Port           string
-	MongoURI       string
-	JWTSecret      string
-	OpenAIAPIKey   string
-	SpeechAPIKey   string
-	EncryptionKey  string
-	CORSOrigin     string
-	AppEnv         string
+	Port          string
+	MongoURI      string
+	JWTSecret     string
+	OpenAIAPIKey  string
+	SpeechAPIKey  string
+	EncryptionKey string
+	CORSOrigin    string
+	AppEnv        string
+
+	// Encryption keys. EncryptionKey is the current secret, identified by
+	// EncryptionKeyID. Secrets being rotated out stay in EncryptionOldKeys,
+	// as comma-separated id:secret pairs, until cmd/rotate-keys has
+	// re-encrypted everything under the current one.
+	EncryptionKeyID   string
+	EncryptionOldKeys string
+
+	// LLM settings. LLMProvider is one of "openai", "local" or "mock"; when
+	// empty it is derived from whether OpenAIAPIKey is set.
+	LLMProvider       string
+	LLMBaseURL        string
+	LLMModel          string
+	LLMEmbeddingModel string
+	LLMTimeout        time.Duration
+
+	// TranslatorProvider is "llm" or "fake"; when empty the LLM translator
+	// is used whenever an LLM is configured.
+	TranslatorProvider string
+
+	// Evidence storage. Files are kept under EvidenceStoragePath and
+	// uploads larger than EvidenceMaxSize bytes are rejected.
+	EvidenceStoragePath string
+	EvidenceMaxSize     int64
+
+	// Speech-to-text settings. TranscriberProvider is one of "whisper",
+	// "local" or "fake"; when empty it is derived from whether
+	// TranscriberBaseURL or OpenAIAPIKey is set.
+	TranscriberProvider   string
+	TranscriberBaseURL    string
+	TranscriberModel      string
+	TranscriberTimeout    time.Duration
+	TranscribeMaxSize     int64
+	TranscribeMaxDuration time.Duration
+
+	// Speaker diarization. DiarizerProvider is one of "http", "fake" or
+	// "none"; when empty it is derived from whether DiarizerURL is set and
+	// which transcriber is in use.
+	DiarizerProvider string
+	DiarizerURL      string
+
+	// Background jobs. JobWorkers is the number of jobs run at once, and
+	// recordings queued for transcription wait under AudioStoragePath.
+	JobWorkers       int
+	AudioStoragePath string
+
+	// Printed FIRs. Fonts are read from PDFFontDir, and the QR code on a
+	// printed copy links to PublicURL.
+	PDFFontDir string
+	PublicURL  string
 }
 
 func Load() *Config {
 	return &Config{
-		Port:          getEnv("PORT", "5000"),
-		MongoURI:      getEnv("MONGODB_URI", "mongodb://localhost:27017/legalassist-ai"),
-		JWTSecret:     getEnv("JWT_SECRET", "fallback-secret-key"),
-		OpenAIAPIKey:  getEnv("OPENAI_API_KEY", ""),
-		SpeechAPIKey:  getEnv("GOOGLE_SPEECH_API_KEY", ""),
-		EncryptionKey: getEnv("ENCRYPTION_KEY", ""),
-		CORSOrigin:    getEnv("CORS_ORIGIN", "http://localhost:3000"),
-		AppEnv:        getEnv("APP_ENV", "development"),
+		Port:              getEnv("PORT", "5000"),
+		MongoURI:          getEnv("MONGODB_URI", "mongodb://localhost:27017/legalassist-ai"),
+		JWTSecret:         getEnv("JWT_SECRET", "fallback-secret-key"),
+		OpenAIAPIKey:      getEnv("OPENAI_API_KEY", ""),
+		SpeechAPIKey:      getEnv("GOOGLE_SPEECH_API_KEY", ""),
+		EncryptionKey:     getEnv("ENCRYPTION_KEY", ""),
+		EncryptionKeyID:   getEnv("ENCRYPTION_KEY_ID", "k1"),
+		EncryptionOldKeys: getEnv("ENCRYPTION_OLD_KEYS", ""),
+		CORSOrigin:        getEnv("CORS_ORIGIN", "http://localhost:3000"),
+		AppEnv:            getEnv("APP_ENV", "development"),
+		LLMProvider:       getEnv("LLM_PROVIDER", ""),
+		LLMBaseURL:        getEnv("LLM_BASE_URL", ""),
+		LLMModel:          getEnv("LLM_MODEL", ""),
+		LLMEmbeddingModel: getEnv("LLM_EMBEDDING_MODEL", ""),
+		LLMTimeout:        getEnvDuration("LLM_TIMEOUT", 30*time.Second),
+
+		TranslatorProvider: getEnv("TRANSLATOR_PROVIDER", ""),
+
+		EvidenceStoragePath: getEnv("EVIDENCE_STORAGE_PATH", "./data/evidence"),
+		EvidenceMaxSize:     getEnvInt64("EVIDENCE_MAX_SIZE_MB", 512) << 20,
+
+		TranscriberProvider:   getEnv("TRANSCRIBER_PROVIDER", ""),
+		TranscriberBaseURL:    getEnv("TRANSCRIBER_BASE_URL", ""),
+		TranscriberModel:      getEnv("TRANSCRIBER_MODEL", ""),
+		TranscriberTimeout:    getEnvDuration("TRANSCRIBER_TIMEOUT", 2*time.Minute),
+		TranscribeMaxSize:     getEnvInt64("TRANSCRIBE_MAX_SIZE_MB", 25) << 20,
+		TranscribeMaxDuration: getEnvDuration("TRANSCRIBE_MAX_DURATION", 15*time.Minute),
+
+		DiarizerProvider: getEnv("DIARIZER_PROVIDER", ""),
+		DiarizerURL:      getEnv("DIARIZER_URL", ""),
+
+		JobWorkers:       int(getEnvInt64("JOB_WORKERS", 4)),
+		AudioStoragePath: getEnv("AUDIO_STORAGE_PATH", "./data/audio"),
+
+		PDFFontDir: getEnv("PDF_FONT_DIR", "./fonts"),
+		PublicURL:  getEnv("PUBLIC_URL", "http://localhost:5000"),
 	}
 }
 
@@ -33,4 +113,22 @@ func getEnv(key, fallback string) string {
 		return value
 	}
 	return fallback
-}
\ No newline at end of file
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn in a transaction, retrying it when MongoDB
+// reports a transient error such as a write conflict with a concurrent
+// transaction. Writes made with the context passed to fn are committed
+// together or not at all. Transactions need MongoDB to run as a replica
+// set; a single-node replica set is enough.
+func WithTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
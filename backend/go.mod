@@ -6,11 +6,15 @@ require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/gin-contrib/cors v1.4.0
 	github.com/gin-gonic/gin v1.9.1
+	github.com/go-text/typesetting v0.2.1
 	github.com/joho/godotenv v1.4.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/sashabaranov/go-openai v1.17.9
 	go.mongodb.org/mongo-driver v1.12.1
 	golang.org/x/crypto v0.17.0
+	golang.org/x/image v0.3.0
 	github.com/google/uuid v1.4.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 )
 
 require (
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/services"
 
 	"github.com/gin-gonic/gin"
@@ -12,9 +13,9 @@ type DashboardHandler struct {
 	firService *services.FIRService
 }
 
-func NewDashboardHandler() *DashboardHandler {
+func NewDashboardHandler(cfg *config.Config) *DashboardHandler {
 	return &DashboardHandler{
-		firService: services.NewFIRService(),
+		firService: services.NewFIRService(cfg),
 	}
 }
 
@@ -33,11 +34,11 @@ func (h *DashboardHandler) GetStats(c *gin.Context) {
 func (h *DashboardHandler) GetRecentCases(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
-	firs, _, err := h.firService.GetFIRs(userID.(string), 1, 5)
+	firs, err := h.firService.GetFIRs(userID.(string), 1, 5)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, firs)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, firs.Data)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+	documentService *services.DocumentService
+	firService      *services.FIRService
+}
+
+func NewDocumentHandler(cfg *config.Config) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: services.NewDocumentService(),
+		firService:      services.NewFIRService(cfg),
+	}
+}
+
+// ListTemplates lists stored template versions, optionally filtered by
+// ?kind and ?state.
+func (h *DocumentHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.documentService.ListTemplates(c.Request.Context(), c.Query("kind"), c.Query("state"))
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  templates,
+		"total": len(templates),
+	})
+}
+
+func (h *DocumentHandler) GetTemplate(c *gin.Context) {
+	tmpl, err := h.documentService.GetTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// CreateTemplate saves a new version of a template. The template must
+// render against a sample FIR.
+func (h *DocumentHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	tmpl, err := h.documentService.CreateTemplate(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tmpl)
+}
+
+func (h *DocumentHandler) PreviewTemplate(c *gin.Context) {
+	var req models.PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	document, err := h.firService.PreviewTemplate(c.Request.Context(), req)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+func writeTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound), errors.Is(err, services.ErrFIRNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		writeAdminError(c, err)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead allows for the form fields and boundaries around an
+// evidence file of the maximum size.
+const multipartOverhead = 1 << 20
+
+type EvidenceHandler struct {
+	evidenceService *services.EvidenceService
+}
+
+func NewEvidenceHandler(cfg *config.Config) *EvidenceHandler {
+	return &EvidenceHandler{
+		evidenceService: services.NewEvidenceService(cfg),
+	}
+}
+
+func (h *EvidenceHandler) UploadEvidence(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.evidenceService.MaxSize()+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeEvidenceError(c, services.ErrEvidenceTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Evidence file required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	evidence, err := h.evidenceService.Upload(c.Request.Context(), c.Param("id"), custodyActor(c), services.EvidenceUpload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Kind:        c.PostForm("kind"),
+		Description: c.PostForm("description"),
+	}, file)
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, evidence)
+}
+
+func (h *EvidenceHandler) StartUpload(c *gin.Context) {
+	var req models.StartEvidenceUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	evidence, err := h.evidenceService.StartUpload(c.Request.Context(), c.Param("id"), custodyActor(c), req)
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.Header("Upload-Offset", "0")
+	c.JSON(http.StatusCreated, evidence)
+}
+
+func (h *EvidenceHandler) UploadChunk(c *gin.Context) {
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header must be a non-negative byte offset"})
+		return
+	}
+
+	evidence, err := h.evidenceService.AppendChunk(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c), offset, c.Request.Body)
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(evidence.Offset, 10))
+	c.JSON(http.StatusOK, evidence)
+}
+
+func (h *EvidenceHandler) ListEvidence(c *gin.Context) {
+	evidence, err := h.evidenceService.ListEvidence(c.Request.Context(), c.Param("id"), custodyActor(c))
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  evidence,
+		"total": len(evidence),
+	})
+}
+
+func (h *EvidenceHandler) GetEvidence(c *gin.Context) {
+	evidence, err := h.evidenceService.GetEvidence(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c))
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(evidence.Offset, 10))
+	c.JSON(http.StatusOK, evidence)
+}
+
+func (h *EvidenceHandler) DownloadEvidence(c *gin.Context) {
+	evidence, file, err := h.evidenceService.Download(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c))
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+	defer file.Close()
+
+	sum, _ := hex.DecodeString(evidence.SHA256)
+	c.DataFromReader(http.StatusOK, evidence.Size, evidence.ContentType, file, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", evidence.FileName),
+		"Digest":              "sha-256=" + base64.StdEncoding.EncodeToString(sum),
+		"X-Content-SHA256":    evidence.SHA256,
+	})
+}
+
+func (h *EvidenceHandler) VerifyEvidence(c *gin.Context) {
+	verification, err := h.evidenceService.VerifyEvidence(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c))
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+func (h *EvidenceHandler) TransferEvidence(c *gin.Context) {
+	var req models.TransferEvidenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	evidence, err := h.evidenceService.TransferEvidence(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c), req)
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, evidence)
+}
+
+func (h *EvidenceHandler) GetCustodyLog(c *gin.Context) {
+	events, err := h.evidenceService.CustodyLog(c.Request.Context(), c.Param("id"), c.Param("evidenceId"), custodyActor(c))
+	if err != nil {
+		writeEvidenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  events,
+		"total": len(events),
+	})
+}
+
+func custodyActor(c *gin.Context) services.CustodyActor {
+	userID, _ := c.Get("user_id")
+	return services.CustodyActor{
+		OfficerID: userID.(string),
+		ClientIP:  c.ClientIP(),
+	}
+}
+
+// writeEvidenceError maps evidence service errors to HTTP responses,
+// falling back to writeFIRError for errors about the FIR itself.
+func writeEvidenceError(c *gin.Context, err error) {
+	var offsetErr *services.OffsetMismatchError
+	var integrityErr *services.IntegrityError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &offsetErr):
+		c.Header("Upload-Offset", strconv.FormatInt(offsetErr.Offset, 10))
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "offset": offsetErr.Offset})
+	case errors.As(err, &integrityErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    err.Error(),
+			"expected": integrityErr.Expected,
+			"actual":   integrityErr.Actual,
+		})
+	case errors.Is(err, services.ErrEvidenceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEvidenceTooLarge), errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrEvidenceTooLarge.Error()})
+	case errors.Is(err, services.ErrUploadComplete), errors.Is(err, services.ErrUploadIncomplete):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrChecksumMismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		writeFIRError(c, err)
+	}
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/models"
 	"legalassist-ai-backend/services"
 
@@ -11,12 +15,14 @@ import (
 )
 
 type FIRHandler struct {
-	firService *services.FIRService
+	firService           *services.FIRService
+	transcriptionService *services.TranscriptionService
 }
 
-func NewFIRHandler() *FIRHandler {
+func NewFIRHandler(cfg *config.Config) *FIRHandler {
 	return &FIRHandler{
-		firService: services.NewFIRService(),
+		firService:           services.NewFIRService(cfg),
+		transcriptionService: services.NewTranscriptionService(cfg),
 	}
 }
 
@@ -28,7 +34,7 @@ func (h *FIRHandler) CreateFIR(c *gin.Context) {
 	}
 
 	userID, _ := c.Get("user_id")
-	fir, err := h.firService.CreateFIR(req, userID.(string))
+	fir, err := h.firService.CreateFIR(c.Request.Context(), req, userID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -37,22 +43,33 @@ func (h *FIRHandler) CreateFIR(c *gin.Context) {
 	c.JSON(http.StatusCreated, fir)
 }
 
+// GetFIRs lists the FIRs in the user's jurisdiction. With
+// complainant_phone or complainant_name only FIRs for that complainant are
+// listed.
 func (h *FIRHandler) GetFIRs(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, limit := paginationParams(c)
+	phone := c.Query("complainant_phone")
+	name := c.Query("complainant_name")
 
 	userID, _ := c.Get("user_id")
-	firs, total, err := h.firService.GetFIRs(userID.(string), page, limit)
+	var firs *services.FIRList
+	var err error
+	if phone != "" || name != "" {
+		firs, err = h.firService.FindFIRsByComplainant(userID.(string), phone, name, page, limit)
+	} else {
+		firs, err = h.firService.GetFIRs(userID.(string), page, limit)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeFIRError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  firs,
-		"total": total,
+		"data":  firs.Data,
+		"total": firs.Total,
 		"page":  page,
 		"limit": limit,
+		"scope": firs.Scope,
 	})
 }
 
@@ -62,13 +79,92 @@ func (h *FIRHandler) GetFIRByID(c *gin.Context) {
 
 	fir, err := h.firService.GetFIRByID(firID, userID.(string))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "FIR not found"})
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fir)
+}
+
+func (h *FIRHandler) UpdateFIR(c *gin.Context) {
+	var req models.UpdateFIRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	fir, err := h.firService.UpdateFIR(c.Request.Context(), c.Param("id"), userID.(string), req)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fir)
+}
+
+func (h *FIRHandler) DeleteFIR(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.firService.DeleteFIR(c.Request.Context(), c.Param("id"), userID.(string), c.Query("reason")); err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "FIR deleted successfully"})
+}
+
+func (h *FIRHandler) ChangeStatus(c *gin.Context) {
+	var req models.ChangeFIRStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	fir, err := h.firService.ChangeStatus(c.Request.Context(), c.Param("id"), userID.(string), req.Status, req.Reason)
+	if err != nil {
+		writeFIRError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, fir)
 }
 
+func (h *FIRHandler) AddAmendment(c *gin.Context) {
+	var req models.AmendFIRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	amendment, err := h.firService.AddAmendment(c.Request.Context(), c.Param("id"), userID.(string), req)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, amendment)
+}
+
+func (h *FIRHandler) GetAmendments(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	fir, err := h.firService.GetFIRByID(c.Param("id"), userID.(string))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	amendments := fir.Amendments
+	if amendments == nil {
+		amendments = []models.FIRAmendment{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  amendments,
+		"total": len(amendments),
+	})
+}
+
 func (h *FIRHandler) GenerateFIR(c *gin.Context) {
 	var req models.GenerateFIRRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -76,24 +172,98 @@ func (h *FIRHandler) GenerateFIR(c *gin.Context) {
 		return
 	}
 
-	generatedFIR, err := h.firService.GenerateFIR(req)
+	userID, _ := c.Get("user_id")
+	generatedFIR, err := h.firService.GenerateFIR(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, generatedFIR)
+}
+
+// GetDocument renders a saved FIR as a document. With ?bilingual=true the
+// document is also translated into ?language, or into the FIR's language.
+func (h *FIRHandler) GetDocument(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	bilingual, _ := strconv.ParseBool(c.Query("bilingual"))
+
+	document, err := h.firService.Document(c.Request.Context(), c.Param("id"), userID.(string), bilingual, c.Query("language"))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// GetSignatures verifies the registering officer's signature and any
+// countersignatures on a submitted FIR.
+func (h *FIRHandler) GetSignatures(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	verification, err := h.firService.VerifySignatures(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// Countersign adds the calling supervisor's signature to a submitted FIR.
+func (h *FIRHandler) Countersign(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	signature, err := h.firService.Countersign(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, signature)
+}
+
+// GetPDF prints a registered FIR for the complainant, with the same
+// ?bilingual and ?language options as GetDocument.
+func (h *FIRHandler) GetPDF(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	bilingual, _ := strconv.ParseBool(c.Query("bilingual"))
+
+	pdf, err := h.firService.PDF(c.Request.Context(), c.Param("id"), userID.(string), bilingual, c.Query("language"))
 	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdf.FileName))
+	c.Data(http.StatusOK, "application/pdf", pdf.Data)
+}
+
+// VerifyCopy checks the verification code printed on an FIR. It is public
+// so that anyone holding a printed copy can check it.
+func (h *FIRHandler) VerifyCopy(c *gin.Context) {
+	verification, err := h.firService.VerifyCopy(c.Request.Context(), c.Param("code"))
+	switch {
+	case errors.Is(err, services.ErrInvalidVerificationCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrFIRNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "No FIR is registered with this number"})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"generated_fir": generatedFIR,
-	})
+	c.JSON(http.StatusOK, verification)
 }
 
 func (h *FIRHandler) SubmitFIR(c *gin.Context) {
 	firID := c.Param("id")
 	userID, _ := c.Get("user_id")
 
-	err := h.firService.SubmitFIR(firID, userID.(string))
+	err := h.firService.SubmitFIR(c.Request.Context(), firID, userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeFIRError(c, err)
 		return
 	}
 
@@ -101,14 +271,223 @@ func (h *FIRHandler) SubmitFIR(c *gin.Context) {
 }
 
 func (h *FIRHandler) TranscribeAudio(c *gin.Context) {
-	file, err := c.FormFile("audio")
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.transcriptionService.MaxSize()+multipartOverhead)
+
+	header, err := c.FormFile("audio")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrAudioTooLarge.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file required"})
 		return
 	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	userID, _ := c.Get("user_id")
+	upload := services.AudioUpload{
+		Audio:    file,
+		Language: c.PostForm("language"),
+		FIRID:    c.PostForm("fir_id"),
+	}
+
+	// Long recordings can be queued and polled for at /jobs/:id.
+	if async, _ := strconv.ParseBool(c.Query("async")); async {
+		job, err := h.transcriptionService.TranscribeAsync(c.Request.Context(), userID.(string), upload)
+		if err != nil {
+			writeTranscriptionError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"job_id": job.ID,
+			"status": job.Status,
+		})
+		return
+	}
+
+	transcript, err := h.transcriptionService.Transcribe(c.Request.Context(), userID.(string), upload)
+	if err != nil {
+		writeTranscriptionError(c, err)
+		return
+	}
 
-	// Mock transcription response
 	c.JSON(http.StatusOK, gin.H{
-		"transcription": "This is a mock transcription of the recorded audio. In a real application, this would be processed by your speech-to-text service.",
+		"transcription": transcript.Text,
+		"language":      transcript.Language,
+		"duration":      transcript.Duration,
+		"segments":      transcript.Segments,
+		"turns":         transcript.Turns,
+		"speakers":      transcript.Speakers,
+		"provider":      transcript.Provider,
+		"model":         transcript.Model,
 	})
-}
\ No newline at end of file
+}
+
+// MapStatement previews how a transcribed statement's turns map into FIR
+// fields without changing any FIR.
+func (h *FIRHandler) MapStatement(c *gin.Context) {
+	var req models.MapStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mapping, err := services.MapStatement(req)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+func (h *FIRHandler) ApplyStatement(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.MapStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fir, err := h.firService.ApplyStatement(c.Request.Context(), c.Param("id"), userID.(string), req)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fir)
+}
+
+// writeTranscriptionError maps transcription errors to HTTP responses.
+// Failures of the speech backend are reported as 502.
+func writeTranscriptionError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.Is(err, services.ErrAudioTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedAudioFormat):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAudioTooLong), errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrFIRNotFound):
+		writeFIRError(c, err)
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Transcription failed: " + err.Error()})
+	}
+}
+
+func (h *FIRHandler) GetRevisions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	history, err := h.firService.ListRevisions(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *FIRHandler) DiffRevisions(c *gin.Context) {
+	from, fromErr := strconv.Atoi(c.Query("from"))
+	to, toErr := strconv.Atoi(c.Query("to"))
+	if fromErr != nil || toErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be revision numbers"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	diff, err := h.firService.DiffRevisions(c.Request.Context(), c.Param("id"), userID.(string), from, to)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+func (h *FIRHandler) GetFIRAsOf(c *gin.Context) {
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at must be an RFC 3339 timestamp"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	revision, err := h.firService.FIRAsOf(c.Request.Context(), c.Param("id"), userID.(string), at)
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+func (h *FIRHandler) VerifyFIR(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	verification, err := h.firService.VerifyFIR(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		writeFIRError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+func (h *FIRHandler) VerifyLedger(c *gin.Context) {
+	reports, err := h.firService.VerifyLedger(c.Request.Context(), c.Query("station"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	valid := true
+	for _, report := range reports {
+		valid = valid && report.Valid
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reports,
+		"valid": valid,
+	})
+}
+
+// writeFIRError maps FIR service errors to HTTP responses.
+func writeFIRError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	var transitionErr *services.InvalidTransitionError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErr.Problems})
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   err.Error(),
+			"from":    transitionErr.From,
+			"to":      transitionErr.To,
+			"allowed": transitionErr.Allowed,
+		})
+	case errors.Is(err, services.ErrFIRNotFound), errors.Is(err, services.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrFIRNotDraft), errors.Is(err, services.ErrFIRNotAmendable), errors.Is(err, services.ErrFIRNotSubmitted),
+		errors.Is(err, services.ErrAlreadyCountersigned), errors.Is(err, services.ErrSignatureInvalid),
+		errors.Is(err, services.ErrAnalysisPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOwnFIRCountersign), errors.Is(err, services.ErrNotCountersigner),
+		errors.Is(err, services.ErrNotRegisteringOfficer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPDFScriptUnsupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"hint":  "Print the document from /document instead, or request the PDF without a translation",
+		})
+	case errors.Is(err, services.ErrKeystoreNotConfigured), errors.Is(err, services.ErrEncryptionNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
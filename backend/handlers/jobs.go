@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"legalassist-ai-backend/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	queue *jobs.Queue
+}
+
+func NewJobHandler() *JobHandler {
+	return &JobHandler{
+		queue: jobs.NewQueue(),
+	}
+}
+
+// GetJob reports the status of a background job and, once it has
+// succeeded, its result. Officers can only see their own jobs.
+func (h *JobHandler) GetJob(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	job, err := h.queue.Get(c.Request.Context(), c.Param("id"))
+	if err == nil && job.OwnerID.Hex() != userID.(string) {
+		err = jobs.ErrNotFound
+	}
+	if errors.Is(err, jobs.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
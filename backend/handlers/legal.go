@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"legalassist-ai-backend/services"
 
@@ -20,13 +22,25 @@ func NewLegalHandler() *LegalHandler {
 }
 
 func (h *LegalHandler) SearchLaws(c *gin.Context) {
-	query := c.Query("q")
-	category := c.Query("category")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	query := services.SearchQuery{
+		Query:    c.Query("q"),
+		Category: c.Query("category"),
+		Act:      c.Query("act"),
+		Court:    c.Query("court"),
+		Cursor:   c.Query("cursor"),
+		Limit:    limit,
+	}
+	if types := c.Query("type"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
 
-	results, err := h.legalService.SearchLaws(query, category, page, limit)
+	results, err := h.legalService.Search(c.Request.Context(), query)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -36,27 +50,96 @@ func (h *LegalHandler) SearchLaws(c *gin.Context) {
 
 func (h *LegalHandler) GetSections(c *gin.Context) {
 	act := c.Param("act")
-	
-	// Mock response for now
-	c.JSON(http.StatusOK, gin.H{
-		"act":      act,
-		"sections": []string{"354", "420", "498A"},
-	})
+	page, limit := paginationParams(c)
+	sort := c.Query("sort")
+
+	sections, total, err := h.legalService.ListSections(act, sort, page, limit)
+	if err != nil {
+		h.listError(c, err)
+		return
+	}
+
+	response := paginatedResponse(sections, total, page, limit, sort)
+	response["act"] = act
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LegalHandler) GetSectionGraph(c *gin.Context) {
+	// The route shares its wildcard with /sections/:act, so the section ID
+	// arrives as "act".
+	graph, err := h.legalService.SectionGraph(c.Request.Context(), c.Param("act"))
+	if err != nil {
+		if errors.Is(err, services.ErrLegalRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
 }
 
 func (h *LegalHandler) GetCaseLaws(c *gin.Context) {
 	section := c.Param("section")
-	
-	// Mock response for now
-	c.JSON(http.StatusOK, gin.H{
-		"section":   section,
-		"case_laws": []string{},
-	})
+	page, limit := paginationParams(c)
+	sort := c.Query("sort")
+
+	caseLaws, total, err := h.legalService.ListCaseLaws(section, sort, page, limit)
+	if err != nil {
+		h.listError(c, err)
+		return
+	}
+
+	response := paginatedResponse(caseLaws, total, page, limit, sort)
+	response["section"] = section
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *LegalHandler) GetLandmarkJudgments(c *gin.Context) {
-	// Mock response for now
+	page, limit := paginationParams(c)
+	sort := c.Query("sort")
+
+	judgments, total, err := h.legalService.ListLandmarkJudgments(sort, page, limit)
+	if err != nil {
+		h.listError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse(judgments, total, page, limit, sort))
+}
+
+func (h *LegalHandler) listError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func (h *LegalHandler) MapSection(c *gin.Context) {
+	act := c.Query("act")
+	section := c.Query("section")
+	if act == "" || section == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "act and section are required"})
+		return
+	}
+
+	mappings, err := h.legalService.MapSection(act, section)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownAct) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	canonical, _ := services.NormalizeAct(act)
 	c.JSON(http.StatusOK, gin.H{
-		"judgments": []string{},
+		"act":      canonical,
+		"section":  section,
+		"mappings": mappings,
+		"version":  h.legalService.SectionMappingVersion(),
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxImportSize caps the size of a bulk import upload.
+const maxImportSize = 32 << 20
+
+type LegalAdminHandler struct {
+	legalService *services.LegalService
+	aiService    *services.AIService
+}
+
+func NewLegalAdminHandler(cfg *config.Config) *LegalAdminHandler {
+	return &LegalAdminHandler{
+		legalService: services.NewLegalService(),
+		aiService:    services.NewAIService(cfg),
+	}
+}
+
+func (h *LegalAdminHandler) CreateSection(c *gin.Context) {
+	var section models.LegalSection
+	if err := c.ShouldBindJSON(&section); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.legalService.CreateSection(section)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *LegalAdminHandler) UpdateSection(c *gin.Context) {
+	var section models.LegalSection
+	if err := c.ShouldBindJSON(&section); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.legalService.UpdateSection(c.Param("id"), section)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *LegalAdminHandler) DeleteSection(c *gin.Context) {
+	if err := h.legalService.DeleteSection(c.Param("id")); err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Section deleted successfully"})
+}
+
+func (h *LegalAdminHandler) CreateCaseLaw(c *gin.Context) {
+	var caseLaw models.CaseLawRecord
+	if err := c.ShouldBindJSON(&caseLaw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.legalService.CreateCaseLaw(caseLaw)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *LegalAdminHandler) UpdateCaseLaw(c *gin.Context) {
+	var caseLaw models.CaseLawRecord
+	if err := c.ShouldBindJSON(&caseLaw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.legalService.UpdateCaseLaw(c.Param("id"), caseLaw)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *LegalAdminHandler) DeleteCaseLaw(c *gin.Context) {
+	if err := h.legalService.DeleteCaseLaw(c.Param("id")); err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Case law deleted successfully"})
+}
+
+func (h *LegalAdminHandler) CreateJudgment(c *gin.Context) {
+	var judgment models.LandmarkJudgment
+	if err := c.ShouldBindJSON(&judgment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.legalService.CreateJudgment(judgment)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *LegalAdminHandler) UpdateJudgment(c *gin.Context) {
+	var judgment models.LandmarkJudgment
+	if err := c.ShouldBindJSON(&judgment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.legalService.UpdateJudgment(c.Param("id"), judgment)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *LegalAdminHandler) DeleteJudgment(c *gin.Context) {
+	if err := h.legalService.DeleteJudgment(c.Param("id")); err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Judgment deleted successfully"})
+}
+
+// Import accepts a multipart "file" upload. The format is taken from the
+// "format" query parameter or the file extension; "dry_run=true" validates
+// and reports without writing.
+func (h *LegalAdminHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Import file required"})
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = services.ImportFormatFromFilename(fileHeader.Filename)
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.legalService.Import(c.Request.Context(), c.Param("kind"), format, file, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownImportKind) || errors.Is(err, services.ErrUnknownImportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Embed the new and changed sections in the background so retrieval
+	// can use them without waiting for a restart
+	if !dryRun && result.Reembed > 0 {
+		go h.indexEmbeddings()
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *LegalAdminHandler) indexEmbeddings() {
+	indexed, err := h.aiService.IndexEmbeddings(context.Background())
+	if err != nil {
+		log.Println("Failed to index legal section embeddings:", err)
+	} else if indexed > 0 {
+		log.Printf("Indexed embeddings for %d legal sections", indexed)
+	}
+}
+
+func (h *LegalAdminHandler) DanglingReferences(c *gin.Context) {
+	dangling, err := h.legalService.DanglingReferences(c.Request.Context())
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dangling, "total": len(dangling)})
+}
+
+func writeAdminError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErr.Problems})
+	case errors.Is(err, services.ErrLegalRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case mongo.IsDuplicateKeyError(err):
+		c.JSON(http.StatusConflict, gin.H{"error": "A record with the same key already exists"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"strconv"
+
+	"legalassist-ai-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationParams reads page and limit from the query string, clamped the
+// same way the services clamp them.
+func paginationParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	offset, limit := utils.Paginate(page, limit)
+	return offset/limit + 1, limit
+}
+
+// paginatedResponse is the envelope shared by list endpoints.
+func paginatedResponse(data interface{}, total int64, page, limit int, sort string) gin.H {
+	return gin.H{
+		"data":  data,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+		"sort":  sort,
+	}
+}
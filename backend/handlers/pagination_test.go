@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaginationParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query     string
+		wantPage  int
+		wantLimit int
+	}{
+		{"", 1, 10},
+		{"?page=3&limit=20", 3, 20},
+		{"?page=0", 1, 10},
+		{"?page=-2&limit=5", 1, 5},
+		{"?limit=0", 1, 10},
+		{"?limit=-1", 1, 10},
+		{"?limit=5000", 1, 100},
+		{"?page=abc&limit=xyz", 1, 10},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/fir"+tt.query, nil)
+		if page, limit := paginationParams(c); page != tt.wantPage || limit != tt.wantLimit {
+			t.Errorf("paginationParams(%q) = %d, %d, want %d, %d", tt.query, page, limit, tt.wantPage, tt.wantLimit)
+		}
+	}
+}
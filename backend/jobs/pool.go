@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultLease        = 15 * time.Minute
+)
+
+// Handler runs one job. The returned result is stored on the job as JSON.
+// Returning an error schedules a retry unless it is wrapped with Permanent
+// or the job is on its last attempt.
+type Handler func(ctx context.Context, job *Job) (interface{}, error)
+
+// Pool runs registered handlers on a fixed number of workers, each polling
+// the queue for due jobs.
+type Pool struct {
+	queue    *Queue
+	workers  int
+	handlers map[string]Handler
+	poll     time.Duration
+	lease    time.Duration
+	wg       sync.WaitGroup
+}
+
+// NewPool creates a pool of workers taking jobs from queue.
+func NewPool(queue *Queue, workers int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pool{
+		queue:    queue,
+		workers:  workers,
+		handlers: make(map[string]Handler),
+		poll:     defaultPollInterval,
+		lease:    defaultLease,
+	}
+}
+
+// Register sets the handler for jobType. Handlers must be registered before
+// Start.
+func (p *Pool) Register(jobType string, handler Handler) {
+	p.handlers[jobType] = handler
+}
+
+// Start launches the workers. They stop once ctx is cancelled; Wait blocks
+// until they have finished their current jobs.
+func (p *Pool) Start(ctx context.Context) {
+	types := make([]string, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+	if len(types) == 0 {
+		return
+	}
+
+	host, _ := os.Hostname()
+	for i := 0; i < p.workers; i++ {
+		workerID := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), i)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(ctx, workerID, types)
+		}()
+	}
+}
+
+// Wait blocks until every worker has stopped.
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pool) work(ctx context.Context, workerID string, types []string) {
+	for {
+		job, err := p.queue.claim(ctx, types, workerID, p.lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Job worker %s failed to claim a job: %v", workerID, err)
+		}
+		if job == nil {
+			p.failAbandoned(ctx, workerID, types)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.poll):
+			}
+			continue
+		}
+
+		p.run(ctx, job)
+	}
+}
+
+// failAbandoned fails jobs left running on their last attempt by a worker
+// that died. Idle workers do this between polls.
+func (p *Pool) failAbandoned(ctx context.Context, workerID string, types []string) {
+	failed, err := p.queue.failAbandoned(ctx, types)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Job worker %s failed to check for abandoned jobs: %v", workerID, err)
+	}
+	if failed > 0 {
+		log.Printf("Job worker %s failed %d jobs abandoned on their last attempt", workerID, failed)
+	}
+}
+
+// run executes a claimed job within its lease and records the outcome. The
+// outcome is recorded even if the pool is shutting down, so the job is not
+// left running until its lease expires.
+func (p *Pool) run(ctx context.Context, job *Job) {
+	runCtx, cancel := context.WithTimeout(ctx, p.lease)
+	result, err := p.runHandler(runCtx, job)
+	cancel()
+
+	recordCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err != nil {
+		log.Printf("Job %s (%s) attempt %d/%d failed: %v", job.ID.Hex(), job.Type, job.Attempts, job.MaxAttempts, err)
+		err = p.queue.fail(recordCtx, job, err)
+	} else {
+		err = p.queue.complete(recordCtx, job, result)
+	}
+	if err != nil {
+		log.Printf("Failed to record outcome of job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// runHandler calls the job's handler, turning a panic into a permanent
+// failure so one bad job cannot take down the worker.
+func (p *Pool) runHandler(ctx context.Context, job *Job) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("job panicked: %v", r))
+		}
+	}()
+	return p.handlers[job.Type](ctx, job)
+}
@@ -0,0 +1,275 @@
+// Package jobs runs slow work, such as transcription and LLM analysis,
+// outside the request that asked for it. Jobs are stored in MongoDB so they
+// survive restarts and can be claimed by any worker process.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"legalassist-ai-backend/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is returned when a job does not exist.
+var ErrNotFound = errors.New("job not found")
+
+// Job statuses. A job is queued until a worker claims it, running while the
+// worker holds its lease, and then succeeded, failed, or queued again for a
+// retry.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const (
+	defaultMaxAttempts = 5
+	baseBackoff        = 5 * time.Second
+	maxBackoff         = 10 * time.Minute
+)
+
+// Job is a unit of background work. Payload and Result are JSON so they are
+// returned to API clients exactly as the job type defines them.
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	OwnerID     primitive.ObjectID `bson:"owner_id" json:"owner_id"`
+	Status      string             `bson:"status" json:"status"`
+	Payload     json.RawMessage    `bson:"payload" json:"-"`
+	Result      json.RawMessage    `bson:"result,omitempty" json:"result,omitempty"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	MaxAttempts int                `bson:"max_attempts" json:"max_attempts"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	RunAt       time.Time          `bson:"run_at" json:"run_at"`
+	LockedUntil *time.Time         `bson:"locked_until,omitempty" json:"-"`
+	WorkerID    string             `bson:"worker_id,omitempty" json:"-"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	StartedAt   *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt  *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// Decode unmarshals the job's payload into v.
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// LastAttempt reports whether a failure of the current run will be final.
+func (j *Job) LastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails at once instead of being retried.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Queue stores jobs in a MongoDB collection.
+type Queue struct {
+	collection string
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		collection: "jobs",
+	}
+}
+
+// EnsureIndexes creates the index workers use to find due jobs.
+func (q *Queue) EnsureIndexes(ctx context.Context) error {
+	_, err := database.GetCollection(q.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_at", Value: 1}},
+	})
+	return err
+}
+
+// Enqueue stores a new job of jobType for ownerID, to run as soon as a
+// worker is free.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, ownerID primitive.ObjectID, payload interface{}) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          primitive.NewObjectID(),
+		Type:        jobType,
+		OwnerID:     ownerID,
+		Status:      StatusQueued,
+		Payload:     data,
+		MaxAttempts: defaultMaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := database.GetCollection(q.collection).InsertOne(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get returns a job by ID.
+func (q *Queue) Get(ctx context.Context, id string) (*Job, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var job Job
+	err = database.GetCollection(q.collection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// claim atomically takes the oldest due job of one of types and leases it
+// to workerID. A running job whose lease has expired belonged to a worker
+// that died, and is claimed again if it has attempts left. It returns nil
+// when no job is due.
+func (q *Queue) claim(ctx context.Context, types []string, workerID string, lease time.Duration) (*Job, error) {
+	now := time.Now()
+	filter := bson.M{
+		"type":   bson.M{"$in": types},
+		"run_at": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"status": StatusQueued},
+			{
+				"status":       StatusRunning,
+				"locked_until": bson.M{"$lt": now},
+				"$expr":        bson.M{"$lt": bson.A{"$attempts", "$max_attempts"}},
+			},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       StatusRunning,
+			"locked_until": now.Add(lease),
+			"worker_id":    workerID,
+			"started_at":   now,
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	var job Job
+	err := database.GetCollection(q.collection).FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "run_at", Value: 1}}).SetReturnDocument(options.After),
+	).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// failAbandoned fails running jobs of one of types whose lease expired on
+// their last attempt. Their worker died mid-run, so no one will record an
+// outcome, and claim will not take them again.
+func (q *Queue) failAbandoned(ctx context.Context, types []string) (int64, error) {
+	now := time.Now()
+	res, err := database.GetCollection(q.collection).UpdateMany(ctx,
+		bson.M{
+			"type":         bson.M{"$in": types},
+			"status":       StatusRunning,
+			"locked_until": bson.M{"$lt": now},
+			"$expr":        bson.M{"$gte": bson.A{"$attempts", "$max_attempts"}},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status":      StatusFailed,
+				"last_error":  "worker stopped before finishing the last attempt",
+				"finished_at": now,
+				"updated_at":  now,
+			},
+			"$unset": bson.M{"locked_until": "", "worker_id": ""},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// complete records a job's result.
+func (q *Queue) complete(ctx context.Context, job *Job, result interface{}) error {
+	set := bson.M{
+		"status":      StatusSucceeded,
+		"finished_at": time.Now(),
+		"updated_at":  time.Now(),
+		"last_error":  "",
+	}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return q.fail(ctx, job, Permanent(err))
+		}
+		set["result"] = json.RawMessage(data)
+	}
+	return q.finish(ctx, job, set)
+}
+
+// fail records a failed run, scheduling a retry with exponential backoff
+// unless the job is out of attempts or the error is permanent.
+func (q *Queue) fail(ctx context.Context, job *Job, runErr error) error {
+	now := time.Now()
+	set := bson.M{
+		"last_error": runErr.Error(),
+		"updated_at": now,
+	}
+
+	var permanent permanentError
+	if job.LastAttempt() || errors.As(runErr, &permanent) {
+		set["status"] = StatusFailed
+		set["finished_at"] = now
+	} else {
+		set["status"] = StatusQueued
+		set["run_at"] = now.Add(backoff(job.Attempts))
+	}
+	return q.finish(ctx, job, set)
+}
+
+// finish applies the outcome of a run if the worker still holds the job,
+// releasing its lease.
+func (q *Queue) finish(ctx context.Context, job *Job, set bson.M) error {
+	_, err := database.GetCollection(q.collection).UpdateOne(ctx,
+		bson.M{"_id": job.ID, "worker_id": job.WorkerID, "status": StatusRunning},
+		bson.M{"$set": set, "$unset": bson.M{"locked_until": "", "worker_id": ""}},
+	)
+	return err
+}
+
+// backoff is the delay before retry number attempt, doubling from
+// baseBackoff up to maxBackoff with up to 20% jitter so failed jobs do not
+// retry in lockstep.
+func backoff(attempt int) time.Duration {
+	delay := float64(baseBackoff) * math.Pow(2, float64(attempt-1))
+	if delay > float64(maxBackoff) {
+		delay = float64(maxBackoff)
+	}
+	return time.Duration(delay * (1 + 0.2*rand.Float64()))
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
 	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/jobs"
 	"legalassist-ai-backend/routes"
+	"legalassist-ai-backend/services"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -26,6 +29,49 @@ func main() {
 	database.InitMongoDB(cfg.MongoURI)
 	defer database.CloseMongoDB()
 
+	if cfg.EncryptionKey == "" {
+		log.Println("ENCRYPTION_KEY is not set; complainant details will be stored unencrypted and FIRs cannot be submitted")
+	}
+
+	if err := services.NewLegalService().EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create legal database indexes:", err)
+	}
+	if err := services.NewFIRService(cfg).EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create FIR indexes:", err)
+	}
+	if err := services.NewKeystore(cfg).EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create keystore indexes:", err)
+	}
+	if err := services.NewDocumentService().EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create document template indexes:", err)
+	}
+	if err := services.NewAuditService().EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create audit log indexes:", err)
+	}
+
+	// Start background workers for AI analysis and long transcriptions
+	queue := jobs.NewQueue()
+	if err := queue.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Failed to create job indexes:", err)
+	}
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	pool := jobs.NewPool(queue, cfg.JobWorkers)
+	services.NewFIRService(cfg).RegisterJobs(pool)
+	services.NewTranscriptionService(cfg).RegisterJobs(pool)
+	pool.Start(workerCtx)
+
+	// Embed legal sections added since the last start so retrieval can use
+	// similarity search
+	go func() {
+		indexed, err := services.NewAIService(cfg).IndexEmbeddings(context.Background())
+		if err != nil {
+			log.Println("Failed to index legal section embeddings:", err)
+		} else if indexed > 0 {
+			log.Printf("Indexed embeddings for %d legal sections", indexed)
+		}
+	}()
+
 	// Initialize Gin router
 	if cfg.AppEnv == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -36,8 +82,9 @@ func main() {
 	// CORS middleware
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{cfg.CORSOrigin}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "Upload-Offset"}
+	corsConfig.ExposeHeaders = []string{"Upload-Offset", "Content-Disposition", "Digest", "X-Content-SHA256"}
 	corsConfig.AllowCredentials = true
 	router.Use(cors.New(corsConfig))
 
@@ -60,4 +107,4 @@ func main() {
 	if err := router.Run(":" + port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
@@ -1,15 +1,27 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"legalassist-ai-backend/policy"
+	"legalassist-ai-backend/services"
 	"legalassist-ai-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// AuthMiddleware authenticates requests by their bearer token. Tokens
+// issued before the user's role or permissions last changed are refused.
 func AuthMiddleware() gin.HandlerFunc {
+	return authenticate(services.NewAuthService().AccessVersion)
+}
+
+// authenticate is AuthMiddleware with the lookup of a user's current
+// access version passed in.
+func authenticate(accessVersion func(ctx context.Context, userID string) (int, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -32,18 +44,41 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		version, err := accessVersion(c.Request.Context(), claims.UserID)
+		if errors.Is(err, services.ErrUserNotFound) || (err == nil && version != claims.AccessVersion) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked, please log in again"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		// Tokens issued before permissions were added to them get the
+		// permissions of their role.
+		permissions := claims.Permissions
+		if permissions == nil {
+			permissions = policy.Permissions(claims.Role, nil)
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
+		c.Set("user_permissions", permissions)
 		c.Next()
 	}
 }
 
-func RequireRole(role string) gin.HandlerFunc {
+// RequirePermission rejects requests from users without permission. It
+// must run after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("user_role")
-		if !exists || userRole != role {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		permissions, _ := c.Get("user_permissions")
+		granted, _ := permissions.([]string)
+		if !policy.Has(granted, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "required": permission})
 			c.Abort()
 			return
 		}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/policy"
+	"legalassist-ai-backend/services"
+	"legalassist-ai-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs a request with the given Authorization header through
+// handlers and returns the response and the permissions the last handler
+// saw.
+func serve(authorization string, handlers ...gin.HandlerFunc) (*httptest.ResponseRecorder, []string) {
+	var seen []string
+	router := gin.New()
+	router.GET("/", append(handlers, func(c *gin.Context) {
+		seen = c.GetStringSlice("user_permissions")
+		c.Status(http.StatusNoContent)
+	})...)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestAuthenticate(t *testing.T) {
+	const userID = "64b7f0c2a1e4d3b2c1a09f8e"
+	token := func(permissions []string, accessVersion int) string {
+		signed, err := utils.GenerateJWT(userID, "asha@example.com", policy.RoleOfficer, permissions, accessVersion)
+		if err != nil {
+			t.Fatalf("GenerateJWT() error = %v", err)
+		}
+		return "Bearer " + signed
+	}
+	versions := func(version int, err error) func(context.Context, string) (int, error) {
+		return func(ctx context.Context, id string) (int, error) {
+			if id != userID {
+				return 0, services.ErrUserNotFound
+			}
+			return version, err
+		}
+	}
+
+	tests := []struct {
+		name            string
+		authorization   string
+		accessVersion   func(context.Context, string) (int, error)
+		wantStatus      int
+		wantPermissions []string
+	}{
+		{"current token", token([]string{policy.FIRRead}, 2), versions(2, nil), http.StatusNoContent, []string{policy.FIRRead}},
+		{"token from before permissions", token(nil, 0), versions(0, nil), http.StatusNoContent, policy.Permissions(policy.RoleOfficer, nil)},
+		{"access changed since issue", token([]string{policy.FIRRead}, 1), versions(2, nil), http.StatusUnauthorized, nil},
+		{"user removed", token([]string{policy.FIRRead}, 0), versions(0, services.ErrUserNotFound), http.StatusUnauthorized, nil},
+		{"lookup failed", token([]string{policy.FIRRead}, 0), versions(0, errors.New("connection refused")), http.StatusInternalServerError, nil},
+		{"no header", "", versions(0, nil), http.StatusUnauthorized, nil},
+		{"not bearer", "Basic YXNoYTpzZWNyZXQ=", versions(0, nil), http.StatusUnauthorized, nil},
+		{"malformed token", "Bearer not.a.token", versions(0, nil), http.StatusUnauthorized, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, permissions := serve(tt.authorization, authenticate(tt.accessVersion))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if !reflect.DeepEqual(permissions, tt.wantPermissions) {
+				t.Errorf("permissions = %q, want %q", permissions, tt.wantPermissions)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	withPermissions := func(permissions interface{}) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if permissions != nil {
+				c.Set("user_permissions", permissions)
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions interface{}
+		required    string
+		wantStatus  int
+	}{
+		{"granted", []string{policy.FIRRead, policy.FIRSubmit}, policy.FIRSubmit, http.StatusNoContent},
+		{"missing", []string{policy.FIRRead}, policy.FIRSubmit, http.StatusForbidden},
+		{"read scope does not imply another", []string{policy.FIRReadDistrict}, policy.FIRReadState, http.StatusForbidden},
+		{"not authenticated", nil, policy.FIRRead, http.StatusForbidden},
+		{"wrong type", "fir:read", policy.FIRRead, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := serve("", withPermissions(tt.permissions), RequirePermission(tt.required))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Code == http.StatusForbidden {
+				var body map[string]string
+				json.Unmarshal(w.Body.Bytes(), &body)
+				if body["required"] != tt.required {
+					t.Errorf("required = %q, want %q", body["required"], tt.required)
+				}
+			}
+		})
+	}
+}
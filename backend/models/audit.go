@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Access scope levels, from narrowest to widest. An officer sees their own
+// FIRs; supervisors see every FIR in their station, district or state.
+const (
+	ScopeOwn      = "own"
+	ScopeStation  = "station"
+	ScopeDistrict = "district"
+	ScopeState    = "state"
+)
+
+// AccessScope is the jurisdiction a query for FIRs ran under. Only the
+// fields for its level are set.
+type AccessScope struct {
+	Level    string `bson:"level" json:"level"`
+	Station  string `bson:"station,omitempty" json:"station,omitempty"`
+	District string `bson:"district,omitempty" json:"district,omitempty"`
+	State    string `bson:"state,omitempty" json:"state,omitempty"`
+}
+
+// Audited FIR reads.
+const (
+	AuditFIRList          = "fir.list"
+	AuditFIRGet           = "fir.get"
+	AuditFIRLookup        = "fir.lookup"
+	AuditFIRDocument      = "fir.document"
+	AuditFIRPDF           = "fir.pdf"
+	AuditFIRRevisions     = "fir.revisions"
+	AuditFIRVerify        = "fir.verify"
+	AuditFIRSignatures    = "fir.signatures"
+	AuditEvidenceList     = "evidence.list"
+	AuditEvidenceGet      = "evidence.get"
+	AuditEvidenceDownload = "evidence.download"
+	AuditEvidenceCustody  = "evidence.custody"
+	AuditDashboardStats   = "dashboard.stats"
+)
+
+// AuditEntry records one query for FIR data, who made it and the scope it
+// ran under. Results is how many FIRs matched.
+type AuditEntry struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Role      string              `bson:"role" json:"role"`
+	Action    string              `bson:"action" json:"action"`
+	FIRID     *primitive.ObjectID `bson:"fir_id,omitempty" json:"fir_id,omitempty"`
+	Scope     AccessScope         `bson:"scope" json:"scope"`
+	Results   int64               `bson:"results" json:"results"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
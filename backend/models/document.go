@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Document template kinds.
+const (
+	TemplateKindFIR = "fir"
+)
+
+// DocumentTemplate is one version of a text/template used to render a
+// document. State is lower case; templates with no state are the default
+// for states that have no template of their own. Saving a template adds a
+// version, and the latest version for a kind and state is the one used.
+type DocumentTemplate struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Kind      string             `bson:"kind" json:"kind"`
+	State     string             `bson:"state" json:"state"`
+	Version   int                `bson:"version" json:"version"`
+	Body      string             `bson:"body" json:"body"`
+	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// TemplateRef identifies the template a document was rendered from. The
+// built-in template has no ID and version 0.
+type TemplateRef struct {
+	ID      *primitive.ObjectID `json:"id,omitempty"`
+	Kind    string              `json:"kind"`
+	State   string              `json:"state"`
+	Version int                 `json:"version"`
+}
+
+type CreateTemplateRequest struct {
+	Kind  string `json:"kind"`
+	State string `json:"state"`
+	Body  string `json:"body" binding:"required"`
+	Notes string `json:"notes"`
+}
+
+// PreviewTemplateRequest renders a template without saving anything. Body
+// previews an unsaved template, TemplateID a stored version, and otherwise
+// the template in use for State is previewed. The FIR named by FIRID is
+// used as data, or a sample FIR when it is empty.
+type PreviewTemplateRequest struct {
+	Kind       string `json:"kind"`
+	Body       string `json:"body"`
+	TemplateID string `json:"template_id"`
+	State      string `json:"state"`
+	FIRID      string `json:"fir_id"`
+}
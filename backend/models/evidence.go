@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Evidence is a file attached to an FIR. SHA256 is computed when the upload
+// completes and checked whenever the file is read back. Offset counts the
+// bytes received so far for a resumable upload.
+type Evidence struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	FIRID          primitive.ObjectID `bson:"fir_id" json:"fir_id"`
+	Kind           string             `bson:"kind" json:"kind"` // "photo", "video", "document", "audio"
+	FileName       string             `bson:"file_name" json:"file_name"`
+	ContentType    string             `bson:"content_type" json:"content_type"`
+	Description    string             `bson:"description,omitempty" json:"description,omitempty"`
+	Size           int64              `bson:"size" json:"size"`
+	Offset         int64              `bson:"offset" json:"offset"`
+	SHA256         string             `bson:"sha256,omitempty" json:"sha256,omitempty"`
+	ExpectedSHA256 string             `bson:"expected_sha256,omitempty" json:"expected_sha256,omitempty"`
+	StorageKey     string             `bson:"storage_key" json:"-"`
+	Status         string             `bson:"status" json:"status"`
+	Custodian      string             `bson:"custodian" json:"custodian"`
+	UploadedBy     primitive.ObjectID `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+	CompletedAt    *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+const (
+	EvidenceKindPhoto    = "photo"
+	EvidenceKindVideo    = "video"
+	EvidenceKindDocument = "document"
+	EvidenceKindAudio    = "audio"
+)
+
+const (
+	EvidenceStatusUploading = "uploading"
+	EvidenceStatusAvailable = "available"
+)
+
+// CustodyEvent is an entry in an evidence item's chain-of-custody log. The
+// log is append-only; SHA256 records the hash observed at the time of the
+// event when the file was read.
+type CustodyEvent struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	EvidenceID primitive.ObjectID `bson:"evidence_id" json:"evidence_id"`
+	FIRID      primitive.ObjectID `bson:"fir_id" json:"fir_id"`
+	Action     string             `bson:"action" json:"action"`
+	ActorID    primitive.ObjectID `bson:"actor_id" json:"actor_id"`
+	ClientIP   string             `bson:"client_ip,omitempty" json:"client_ip,omitempty"`
+	Details    string             `bson:"details,omitempty" json:"details,omitempty"`
+	SHA256     string             `bson:"sha256,omitempty" json:"sha256,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Chain-of-custody actions.
+const (
+	CustodyUploadStarted   = "upload_started"
+	CustodyUploaded        = "uploaded"
+	CustodyDownloaded      = "downloaded"
+	CustodyVerified        = "verified"
+	CustodyIntegrityFailed = "integrity_failed"
+	CustodyTransferred     = "transferred"
+)
+
+// StartEvidenceUploadRequest opens a resumable upload. SHA256, when given,
+// is checked once the last chunk arrives.
+type StartEvidenceUploadRequest struct {
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type"`
+	Kind        string `json:"kind"`
+	Size        int64  `json:"size" binding:"required"`
+	SHA256      string `json:"sha256"`
+	Description string `json:"description"`
+}
+
+// TransferEvidenceRequest hands an evidence item to a new custodian, such
+// as a forensic lab or the court.
+type TransferEvidenceRequest struct {
+	To     string `json:"to" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
@@ -10,6 +10,9 @@ type FIR struct {
 	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	FIRNumber           string             `bson:"fir_number" json:"fir_number"`
 	OfficerID           primitive.ObjectID `bson:"officer_id" json:"officer_id"`
+	Station             string             `bson:"station" json:"station"`
+	District            string             `bson:"district,omitempty" json:"district,omitempty"`
+	State               string             `bson:"state,omitempty" json:"state,omitempty"`
 	ComplainantName     string             `bson:"complainant_name" json:"complainant_name"`
 	ComplainantAddress  string             `bson:"complainant_address" json:"complainant_address"`
 	ComplainantPhone    string             `bson:"complainant_phone" json:"complainant_phone"`
@@ -17,11 +20,12 @@ type FIR struct {
 	IncidentTime        string             `bson:"incident_time" json:"incident_time"`
 	IncidentLocation    string             `bson:"incident_location" json:"incident_location"`
 	IncidentDescription string             `bson:"incident_description" json:"incident_description"`
+	DescriptionEnglish  string             `bson:"description_english,omitempty" json:"description_english,omitempty"`
 	WitnessDetails      string             `bson:"witness_details" json:"witness_details"`
 	EvidenceDetails     string             `bson:"evidence_details" json:"evidence_details"`
 	OfficerRemarks      string             `bson:"officer_remarks" json:"officer_remarks"`
 	Language            string             `bson:"language" json:"language"`
-	Status              string             `bson:"status" json:"status"` // "draft", "submitted", "under_investigation", "closed"
+	Status              string             `bson:"status" json:"status"`     // "draft", "submitted", "under_investigation", "closed"
 	Priority            string             `bson:"priority" json:"priority"` // "low", "medium", "high"
 	ApplicableSections  []string           `bson:"applicable_sections" json:"applicable_sections"`
 	SuggestedLaws       []SuggestedLaw     `bson:"suggested_laws" json:"suggested_laws"`
@@ -30,31 +34,147 @@ type FIR struct {
 	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
 	SubmittedAt         *time.Time         `bson:"submitted_at" json:"submitted_at"`
+	DeletedAt           *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	StatusHistory       []StatusChange     `bson:"status_history,omitempty" json:"status_history,omitempty"`
+	Amendments          []FIRAmendment     `bson:"amendments,omitempty" json:"amendments,omitempty"`
+	Signature           *FIRSignature      `bson:"signature,omitempty" json:"signature,omitempty"`
+	Countersignatures   []FIRSignature     `bson:"countersignatures,omitempty" json:"countersignatures,omitempty"`
+
+	// The complainant's name, address and phone are stored encrypted
+	// under DataKey, a key of the FIR's own. The blind indexes are keyed
+	// hashes of the name and phone, so FIRs can be looked up by them
+	// without decrypting every record.
+	DataKey               *WrappedKey `bson:"data_key,omitempty" json:"-"`
+	ComplainantNameIndex  string      `bson:"complainant_name_index,omitempty" json:"-"`
+	ComplainantPhoneIndex string      `bson:"complainant_phone_index,omitempty" json:"-"`
+}
+
+// WrappedKey is a data key encrypted under the configured encryption key
+// KeyID, so rotating that key only means re-encrypting the data key.
+type WrappedKey struct {
+	KeyID string `bson:"key_id"`
+	Key   []byte `bson:"key"`
+}
+
+// FIR statuses. An FIR moves draft -> submitted -> under_investigation ->
+// closed and can only be edited or deleted while it is a draft.
+const (
+	FIRStatusDraft              = "draft"
+	FIRStatusSubmitted          = "submitted"
+	FIRStatusUnderInvestigation = "under_investigation"
+	FIRStatusClosed             = "closed"
+)
+
+// StatusChange records a move between FIR statuses.
+type StatusChange struct {
+	From      string             `bson:"from" json:"from"`
+	To        string             `bson:"to" json:"to"`
+	ChangedBy primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedAt time.Time          `bson:"changed_at" json:"changed_at"`
+}
+
+// FIRAmendment is a correction or supplementary statement added to an FIR
+// after submission, when the original text can no longer be edited. Field
+// names the FIR field it corrects, if any.
+type FIRAmendment struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Field     string             `bson:"field,omitempty" json:"field,omitempty"`
+	Text      string             `bson:"text" json:"text"`
+	Reason    string             `bson:"reason" json:"reason"`
+	OfficerID primitive.ObjectID `bson:"officer_id" json:"officer_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// FIRRevision is an append-only record of one change to an FIR. Snapshot
+// holds the whole FIR after the change and Fields the top-level fields that
+// changed. Revisions are chained: Hash covers the revision and PrevHash, so
+// editing or removing a stored revision breaks every hash after it.
+type FIRRevision struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	FIRID     primitive.ObjectID `bson:"fir_id" json:"fir_id"`
+	Number    int                `bson:"number" json:"number"`
+	Action    string             `bson:"action" json:"action"`
+	Fields    []string           `bson:"fields,omitempty" json:"fields,omitempty"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedBy primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	Snapshot  *FIR               `bson:"snapshot" json:"snapshot,omitempty"`
+	PrevHash  string             `bson:"prev_hash" json:"prev_hash"`
+	Hash      string             `bson:"hash" json:"hash"`
+}
+
+// FIR revision actions.
+const (
+	RevisionCreated       = "created"
+	RevisionUpdated       = "updated"
+	RevisionStatusChanged = "status_changed"
+	RevisionAmended       = "amended"
+	RevisionDeleted       = "deleted"
+	RevisionAnalyzed      = "analyzed"
+	RevisionCountersigned = "countersigned"
+)
+
+// LedgerEntry chains a submitted FIR into its station's ledger.
+// ContentHash is the hash of the FIR's canonical content at submission and
+// Hash covers the entry itself, including the previous entry's Hash.
+type LedgerEntry struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Station     string             `bson:"station" json:"station"`
+	Seq         int                `bson:"seq" json:"seq"`
+	FIRID       primitive.ObjectID `bson:"fir_id" json:"fir_id"`
+	FIRNumber   string             `bson:"fir_number" json:"fir_number"`
+	ContentHash string             `bson:"content_hash" json:"content_hash"`
+	PrevHash    string             `bson:"prev_hash" json:"prev_hash"`
+	Hash        string             `bson:"hash" json:"hash"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 }
 
 type SuggestedLaw struct {
-	Section     string  `bson:"section" json:"section"`
-	Act         string  `bson:"act" json:"act"`
-	Description string  `bson:"description" json:"description"`
-	Confidence  float64 `bson:"confidence" json:"confidence"`
-	Relevance   string  `bson:"relevance" json:"relevance"`
+	SectionID   primitive.ObjectID `bson:"section_id,omitempty" json:"section_id,omitempty"`
+	Section     string             `bson:"section" json:"section"`
+	Act         string             `bson:"act" json:"act"`
+	Description string             `bson:"description" json:"description"`
+	Confidence  float64            `bson:"confidence" json:"confidence"`
+	Relevance   string             `bson:"relevance" json:"relevance"`
+	Equivalents []SectionRef       `bson:"equivalents,omitempty" json:"equivalents,omitempty"`
 }
 
+// AIAnalysis is the AI assessment of an FIR's incident. Analysis runs in the
+// background: Status is pending until the job identified by JobID stores
+// the result. FIRs analysed before analysis was queued have no Status.
 type AIAnalysis struct {
-	Confidence       float64     `bson:"confidence" json:"confidence"`
-	KeyEntities      []string    `bson:"key_entities" json:"key_entities"`
-	CrimeType        string      `bson:"crime_type" json:"crime_type"`
-	RelevantCaseLaws []CaseLaw   `bson:"relevant_case_laws" json:"relevant_case_laws"`
-	Recommendations  []string    `bson:"recommendations" json:"recommendations"`
-	ProcessedAt      time.Time   `bson:"processed_at" json:"processed_at"`
+	Status           string              `bson:"status,omitempty" json:"status,omitempty"`
+	JobID            *primitive.ObjectID `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	Confidence       float64             `bson:"confidence" json:"confidence"`
+	KeyEntities      []string            `bson:"key_entities" json:"key_entities"`
+	CrimeType        string              `bson:"crime_type" json:"crime_type"`
+	RelevantCaseLaws []CaseLaw           `bson:"relevant_case_laws" json:"relevant_case_laws"`
+	Recommendations  []string            `bson:"recommendations" json:"recommendations"`
+	Source           string              `bson:"source" json:"source"` // "llm", "keyword"
+	Model            string              `bson:"model,omitempty" json:"model,omitempty"`
+	ProcessedAt      time.Time           `bson:"processed_at" json:"processed_at"`
 }
 
+const (
+	AnalysisSourceLLM     = "llm"
+	AnalysisSourceKeyword = "keyword"
+)
+
+// AI analysis statuses.
+const (
+	AnalysisStatusPending   = "pending"
+	AnalysisStatusCompleted = "completed"
+	AnalysisStatusFailed    = "failed"
+)
+
 type CaseLaw struct {
-	Title     string  `bson:"title" json:"title"`
-	Court     string  `bson:"court" json:"court"`
-	Year      string  `bson:"year" json:"year"`
-	Relevance float64 `bson:"relevance" json:"relevance"`
-	Summary   string  `bson:"summary" json:"summary"`
+	ID        primitive.ObjectID `bson:"case_law_id,omitempty" json:"id,omitempty"`
+	Title     string             `bson:"title" json:"title"`
+	Court     string             `bson:"court" json:"court"`
+	Year      string             `bson:"year" json:"year"`
+	Relevance float64            `bson:"relevance" json:"relevance"`
+	Summary   string             `bson:"summary" json:"summary"`
 }
 
 type CreateFIRRequest struct {
@@ -71,9 +191,64 @@ type CreateFIRRequest struct {
 	Language            string `json:"language"`
 }
 
+// UpdateFIRRequest edits a draft FIR. Only the fields that are set are
+// changed; Reason is recorded in the FIR's revision history.
+type UpdateFIRRequest struct {
+	ComplainantName     *string `json:"complainant_name"`
+	ComplainantAddress  *string `json:"complainant_address"`
+	ComplainantPhone    *string `json:"complainant_phone"`
+	IncidentDate        *string `json:"incident_date"`
+	IncidentTime        *string `json:"incident_time"`
+	IncidentLocation    *string `json:"incident_location"`
+	IncidentDescription *string `json:"incident_description"`
+	WitnessDetails      *string `json:"witness_details"`
+	EvidenceDetails     *string `json:"evidence_details"`
+	OfficerRemarks      *string `json:"officer_remarks"`
+	Language            *string `json:"language"`
+	Reason              string  `json:"reason"`
+}
+
+type ChangeFIRStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type AmendFIRRequest struct {
+	Field  string `json:"field"`
+	Text   string `json:"text" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GenerateFIRRequest drafts an FIR document. With Bilingual set, the
+// document is also produced in Language, or in the language the
+// description is written in when Language is empty.
 type GenerateFIRRequest struct {
 	IncidentDescription string `json:"incident_description" binding:"required"`
 	ComplainantName     string `json:"complainant_name"`
+	ComplainantAddress  string `json:"complainant_address"`
+	ComplainantPhone    string `json:"complainant_phone"`
 	IncidentLocation    string `json:"incident_location"`
 	IncidentDate        string `json:"incident_date"`
-}
\ No newline at end of file
+	IncidentTime        string `json:"incident_time"`
+	WitnessDetails      string `json:"witness_details"`
+	EvidenceDetails     string `json:"evidence_details"`
+	OfficerRemarks      string `json:"officer_remarks"`
+	Language            string `json:"language"`
+	Bilingual           bool   `json:"bilingual"`
+}
+
+// GeneratedFIR is a drafted FIR document in English and, for bilingual
+// documents, in a second language. Sections pairs each part of the English
+// text with its translation so the two can be laid out side by side.
+type GeneratedFIR struct {
+	Text           string                `json:"generated_fir"`
+	Template       *TemplateRef          `json:"template,omitempty"`
+	Language       string                `json:"language,omitempty"`
+	TranslatedText string                `json:"generated_fir_translated,omitempty"`
+	Sections       []GeneratedFIRSection `json:"sections,omitempty"`
+}
+
+type GeneratedFIRSection struct {
+	English    string `json:"english"`
+	Translated string `json:"translated"`
+}
@@ -7,38 +7,39 @@ import (
 )
 
 type LegalSection struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Section          string             `bson:"section" json:"section"`
-	Act              string             `bson:"act" json:"act"`
-	Title            string             `bson:"title" json:"title"`
-	Description      string             `bson:"description" json:"description"`
-	Category         string             `bson:"category" json:"category"`
-	Amendments       []string           `bson:"amendments" json:"amendments"`
-	RelatedSections  []string           `bson:"related_sections" json:"related_sections"`
-	Keywords         []string           `bson:"keywords" json:"keywords"`
-	Punishment       string             `bson:"punishment" json:"punishment"`
-	IsBailable       bool               `bson:"is_bailable" json:"is_bailable"`
-	IsCognizable     bool               `bson:"is_cognizable" json:"is_cognizable"`
-	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Section         string             `bson:"section" json:"section"`
+	Act             string             `bson:"act" json:"act"`
+	Title           string             `bson:"title" json:"title"`
+	Description     string             `bson:"description" json:"description"`
+	Category        string             `bson:"category" json:"category"`
+	Amendments      []string           `bson:"amendments" json:"amendments"`
+	RelatedSections []string           `bson:"related_sections" json:"related_sections"`
+	Keywords        []string           `bson:"keywords" json:"keywords"`
+	Punishment      string             `bson:"punishment" json:"punishment"`
+	IsBailable      bool               `bson:"is_bailable" json:"is_bailable"`
+	IsCognizable    bool               `bson:"is_cognizable" json:"is_cognizable"`
+	Embedding       []float32          `bson:"embedding,omitempty" json:"-"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 type CaseLawRecord struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title        string             `bson:"title" json:"title"`
-	Court        string             `bson:"court" json:"court"`
-	Year         string             `bson:"year" json:"year"`
-	Judges       []string           `bson:"judges" json:"judges"`
-	Summary      string             `bson:"summary" json:"summary"`
-	KeyPoints    []string           `bson:"key_points" json:"key_points"`
-	Citations    []string           `bson:"citations" json:"citations"`
-	Category     string             `bson:"category" json:"category"`
-	Importance   string             `bson:"importance" json:"importance"` // "landmark", "significant", "reference"
-	LegalIssues  []string           `bson:"legal_issues" json:"legal_issues"`
-	Sections     []string           `bson:"sections" json:"sections"`
-	FullText     string             `bson:"full_text" json:"full_text"`
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title       string             `bson:"title" json:"title"`
+	Court       string             `bson:"court" json:"court"`
+	Year        string             `bson:"year" json:"year"`
+	Judges      []string           `bson:"judges" json:"judges"`
+	Summary     string             `bson:"summary" json:"summary"`
+	KeyPoints   []string           `bson:"key_points" json:"key_points"`
+	Citations   []string           `bson:"citations" json:"citations"`
+	Category    string             `bson:"category" json:"category"`
+	Importance  string             `bson:"importance" json:"importance"` // "landmark", "significant", "reference"
+	LegalIssues []string           `bson:"legal_issues" json:"legal_issues"`
+	Sections    []string           `bson:"sections" json:"sections"`
+	FullText    string             `bson:"full_text" json:"full_text"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 type LandmarkJudgment struct {
@@ -55,9 +56,46 @@ type LandmarkJudgment struct {
 	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// Act names as stored in LegalSection.Act. The Bharatiya Nyaya Sanhita,
+// Bharatiya Nagarik Suraksha Sanhita and Bharatiya Sakshya Adhiniyam replaced
+// the IPC, CrPC and Evidence Act on 1 July 2024.
+const (
+	ActIPC  = "Indian Penal Code"
+	ActBNS  = "Bharatiya Nyaya Sanhita"
+	ActCrPC = "Code of Criminal Procedure"
+	ActBNSS = "Bharatiya Nagarik Suraksha Sanhita"
+	ActIEA  = "Indian Evidence Act"
+	ActBSA  = "Bharatiya Sakshya Adhiniyam"
+)
+
+// SectionMapping records how a section of a repealed act corresponds to
+// sections of its replacement, or the reverse.
+type SectionMapping struct {
+	FromAct     string   `bson:"from_act" json:"from_act"`
+	FromSection string   `bson:"from_section" json:"from_section"`
+	ToAct       string   `bson:"to_act" json:"to_act"`
+	ToSections  []string `bson:"to_sections" json:"to_sections"`
+	Type        string   `bson:"type" json:"type"` // "equivalent", "split", "merged", "repealed"
+	Notes       string   `bson:"notes" json:"notes"`
+}
+
+// SectionRef identifies a section of an act.
+type SectionRef struct {
+	Act     string `bson:"act" json:"act"`
+	Section string `bson:"section" json:"section"`
+}
+
+// CriminalCodes are the substantive, procedural and evidence laws that apply
+// to a case.
+type CriminalCodes struct {
+	Penal     string `json:"penal"`
+	Procedure string `json:"procedure"`
+	Evidence  string `json:"evidence"`
+}
+
 type SearchRequest struct {
 	Query    string `json:"query" binding:"required"`
 	Category string `json:"category"`
 	Limit    int    `json:"limit"`
 	Page     int    `json:"page"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Signature roles. The registering officer signs an FIR on submission and
+// supervisors at the station may countersign it afterwards.
+const (
+	SignatureRoleRegistering = "registering_officer"
+	SignatureRoleCountersign = "countersign"
+)
+
+// SignatureAlgorithm is the algorithm FIRs are signed with.
+const SignatureAlgorithm = "Ed25519"
+
+// FIRSignature is a detached signature of an FIR's canonical JSON, the
+// content sealed into the ledger on submission. PublicKey and Signature are
+// base64 encoded, and KeyID is the start of the SHA-256 of the public key.
+// The signer's details are copied at signing time.
+type FIRSignature struct {
+	Role        string             `bson:"role" json:"role"`
+	OfficerID   primitive.ObjectID `bson:"officer_id" json:"officer_id"`
+	SignerName  string             `bson:"signer_name" json:"signer_name"`
+	SignerRank  string             `bson:"signer_rank" json:"signer_rank"`
+	SignerBadge string             `bson:"signer_badge" json:"signer_badge"`
+	Algorithm   string             `bson:"algorithm" json:"algorithm"`
+	KeyID       string             `bson:"key_id" json:"key_id"`
+	PublicKey   string             `bson:"public_key" json:"public_key"`
+	ContentHash string             `bson:"content_hash" json:"content_hash"`
+	Signature   string             `bson:"signature" json:"signature"`
+	SignedAt    time.Time          `bson:"signed_at" json:"signed_at"`
+}
+
+// OfficerKey is an officer's signing keypair. The private key is stored
+// encrypted and never leaves the server. SealedWith is the ID of the
+// encryption key it is encrypted under; keys created before key IDs were
+// recorded have none.
+type OfficerKey struct {
+	ID                  primitive.ObjectID `bson:"_id" json:"id"`
+	OfficerID           primitive.ObjectID `bson:"officer_id" json:"officer_id"`
+	Algorithm           string             `bson:"algorithm" json:"algorithm"`
+	KeyID               string             `bson:"key_id" json:"key_id"`
+	PublicKey           []byte             `bson:"public_key" json:"public_key"`
+	EncryptedPrivateKey []byte             `bson:"encrypted_private_key" json:"-"`
+	SealedWith          string             `bson:"sealed_with,omitempty" json:"-"`
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// Speaker roles in a recorded statement. A turn's role decides which FIR
+// field its text is mapped into; RoleIgnore drops it.
+const (
+	RoleComplainant = "complainant"
+	RoleWitness     = "witness"
+	RoleOfficer     = "officer"
+	RoleIgnore      = "ignore"
+)
+
+// SpeakerTurn is a stretch of a recording in which one speaker talks
+// without interruption. Start and End are in seconds. Role, when set,
+// overrides the role of the turn's speaker.
+type SpeakerTurn struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text" binding:"required"`
+	Role    string  `json:"role,omitempty"`
+}
+
+// SpeakerSummary describes one speaker in a recording, with the role the
+// speaker most likely has.
+type SpeakerSummary struct {
+	Speaker       string  `json:"speaker"`
+	Duration      float64 `json:"duration"`
+	Turns         int     `json:"turns"`
+	SuggestedRole string  `json:"suggested_role"`
+}
+
+// MapStatementRequest maps the turns of a transcribed statement into FIR
+// fields. Roles assigns a role to each speaker label; speakers without one
+// get their suggested role. With Append set, the mapped text is added to
+// the FIR's existing fields instead of replacing them.
+type MapStatementRequest struct {
+	Turns  []SpeakerTurn     `json:"turns" binding:"required,min=1,dive"`
+	Roles  map[string]string `json:"roles"`
+	Append bool              `json:"append"`
+	Reason string            `json:"reason"`
+}
+
+// StatementMapping is the FIR text mapped from a statement.
+type StatementMapping struct {
+	IncidentDescription string           `json:"incident_description"`
+	WitnessDetails      string           `json:"witness_details"`
+	OfficerRemarks      string           `json:"officer_remarks"`
+	Speakers            []SpeakerSummary `json:"speakers"`
+}
@@ -19,11 +19,15 @@ type User struct {
 	District    string             `bson:"district" json:"district"`
 	State       string             `bson:"state" json:"state"`
 	IsActive    bool               `bson:"is_active" json:"is_active"`
-	Role        string             `bson:"role" json:"role"` // "officer", "admin", "supervisor"
-	Permissions []string           `bson:"permissions" json:"permissions"`
+	Role        string             `bson:"role" json:"role"`               // "officer", "sho", "supervisor", "admin"
+	Permissions []string           `bson:"permissions" json:"permissions"` // granted in addition to the role's
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 	LastLogin   *time.Time         `bson:"last_login" json:"last_login"`
+
+	// AccessVersion is incremented whenever the user's role or permissions
+	// change, revoking the tokens issued before.
+	AccessVersion int `bson:"access_version" json:"-"`
 }
 
 type LoginRequest struct {
@@ -47,4 +51,14 @@ type RegisterRequest struct {
 	Department string `json:"department"`
 	District   string `json:"district"`
 	State      string `json:"state"`
-}
\ No newline at end of file
+}
+
+// UpdateUserAccessRequest sets a user's role, the permissions granted to
+// them in addition to it and, when given, their posting.
+type UpdateUserAccessRequest struct {
+	Role        string   `json:"role" binding:"required"`
+	Permissions []string `json:"permissions"`
+	Station     *string  `json:"station"`
+	District    *string  `json:"district"`
+	State       *string  `json:"state"`
+}
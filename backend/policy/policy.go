@@ -0,0 +1,180 @@
+// Package policy maps user roles to the permissions they grant and makes
+// the resource-level checks that go with them, such as whether a user
+// works at an FIR's station.
+//
+// A user's permissions are those of their role plus any granted to them
+// individually in User.Permissions. They are computed at login and carried
+// in the JWT. Changing a user's role or permissions revokes their tokens, so
+// the change takes effect when they log in again.
+package policy
+
+import (
+	"sort"
+
+	"legalassist-ai-backend/models"
+)
+
+// User roles.
+const (
+	RoleOfficer = "officer"
+	// RoleSHO is the station house officer in charge of a police station.
+	RoleSHO        = "sho"
+	RoleSupervisor = "supervisor"
+	RoleAdmin      = "admin"
+)
+
+// Permissions.
+const (
+	FIRCreate      = "fir:create"
+	FIRRead        = "fir:read"
+	FIRUpdate      = "fir:update"
+	FIRDelete      = "fir:delete"
+	FIRSubmit      = "fir:submit"
+	FIRStatus      = "fir:status"
+	FIRCountersign = "fir:countersign"
+	EvidenceRead   = "evidence:read"
+	EvidenceWrite  = "evidence:write"
+	DashboardRead  = "dashboard:read"
+	JobsRead       = "jobs:read"
+	LegalRead      = "legal:read"
+	LegalManage    = "legal:manage"
+	TemplateManage = "templates:manage"
+	LedgerVerify   = "ledger:verify"
+	UsersManage    = "users:manage"
+	ProfileManage  = "profile:manage"
+
+	// FIRManageStation extends a user's FIR and evidence permissions from
+	// their own FIRs to every FIR at their station.
+	FIRManageStation = "fir:manage:station"
+
+	// The FIR read scopes widen fir:read from a user's own FIRs to every
+	// FIR in their station, district or state.
+	FIRReadStation  = "fir:read:station"
+	FIRReadDistrict = "fir:read:district"
+	FIRReadState    = "fir:read:state"
+)
+
+// grantOnlyPermissions are granted to users individually rather than by a
+// role.
+var grantOnlyPermissions = []string{FIRReadState}
+
+// officerPermissions are what every police officer can do with their own
+// FIRs.
+var officerPermissions = []string{
+	FIRCreate, FIRRead, FIRUpdate, FIRDelete, FIRSubmit, FIRStatus,
+	EvidenceRead, EvidenceWrite,
+	DashboardRead, JobsRead, LegalRead, ProfileManage,
+}
+
+// rolePermissions lists the permissions each role grants.
+var rolePermissions = map[string][]string{
+	RoleOfficer:    officerPermissions,
+	RoleSHO:        append([]string{FIRCountersign, FIRManageStation, FIRReadStation}, officerPermissions...),
+	RoleSupervisor: append([]string{FIRCountersign, FIRManageStation, FIRReadStation, FIRReadDistrict, LedgerVerify}, officerPermissions...),
+	RoleAdmin: {
+		FIRRead, DashboardRead, JobsRead, LegalRead, ProfileManage,
+		LegalManage, TemplateManage, LedgerVerify, UsersManage,
+	},
+}
+
+// ValidRole reports whether role is a known role.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// ValidPermission reports whether permission is a known permission.
+func ValidPermission(permission string) bool {
+	if contains(grantOnlyPermissions, permission) {
+		return true
+	}
+	for _, permissions := range rolePermissions {
+		if contains(permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions of a role together with the extra
+// ones granted, sorted and without duplicates. An unknown role grants
+// nothing.
+func Permissions(role string, granted []string) []string {
+	seen := map[string]bool{}
+	permissions := []string{}
+	for _, permission := range append(append([]string{}, rolePermissions[role]...), granted...) {
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// Has reports whether permissions includes permission.
+func Has(permissions []string, permission string) bool {
+	return contains(permissions, permission)
+}
+
+// Allows reports whether user has permission.
+func Allows(user *models.User, permission string) bool {
+	return Has(Permissions(user.Role, user.Permissions), permission)
+}
+
+// SameStation reports whether user is posted at station. Users without a
+// station are at none.
+func SameStation(user *models.User, station string) bool {
+	return user.Station != "" && user.Station == station
+}
+
+// StationAllows reports whether user has permission over a resource at
+// station: they must have the permission and work at that station.
+func StationAllows(user *models.User, permission, station string) bool {
+	return Allows(user, permission) && SameStation(user, station)
+}
+
+// FIRAllows reports whether user may use permission on fir: on their own
+// FIRs, or on any FIR at their station when they manage it.
+func FIRAllows(user *models.User, permission string, fir *models.FIR) bool {
+	if !Allows(user, permission) {
+		return false
+	}
+	return fir.OfficerID == user.ID || StationAllows(user, FIRManageStation, fir.Station)
+}
+
+// PostingRequired reports which parts of a user's posting the permissions
+// of role and granted are scoped by: the station for station-wide
+// permissions, the district and state for district-wide reads and the
+// state for state-wide reads.
+func PostingRequired(role string, granted []string) (station, district, state bool) {
+	permissions := Permissions(role, granted)
+	station = Has(permissions, FIRManageStation) || Has(permissions, FIRCountersign) || Has(permissions, FIRReadStation)
+	district = Has(permissions, FIRReadDistrict)
+	state = district || Has(permissions, FIRReadState)
+	return station, district, state
+}
+
+// ReadScope returns the widest jurisdiction user may read FIRs in. A scope
+// needs its permission and the user's posting at that level, so a
+// supervisor without a district recorded falls back to their station.
+func ReadScope(user *models.User) models.AccessScope {
+	switch {
+	case Allows(user, FIRReadState) && user.State != "":
+		return models.AccessScope{Level: models.ScopeState, State: user.State}
+	case Allows(user, FIRReadDistrict) && user.District != "" && user.State != "":
+		return models.AccessScope{Level: models.ScopeDistrict, District: user.District, State: user.State}
+	case Allows(user, FIRReadStation) && user.Station != "":
+		return models.AccessScope{Level: models.ScopeStation, Station: user.Station}
+	}
+	return models.AccessScope{Level: models.ScopeOwn}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		granted []string
+		want    []string
+	}{
+		{"admin", RoleAdmin, nil, []string{
+			DashboardRead, FIRRead, JobsRead, LedgerVerify, LegalManage, LegalRead, ProfileManage, TemplateManage, UsersManage,
+		}},
+		{"grant added and sorted", RoleAdmin, []string{FIRReadState}, []string{
+			DashboardRead, FIRRead, FIRReadState, JobsRead, LedgerVerify, LegalManage, LegalRead, ProfileManage, TemplateManage, UsersManage,
+		}},
+		{"grant the role already has", RoleAdmin, []string{LedgerVerify, LedgerVerify}, []string{
+			DashboardRead, FIRRead, JobsRead, LedgerVerify, LegalManage, LegalRead, ProfileManage, TemplateManage, UsersManage,
+		}},
+		{"unknown role", "chief", nil, []string{}},
+		{"unknown role with a grant", "chief", []string{LegalRead}, []string{LegalRead}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Permissions(tt.role, tt.granted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Permissions(%q, %q) = %q, want %q", tt.role, tt.granted, got, tt.want)
+			}
+		})
+	}
+
+	// The SHO and supervisor lists are both built on officerPermissions.
+	sho := Permissions(RoleSHO, nil)
+	if Has(sho, FIRReadDistrict) || Has(sho, LedgerVerify) {
+		t.Errorf("Permissions(sho) = %q, want no supervisor permissions", sho)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role       string
+		granted    []string
+		permission string
+		want       bool
+	}{
+		{RoleOfficer, nil, FIRSubmit, true},
+		{RoleOfficer, nil, FIRCountersign, false},
+		{RoleOfficer, []string{FIRReadState}, FIRReadState, true},
+		{RoleSHO, nil, FIRCountersign, true},
+		{RoleSHO, nil, FIRReadDistrict, false},
+		{RoleSupervisor, nil, FIRReadDistrict, true},
+		{RoleSupervisor, nil, FIRReadState, false},
+		{RoleSupervisor, nil, UsersManage, false},
+		{RoleAdmin, nil, UsersManage, true},
+		{RoleAdmin, nil, FIRCreate, false},
+		{RoleAdmin, nil, FIRSubmit, false},
+		{"", nil, FIRRead, false},
+	}
+	for _, tt := range tests {
+		user := &models.User{Role: tt.role, Permissions: tt.granted}
+		if got := Allows(user, tt.permission); got != tt.want {
+			t.Errorf("Allows(%s with %q, %s) = %v, want %v", tt.role, tt.granted, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestStationAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    models.User
+		station string
+		want    bool
+	}{
+		{"SHO at their station", models.User{Role: RoleSHO, Station: "Connaught Place"}, "Connaught Place", true},
+		{"SHO at another station", models.User{Role: RoleSHO, Station: "Connaught Place"}, "Karol Bagh", false},
+		{"SHO without a station", models.User{Role: RoleSHO}, "", false},
+		{"officer at their station", models.User{Role: RoleOfficer, Station: "Connaught Place"}, "Connaught Place", false},
+		{"officer granted countersigning", models.User{Role: RoleOfficer, Station: "Connaught Place", Permissions: []string{FIRCountersign}}, "Connaught Place", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StationAllows(&tt.user, FIRCountersign, tt.station); got != tt.want {
+				t.Errorf("StationAllows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFIRAllows(t *testing.T) {
+	officerID := primitive.NewObjectID()
+	fir := &models.FIR{OfficerID: officerID, Station: "Connaught Place"}
+
+	tests := []struct {
+		name       string
+		user       models.User
+		permission string
+		want       bool
+	}{
+		{"own FIR", models.User{ID: officerID, Role: RoleOfficer, Station: "Connaught Place"}, FIRUpdate, true},
+		{"own FIR without the permission", models.User{ID: officerID, Role: RoleAdmin}, FIRUpdate, false},
+		{"colleague's FIR", models.User{ID: primitive.NewObjectID(), Role: RoleOfficer, Station: "Connaught Place"}, FIRUpdate, false},
+		{"SHO at the FIR's station", models.User{ID: primitive.NewObjectID(), Role: RoleSHO, Station: "Connaught Place"}, FIRUpdate, true},
+		{"SHO at another station", models.User{ID: primitive.NewObjectID(), Role: RoleSHO, Station: "Karol Bagh"}, FIRUpdate, false},
+		{"supervisor at the FIR's station", models.User{ID: primitive.NewObjectID(), Role: RoleSupervisor, Station: "Connaught Place"}, FIRDelete, true},
+		{"admin", models.User{ID: primitive.NewObjectID(), Role: RoleAdmin, Station: "Connaught Place"}, FIRRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FIRAllows(&tt.user, tt.permission, fir); got != tt.want {
+				t.Errorf("FIRAllows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadScope(t *testing.T) {
+	posting := func(user models.User) *models.User {
+		user.Station, user.District, user.State = "Connaught Place", "New Delhi", "Delhi"
+		return &user
+	}
+
+	tests := []struct {
+		name string
+		user *models.User
+		want models.AccessScope
+	}{
+		{"officer", posting(models.User{Role: RoleOfficer}), models.AccessScope{Level: models.ScopeOwn}},
+		{"SHO", posting(models.User{Role: RoleSHO}), models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"}},
+		{"supervisor", posting(models.User{Role: RoleSupervisor}), models.AccessScope{Level: models.ScopeDistrict, District: "New Delhi", State: "Delhi"}},
+		{"supervisor without a district", &models.User{Role: RoleSupervisor, Station: "Connaught Place", State: "Delhi"}, models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"}},
+		{"supervisor without a state", &models.User{Role: RoleSupervisor, Station: "Connaught Place", District: "New Delhi"}, models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"}},
+		{"supervisor without a posting", &models.User{Role: RoleSupervisor}, models.AccessScope{Level: models.ScopeOwn}},
+		{"state read grant", posting(models.User{Role: RoleOfficer, Permissions: []string{FIRReadState}}), models.AccessScope{Level: models.ScopeState, State: "Delhi"}},
+		{"state read grant without a state", &models.User{Role: RoleSupervisor, Permissions: []string{FIRReadState}, Station: "Connaught Place"}, models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"}},
+		{"admin", posting(models.User{Role: RoleAdmin}), models.AccessScope{Level: models.ScopeOwn}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadScope(tt.user); got != tt.want {
+				t.Errorf("ReadScope() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostingRequired(t *testing.T) {
+	tests := []struct {
+		role                                 string
+		granted                              []string
+		wantStation, wantDistrict, wantState bool
+	}{
+		{RoleOfficer, nil, false, false, false},
+		{RoleAdmin, nil, false, false, false},
+		{RoleSHO, nil, true, false, false},
+		{RoleSupervisor, nil, true, true, true},
+		{RoleOfficer, []string{FIRReadState}, false, false, true},
+		{RoleOfficer, []string{FIRCountersign}, true, false, false},
+	}
+	for _, tt := range tests {
+		station, district, state := PostingRequired(tt.role, tt.granted)
+		if station != tt.wantStation || district != tt.wantDistrict || state != tt.wantState {
+			t.Errorf("PostingRequired(%s, %q) = %v, %v, %v, want %v, %v, %v",
+				tt.role, tt.granted, station, district, state, tt.wantStation, tt.wantDistrict, tt.wantState)
+		}
+	}
+}
@@ -4,16 +4,23 @@ import (
 	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/handlers"
 	"legalassist-ai-backend/middleware"
+	"legalassist-ai-backend/policy"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(router *gin.RouterGroup, cfg *config.Config) {
+	require := middleware.RequirePermission
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler()
-	firHandler := handlers.NewFIRHandler()
-	dashboardHandler := handlers.NewDashboardHandler()
+	firHandler := handlers.NewFIRHandler(cfg)
+	evidenceHandler := handlers.NewEvidenceHandler(cfg)
+	jobHandler := handlers.NewJobHandler()
+	dashboardHandler := handlers.NewDashboardHandler(cfg)
 	legalHandler := handlers.NewLegalHandler()
+	legalAdminHandler := handlers.NewLegalAdminHandler(cfg)
+	documentHandler := handlers.NewDocumentHandler(cfg)
 
 	// Auth routes
 	auth := router.Group("/auth")
@@ -21,42 +28,109 @@ func SetupRoutes(router *gin.RouterGroup, cfg *config.Config) {
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
 		auth.GET("/verify", middleware.AuthMiddleware(), authHandler.VerifyToken)
-		auth.GET("/profile", middleware.AuthMiddleware(), authHandler.GetProfile)
+		auth.GET("/profile", middleware.AuthMiddleware(), require(policy.ProfileManage), authHandler.GetProfile)
 	}
 
-	// Protected routes
+	// Public verification of printed FIRs
+	router.GET("/verify/:code", firHandler.VerifyCopy)
+
+	// Protected routes. Each declares the permission it requires; handlers
+	// and services make the resource-level checks, such as ownership or
+	// the user's station.
 	protected := router.Group("/")
 	protected.Use(middleware.AuthMiddleware())
 
 	// Dashboard routes
 	dashboard := protected.Group("/dashboard")
 	{
-		dashboard.GET("/stats", dashboardHandler.GetStats)
-		dashboard.GET("/recent-cases", dashboardHandler.GetRecentCases)
+		dashboard.GET("/stats", require(policy.DashboardRead), dashboardHandler.GetStats)
+		dashboard.GET("/recent-cases", require(policy.DashboardRead), dashboardHandler.GetRecentCases)
 	}
 
 	// FIR routes
 	fir := protected.Group("/fir")
 	{
-		fir.POST("/create", firHandler.CreateFIR)
-		fir.GET("/list", firHandler.GetFIRs)
-		fir.GET("/:id", firHandler.GetFIRByID)
-		fir.POST("/generate", firHandler.GenerateFIR)
-		fir.PUT("/:id/submit", firHandler.SubmitFIR)
-		fir.POST("/transcribe", firHandler.TranscribeAudio)
+		fir.POST("/create", require(policy.FIRCreate), firHandler.CreateFIR)
+		fir.GET("/list", require(policy.FIRRead), firHandler.GetFIRs)
+		fir.GET("/:id", require(policy.FIRRead), firHandler.GetFIRByID)
+		fir.PUT("/:id", require(policy.FIRUpdate), firHandler.UpdateFIR)
+		fir.DELETE("/:id", require(policy.FIRDelete), firHandler.DeleteFIR)
+		fir.POST("/generate", require(policy.FIRCreate), firHandler.GenerateFIR)
+		fir.GET("/:id/document", require(policy.FIRRead), firHandler.GetDocument)
+		fir.GET("/:id/pdf", require(policy.FIRRead), firHandler.GetPDF)
+		fir.PUT("/:id/submit", require(policy.FIRSubmit), firHandler.SubmitFIR)
+		fir.PUT("/:id/status", require(policy.FIRStatus), firHandler.ChangeStatus)
+		fir.POST("/:id/amendments", require(policy.FIRUpdate), firHandler.AddAmendment)
+		fir.GET("/:id/amendments", require(policy.FIRRead), firHandler.GetAmendments)
+		fir.GET("/:id/revisions", require(policy.FIRRead), firHandler.GetRevisions)
+		fir.GET("/:id/revisions/diff", require(policy.FIRRead), firHandler.DiffRevisions)
+		fir.GET("/:id/as-of", require(policy.FIRRead), firHandler.GetFIRAsOf)
+		fir.GET("/:id/verify", require(policy.FIRRead), firHandler.VerifyFIR)
+		fir.GET("/:id/signatures", require(policy.FIRRead), firHandler.GetSignatures)
+		fir.POST("/:id/countersign", require(policy.FIRCountersign), firHandler.Countersign)
+		fir.POST("/:id/evidence", require(policy.EvidenceWrite), evidenceHandler.UploadEvidence)
+		fir.GET("/:id/evidence", require(policy.EvidenceRead), evidenceHandler.ListEvidence)
+		fir.POST("/:id/evidence/uploads", require(policy.EvidenceWrite), evidenceHandler.StartUpload)
+		fir.PATCH("/:id/evidence/uploads/:evidenceId", require(policy.EvidenceWrite), evidenceHandler.UploadChunk)
+		fir.GET("/:id/evidence/:evidenceId", require(policy.EvidenceRead), evidenceHandler.GetEvidence)
+		fir.GET("/:id/evidence/:evidenceId/download", require(policy.EvidenceRead), evidenceHandler.DownloadEvidence)
+		fir.POST("/:id/evidence/:evidenceId/verify", require(policy.EvidenceWrite), evidenceHandler.VerifyEvidence)
+		fir.POST("/:id/evidence/:evidenceId/transfer", require(policy.EvidenceWrite), evidenceHandler.TransferEvidence)
+		fir.GET("/:id/evidence/:evidenceId/custody", require(policy.EvidenceRead), evidenceHandler.GetCustodyLog)
+		fir.POST("/transcribe", require(policy.FIRCreate), firHandler.TranscribeAudio)
+		fir.POST("/transcribe/map", require(policy.FIRCreate), firHandler.MapStatement)
+		fir.POST("/:id/statement", require(policy.FIRUpdate), firHandler.ApplyStatement)
 	}
 
+	// Background job routes
+	protected.GET("/jobs/:id", require(policy.JobsRead), jobHandler.GetJob)
+
 	// Legal database routes
 	legal := protected.Group("/legal")
+	legal.Use(require(policy.LegalRead))
 	{
 		legal.GET("/search", legalHandler.SearchLaws)
 		legal.GET("/sections/:act", legalHandler.GetSections)
+		legal.GET("/sections/:act/graph", legalHandler.GetSectionGraph)
 		legal.GET("/case-laws/:section", legalHandler.GetCaseLaws)
 		legal.GET("/landmark-judgments", legalHandler.GetLandmarkJudgments)
+		legal.GET("/map", legalHandler.MapSection)
 	}
 
+	// Admin routes
+	admin := protected.Group("/admin")
+
+	legalAdmin := admin.Group("/legal")
+	legalAdmin.Use(require(policy.LegalManage))
+	{
+		legalAdmin.POST("/sections", legalAdminHandler.CreateSection)
+		legalAdmin.PUT("/sections/:id", legalAdminHandler.UpdateSection)
+		legalAdmin.DELETE("/sections/:id", legalAdminHandler.DeleteSection)
+		legalAdmin.POST("/case-laws", legalAdminHandler.CreateCaseLaw)
+		legalAdmin.PUT("/case-laws/:id", legalAdminHandler.UpdateCaseLaw)
+		legalAdmin.DELETE("/case-laws/:id", legalAdminHandler.DeleteCaseLaw)
+		legalAdmin.POST("/judgments", legalAdminHandler.CreateJudgment)
+		legalAdmin.PUT("/judgments/:id", legalAdminHandler.UpdateJudgment)
+		legalAdmin.DELETE("/judgments/:id", legalAdminHandler.DeleteJudgment)
+		legalAdmin.POST("/import/:kind", legalAdminHandler.Import)
+		legalAdmin.GET("/dangling-references", legalAdminHandler.DanglingReferences)
+	}
+
+	templateAdmin := admin.Group("/templates")
+	templateAdmin.Use(require(policy.TemplateManage))
+	{
+		templateAdmin.GET("", documentHandler.ListTemplates)
+		templateAdmin.POST("", documentHandler.CreateTemplate)
+		templateAdmin.GET("/:id", documentHandler.GetTemplate)
+		templateAdmin.POST("/preview", documentHandler.PreviewTemplate)
+	}
+
+	admin.GET("/ledger/verify", require(policy.LedgerVerify), firHandler.VerifyLedger)
+	admin.PUT("/users/:id/access", require(policy.UsersManage), authHandler.UpdateUserAccess)
+
 	// Settings routes
 	settings := protected.Group("/settings")
+	settings.Use(require(policy.ProfileManage))
 	{
 		settings.GET("/profile", authHandler.GetProfile)
 		settings.PUT("/profile", authHandler.GetProfile) // Will implement update
@@ -64,4 +138,4 @@ func SetupRoutes(router *gin.RouterGroup, cfg *config.Config) {
 			c.JSON(200, gin.H{"preferences": map[string]interface{}{}})
 		})
 	}
-}
\ No newline at end of file
+}
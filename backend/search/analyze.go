@@ -0,0 +1,209 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "was": true, "were": true, "with": true, "from": true,
+	"that": true, "this": true, "his": true, "her": true, "him": true, "she": true,
+	"they": true, "them": true, "their": true, "had": true, "has": true, "have": true,
+	"for": true, "but": true, "not": true, "are": true, "who": true, "when": true,
+	"which": true, "into": true, "any": true, "such": true, "shall": true, "may": true,
+	"whoever": true, "section": true, "sec": true, "under": true, "near": true,
+	"about": true, "after": true, "there": true, "been": true,
+}
+
+// synonymGroups lists words that should find each other. Each group is
+// expanded in both directions after analysis.
+var synonymGroups = [][]string{
+	{"cheating", "fraud", "deception", "scam", "swindle"},
+	{"theft", "steal", "stolen", "stealing"},
+	{"robbery", "snatching", "loot"},
+	{"assault", "attack", "beating", "hurt"},
+	{"murder", "killing", "homicide"},
+	{"threat", "intimidation", "threatening"},
+	{"harassment", "stalking", "molestation"},
+	{"dowry", "stridhan"},
+	{"bail", "release"},
+	{"arrest", "custody", "detention"},
+	{"fir", "complaint", "information"},
+	{"evidence", "proof"},
+	{"confession", "admission"},
+}
+
+var synonyms = buildSynonyms(synonymGroups)
+
+func buildSynonyms(groups [][]string) map[string][]string {
+	table := make(map[string][]string)
+	for _, group := range groups {
+		var terms []string
+		for _, word := range group {
+			terms = append(terms, Analyze(word)...)
+		}
+		for _, term := range terms {
+			for _, other := range terms {
+				if other != term && !contains(table[term], other) {
+					table[term] = append(table[term], other)
+				}
+			}
+		}
+	}
+	return table
+}
+
+// sectionSuffixPattern matches section numbers written with a separated
+// letter suffix, "498-A" or "498 A", so they analyse to the same term as
+// "498A". A space only joins an upper-case suffix to avoid "3 a person".
+var sectionSuffixPattern = regexp.MustCompile(`\b(\d+)(?:\s*-\s*([A-Za-z])|\s+([A-Z]))\b`)
+
+// Analyze lowercases text, splits it on non-alphanumerics, joins section
+// numbers with their letter suffix, drops stop words and applies light
+// suffix stripping. Tokens containing digits are kept whole.
+func Analyze(text string) []string {
+	var terms []string
+	for _, token := range tokens(text) {
+		if term := normalizeToken(token.text); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// token is a word of text and its byte span in the original string.
+type token struct {
+	text       string
+	start, end int
+}
+
+func tokens(text string) []token {
+	joined := make(map[int][]int)
+	for _, match := range sectionSuffixPattern.FindAllStringSubmatchIndex(text, -1) {
+		joined[match[0]] = match
+	}
+
+	var out []token
+	start := -1
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if start == -1 {
+			if match, ok := joined[i]; ok {
+				var suffix string
+				if match[4] != -1 {
+					suffix = text[match[4]:match[5]]
+				} else {
+					suffix = text[match[6]:match[7]]
+				}
+				out = append(out, token{text: text[match[2]:match[3]] + suffix, start: i, end: match[1]})
+				i = match[1]
+				continue
+			}
+		}
+		// Combining marks, such as Indic vowel signs, are part of the word.
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || (start != -1 && unicode.IsMark(r)) {
+			if start == -1 {
+				start = i
+			}
+		} else if start != -1 {
+			out = append(out, token{text: text[start:i], start: start, end: i})
+			start = -1
+		}
+		i += size
+	}
+	if start != -1 {
+		out = append(out, token{text: text[start:], start: start, end: len(text)})
+	}
+	return out
+}
+
+func normalizeToken(text string) string {
+	text = strings.ToLower(text)
+	if strings.IndexFunc(text, unicode.IsDigit) >= 0 {
+		return text
+	}
+	if len(text) < 3 || stopWords[text] {
+		return ""
+	}
+	return stem(text)
+}
+
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// snippetWords is how many words of context a highlight snippet keeps.
+const snippetWords = 24
+
+// Highlight returns an HTML-escaped snippet of text around its first match
+// of terms, with every matched word wrapped in <mark> tags. It reports
+// false when nothing in text matches.
+func Highlight(text string, terms map[string]bool) (string, bool) {
+	words := tokens(text)
+	first := -1
+	matches := make([]bool, len(words))
+	for i, word := range words {
+		if term := normalizeToken(word.text); term != "" && terms[term] {
+			matches[i] = true
+			if first == -1 {
+				first = i
+			}
+		}
+	}
+	if first == -1 {
+		return "", false
+	}
+
+	from := first - snippetWords/4
+	if from < 0 {
+		from = 0
+	}
+	to := from + snippetWords
+	if to > len(words) {
+		to = len(words)
+	}
+
+	var b strings.Builder
+	start := words[from].start
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	cursor := start
+	for i := from; i < to; i++ {
+		word := words[i]
+		if !matches[i] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[cursor:word.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[word.start:word.end]))
+		b.WriteString("</mark>")
+		cursor = word.end
+	}
+	end := words[to-1].end
+	if to == len(words) {
+		end = len(text)
+	}
+	b.WriteString(html.EscapeString(text[cursor:end]))
+	if end < len(text) {
+		b.WriteString(" …")
+	}
+	return strings.TrimSpace(b.String()), true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
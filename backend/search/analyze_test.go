@@ -0,0 +1,68 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The accused was stealing jewellery", []string{"accus", "steal", "jewellery"}},
+		{"Section 498-A IPC", []string{"498a", "ipc"}},
+		{"under s. 498 A and 304B", []string{"498a", "304b"}},
+		{"3 a person", []string{"3", "person"}},
+		{"cheats, threatened; goes", []string{"cheat", "threaten", "goes"}},
+		{"झूठा वादा", []string{"झूठा", "वादा"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Analyze(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Analyze(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		terms  []string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "marks every match",
+			text:   "Whoever steals property, and the stealing of <cattle>",
+			terms:  []string{"steal"},
+			want:   "Whoever <mark>steals</mark> property, and the <mark>stealing</mark> of &lt;cattle&gt;",
+			wantOK: true,
+		},
+		{
+			name:   "section with suffix",
+			text:   "Punishable under 498-A.",
+			terms:  []string{"498a"},
+			want:   "Punishable under <mark>498-A</mark>.",
+			wantOK: true,
+		},
+		{
+			name:  "no match",
+			text:  "Whoever commits murder",
+			terms: []string{"theft"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := make(map[string]bool)
+			for _, term := range tt.terms {
+				terms[term] = true
+			}
+			got, ok := Highlight(tt.text, terms)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Highlight() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
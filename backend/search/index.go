@@ -0,0 +1,215 @@
+// Package search implements a small in-process full-text index ranked with
+// BM25F. It is sized for the legal corpus: a few thousand documents that are
+// cheap to rebuild from the database.
+package search
+
+import (
+	"math"
+	"sort"
+)
+
+// BM25 parameters. k1 controls term-frequency saturation and b how strongly
+// scores are normalised by field length.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// synonymWeight scales the contribution of terms added by synonym expansion
+// so exact matches rank first.
+const synonymWeight = 0.5
+
+// Field is an indexed document field and its weight relative to the others.
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Document is a record to index. Fields not declared on the index are ignored.
+type Document struct {
+	ID     string
+	Fields map[string]string
+}
+
+// Hit is a matching document. Highlights holds a snippet for every field that
+// matched, with matched words wrapped in <mark> tags.
+type Hit struct {
+	ID         string
+	Score      float64
+	Highlights map[string]string
+}
+
+type indexedDoc struct {
+	id      string
+	fields  []string
+	lengths []float64
+}
+
+type posting struct {
+	doc int
+	tf  []float64 // per field
+}
+
+// Index is an inverted index over a fixed set of fields. It is not safe for
+// concurrent use while documents are being added; once built it may be
+// searched concurrently.
+type Index struct {
+	fields      []Field
+	docs        []indexedDoc
+	postings    map[string][]posting
+	totalLength []float64
+}
+
+// NewIndex creates an empty index over fields.
+func NewIndex(fields ...Field) *Index {
+	return &Index{
+		fields:      fields,
+		postings:    make(map[string][]posting),
+		totalLength: make([]float64, len(fields)),
+	}
+}
+
+// Add indexes a document.
+func (ix *Index) Add(doc Document) {
+	docIndex := len(ix.docs)
+	indexed := indexedDoc{
+		id:      doc.ID,
+		fields:  make([]string, len(ix.fields)),
+		lengths: make([]float64, len(ix.fields)),
+	}
+
+	frequencies := make(map[string][]float64)
+	for i, field := range ix.fields {
+		text := doc.Fields[field.Name]
+		indexed.fields[i] = text
+
+		terms := Analyze(text)
+		indexed.lengths[i] = float64(len(terms))
+		ix.totalLength[i] += float64(len(terms))
+		for _, term := range terms {
+			if frequencies[term] == nil {
+				frequencies[term] = make([]float64, len(ix.fields))
+			}
+			frequencies[term][i]++
+		}
+	}
+
+	for term, tf := range frequencies {
+		ix.postings[term] = append(ix.postings[term], posting{doc: docIndex, tf: tf})
+	}
+	ix.docs = append(ix.docs, indexed)
+}
+
+// Len returns the number of indexed documents.
+func (ix *Index) Len() int {
+	return len(ix.docs)
+}
+
+// All returns every document in the order it was added, unscored.
+func (ix *Index) All() []Hit {
+	hits := make([]Hit, len(ix.docs))
+	for i, doc := range ix.docs {
+		hits[i] = Hit{ID: doc.id}
+	}
+	return hits
+}
+
+// Search returns the documents matching query, best first. Query terms are
+// expanded with their synonyms, which count for less than the terms typed.
+func (ix *Index) Search(query string) []Hit {
+	terms := expandQuery(Analyze(query))
+	if len(terms) == 0 || len(ix.docs) == 0 {
+		return nil
+	}
+
+	n := float64(len(ix.docs))
+	averageLength := make([]float64, len(ix.fields))
+	for i := range ix.fields {
+		averageLength[i] = math.Max(ix.totalLength[i]/n, 1)
+	}
+
+	// Terms are scored in a fixed order so a query's floating-point sums,
+	// and so its scores and ties, are the same on every search.
+	ordered := make([]string, 0, len(terms))
+	for term := range terms {
+		ordered = append(ordered, term)
+	}
+	sort.Strings(ordered)
+
+	scores := make(map[int]float64)
+	matched := make(map[int]map[string]bool)
+	for _, term := range ordered {
+		weight := terms[term]
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for _, p := range postings {
+			var tf float64
+			for i, field := range ix.fields {
+				if p.tf[i] == 0 {
+					continue
+				}
+				norm := 1 - b + b*ix.docs[p.doc].lengths[i]/averageLength[i]
+				tf += field.Weight * p.tf[i] / norm
+			}
+			scores[p.doc] += weight * idf * tf / (k1 + tf)
+
+			if matched[p.doc] == nil {
+				matched[p.doc] = make(map[string]bool)
+			}
+			matched[p.doc][term] = true
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for docIndex, score := range scores {
+		doc := ix.docs[docIndex]
+		hits = append(hits, Hit{
+			ID:         doc.id,
+			Score:      score,
+			Highlights: ix.highlights(doc, matched[docIndex]),
+		})
+	}
+
+	order := make(map[string]int, len(scores))
+	for docIndex := range scores {
+		order[ix.docs[docIndex].id] = docIndex
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return order[hits[i].ID] < order[hits[j].ID]
+	})
+	return hits
+}
+
+func (ix *Index) highlights(doc indexedDoc, terms map[string]bool) map[string]string {
+	highlights := make(map[string]string)
+	for i, field := range ix.fields {
+		if snippet, ok := Highlight(doc.fields[i], terms); ok {
+			highlights[field.Name] = snippet
+		}
+	}
+	return highlights
+}
+
+// expandQuery weights the analysed query terms and adds their synonyms.
+func expandQuery(terms []string) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, term := range terms {
+		weights[term] = 1
+	}
+	for _, term := range terms {
+		for _, synonym := range synonyms[term] {
+			if _, ok := weights[synonym]; !ok {
+				weights[synonym] = synonymWeight
+			}
+		}
+	}
+	return weights
+}
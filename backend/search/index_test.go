@@ -0,0 +1,96 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex(Field{Name: "title", Weight: 2}, Field{Name: "body", Weight: 1})
+	for _, doc := range []Document{
+		{ID: "theft", Fields: map[string]string{"title": "Theft", "body": "Whoever takes movable property out of the possession of another"}},
+		{ID: "robbery", Fields: map[string]string{"title": "Robbery", "body": "Theft is robbery if the offender causes hurt while committing the theft"}},
+		{ID: "cheating", Fields: map[string]string{"title": "Cheating", "body": "Whoever deceives any person and induces delivery of property"}},
+		{ID: "fraud", Fields: map[string]string{"title": "Fraud", "body": "Dishonest concealment of property"}},
+		{ID: "murder", Fields: map[string]string{"title": "Murder", "body": "Culpable homicide is murder if the act is done with intention of causing death"}},
+	} {
+		ix.Add(doc)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"title match ranks above body", "theft", []string{"theft", "robbery"}},
+		{"exact term ranks above synonym", "fraud", []string{"fraud", "cheating"}},
+		{"synonym alone", "deception", []string{"cheating", "fraud"}},
+		{"rarer term and shorter field count for more", "property homicide", []string{"murder", "fraud", "cheating", "theft"}},
+		{"only stop words", "the and with", nil},
+		{"no match", "defamation", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := ix.Search(tt.query)
+			var got []string
+			if hits != nil {
+				got = []string{}
+			}
+			for _, hit := range hits {
+				got = append(got, hit.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSearchHighlights(t *testing.T) {
+	ix := NewIndex(Field{Name: "title", Weight: 2}, Field{Name: "body", Weight: 1})
+	ix.Add(Document{ID: "1", Fields: map[string]string{"title": "Cheating", "body": "Whoever cheats shall be punished"}})
+
+	hits := ix.Search("fraud")
+	if len(hits) != 1 {
+		t.Fatalf("Search() returned %d hits, want 1", len(hits))
+	}
+	want := map[string]string{
+		"title": "<mark>Cheating</mark>",
+		"body":  "Whoever <mark>cheats</mark> shall be punished",
+	}
+	if !reflect.DeepEqual(hits[0].Highlights, want) {
+		t.Errorf("Highlights = %q, want %q", hits[0].Highlights, want)
+	}
+}
+
+func TestIndexSearchTiesKeepInsertionOrder(t *testing.T) {
+	ix := NewIndex(Field{Name: "title", Weight: 1})
+	for _, id := range []string{"c", "a", "b"} {
+		ix.Add(Document{ID: id, Fields: map[string]string{"title": "Dowry death"}})
+	}
+
+	var got []string
+	for _, hit := range ix.Search("dowry") {
+		got = append(got, hit.ID)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() order = %q, want %q", got, want)
+	}
+}
+
+func TestIndexSearchScoresAreRepeatable(t *testing.T) {
+	ix := NewIndex(Field{Name: "title", Weight: 2}, Field{Name: "body", Weight: 1})
+	ix.Add(Document{ID: "a", Fields: map[string]string{"title": "Cheating by personation", "body": "Whoever cheats by pretending to be some other person, or deceives by fraud, dishonestly inducing delivery of property"}})
+	ix.Add(Document{ID: "b", Fields: map[string]string{"title": "Theft", "body": "Whoever dishonestly takes movable property"}})
+
+	query := "cheating fraud deception dishonest property person theft"
+	first := ix.Search(query)
+	for i := 0; i < 50; i++ {
+		hits := ix.Search(query)
+		for j := range hits {
+			if hits[j].ID != first[j].ID || hits[j].Score != first[j].Score {
+				t.Fatalf("search %d: hit %d = %s %v, want %s %v", i, j, hits[j].ID, hits[j].Score, first[j].ID, first[j].Score)
+			}
+		}
+	}
+}
@@ -2,93 +2,290 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
+	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/models"
+)
 
-	"github.com/sashabaranov/go-openai"
+// Retrieval limits for grounding suggestions in the legal database.
+const (
+	sectionCandidateLimit  = 12
+	caseLawCandidateLimit  = 3
+	keywordSuggestionLimit = 5
 )
 
 type AIService struct {
-	client *openai.Client
+	llm        LLMProvider
+	translator Translator
+	legal      *LegalService
 }
 
-func NewAIService() *AIService {
-	// In production, get API key from config
+func NewAIService(cfg *config.Config) *AIService {
 	return &AIService{
-		client: openai.NewClient(""), // Add your OpenAI API key here
+		llm:        NewLLMProvider(cfg),
+		translator: NewTranslator(cfg),
+		legal:      NewLegalService(),
+	}
+}
+
+// EnglishText detects the language text is written in and translates it to
+// English. hint is the declared language, as a name or ISO code. It returns
+// the detected ISO 639-1 code; if detection fails, the hint is assumed.
+// English text is returned unchanged.
+func (s *AIService) EnglishText(ctx context.Context, text, hint string) (english, language string, err error) {
+	hint = languageCode(hint)
+	language, err = s.translator.Detect(ctx, text, hint)
+	if err != nil {
+		log.Printf("Language detection with %s failed: %v", s.translator.Name(), err)
+	}
+	if language == "" {
+		language = hint
+	}
+	if language == "" || language == "en" {
+		return text, "en", nil
+	}
+
+	english, err = s.translator.Translate(ctx, text, language, "en")
+	if err != nil {
+		return "", language, err
+	}
+	return english, language, nil
+}
+
+// AnalyzeIncident retrieves candidate sections and case laws from the legal
+// database and asks the LLM for a structured analysis that may only cite
+// those candidates. Candidates are aligned to the codes in force on
+// incidentDate, and each suggestion lists its equivalents in the other code.
+// If the model is unavailable or keeps returning unusable output, the
+// keyword analyzer is used instead, so callers always get a usable result.
+func (s *AIService) AnalyzeIncident(ctx context.Context, description string, incidentDate time.Time) (models.AIAnalysis, []models.SuggestedLaw) {
+	candidates, err := s.legal.RetrieveSections(ctx, description, s.embedder(), sectionCandidateLimit)
+	if err != nil {
+		log.Printf("Section retrieval failed: %v", err)
+	}
+	candidates = s.alignToCodes(ctx, candidates, CodesForDate(incidentDate))
+	caseLaws, err := s.legal.RetrieveCaseLaws(ctx, description, candidates, caseLawCandidateLimit)
+	if err != nil {
+		log.Printf("Case law retrieval failed: %v", err)
+	}
+
+	analysis, suggestedLaws, err := s.analyzeWithLLM(ctx, description, candidates)
+	if err != nil {
+		if !errors.Is(err, ErrLLMDisabled) {
+			log.Printf("Structured analysis failed, using keyword analyzer: %v", err)
+		}
+		analysis, suggestedLaws = s.keywordAnalysis(description, candidates)
+	}
+
+	for i := range suggestedLaws {
+		suggestedLaws[i].Equivalents = s.legal.EquivalentSections(suggestedLaws[i].Act, suggestedLaws[i].Section)
+	}
+
+	for _, candidate := range caseLaws {
+		analysis.RelevantCaseLaws = append(analysis.RelevantCaseLaws, models.CaseLaw{
+			ID:        candidate.CaseLaw.ID,
+			Title:     candidate.CaseLaw.Title,
+			Court:     candidate.CaseLaw.Court,
+			Year:      candidate.CaseLaw.Year,
+			Relevance: candidate.Score,
+			Summary:   candidate.CaseLaw.Summary,
+		})
+	}
+
+	return analysis, suggestedLaws
+}
+
+// alignToCodes replaces candidates from a code that was not in force with
+// their counterparts in the code that was, e.g. IPC 420 becomes BNS 318 for
+// an incident after 1 July 2024. Counterparts inherit the best score of the
+// sections they replace. Acts outside the 2024 reform are kept as they are.
+func (s *AIService) alignToCodes(ctx context.Context, candidates []SectionCandidate, codes models.CriminalCodes) []SectionCandidate {
+	inForce := map[string]bool{codes.Penal: true, codes.Procedure: true, codes.Evidence: true}
+
+	var aligned []SectionCandidate
+	seen := make(map[string]bool)
+	wanted := make(map[string]map[string]float64) // act -> base section -> score
+	for _, candidate := range candidates {
+		act := candidate.Section.Act
+		if inForce[act] || counterpartAct(act) == "" {
+			if !seen[candidate.Section.ID.Hex()] {
+				seen[candidate.Section.ID.Hex()] = true
+				aligned = append(aligned, candidate)
+			}
+			continue
+		}
+
+		for _, ref := range s.legal.EquivalentSections(act, candidate.Section.Section) {
+			if wanted[ref.Act] == nil {
+				wanted[ref.Act] = make(map[string]float64)
+			}
+			base := baseSection(ref.Section)
+			wanted[ref.Act][base] = math.Max(wanted[ref.Act][base], candidate.Score)
+		}
+	}
+
+	for act, scores := range wanted {
+		var numbers []string
+		for number := range scores {
+			numbers = append(numbers, number)
+		}
+
+		sections, err := s.legal.FindSections(ctx, act, numbers)
+		if err != nil {
+			log.Printf("Failed to load %s counterparts: %v", act, err)
+			continue
+		}
+		for _, section := range sections {
+			if seen[section.ID.Hex()] {
+				continue
+			}
+			seen[section.ID.Hex()] = true
+			aligned = append(aligned, SectionCandidate{Section: section, Score: scores[baseSection(section.Section)]})
+		}
+	}
+
+	sort.SliceStable(aligned, func(i, j int) bool {
+		return aligned[i].Score > aligned[j].Score
+	})
+	return aligned
+}
+
+// IndexEmbeddings embeds legal sections that have no embedding yet. It is a
+// no-op when the configured provider cannot produce embeddings.
+func (s *AIService) IndexEmbeddings(ctx context.Context) (int, error) {
+	embedder := s.embedder()
+	if embedder == nil {
+		return 0, nil
+	}
+	return s.legal.IndexSectionEmbeddings(ctx, embedder)
+}
+
+func (s *AIService) embedder() Embedder {
+	if embedder, ok := s.llm.(Embedder); ok {
+		return embedder
 	}
+	return nil
 }
 
-func (s *AIService) AnalyzeIncident(description string) (models.AIAnalysis, []models.SuggestedLaw) {
-	// For demo purposes, return mock data
-	// In production, this would call OpenAI API or other LLM
-	
+// keywordAnalysis is the deterministic analyzer used when no LLM is
+// configured or the LLM output could not be used. Its suggestions are the
+// top retrieved sections in retrieval order.
+func (s *AIService) keywordAnalysis(description string, candidates []SectionCandidate) (models.AIAnalysis, []models.SuggestedLaw) {
 	analysis := models.AIAnalysis{
 		Confidence:  s.calculateConfidence(description),
 		KeyEntities: s.extractEntities(description),
 		CrimeType:   s.determineCrimeType(description),
-		RelevantCaseLaws: []models.CaseLaw{
-			{
-				Title:     "State of Punjab vs Gurmit Singh",
-				Court:     "Supreme Court of India",
-				Year:      "1996",
-				Relevance: 0.85,
-				Summary:   "Definition and scope of outraging modesty under Section 354 IPC",
-			},
-		},
 		Recommendations: []string{
 			"Ensure all witness statements are recorded accurately",
 			"Consider collecting additional evidence for stronger case",
 			"Verify jurisdiction and applicable state amendments",
 		},
+		Source:      models.AnalysisSourceKeyword,
+		ProcessedAt: time.Now(),
 	}
 
-	suggestedLaws := s.getSuggestedLaws(description)
+	var suggestedLaws []models.SuggestedLaw
+	for i, candidate := range candidates {
+		if i == keywordSuggestionLimit {
+			break
+		}
+		confidence := math.Round(candidate.Score * 100)
+		suggestedLaws = append(suggestedLaws, suggestionFromSection(candidate.Section, confidence, relevanceForConfidence(confidence)))
+	}
 
 	return analysis, suggestedLaws
 }
 
-func (s *AIService) GenerateFIRDocument(req models.GenerateFIRRequest) (string, error) {
-	// Mock FIR generation - in production, use OpenAI API
-	template := `
-FIRST INFORMATION REPORT
-(Under Section 154 of the Code of Criminal Procedure, 1973)
-
-Police Station: %s
-Date: %s
-
-COMPLAINANT DETAILS:
-Name: %s
-
-INCIDENT DETAILS:
-Date of Incident: %s
-Description: %s
+// suggestionFromSection builds a SuggestedLaw whose section, act and
+// description come from the database record rather than the model.
+func suggestionFromSection(section models.LegalSection, confidence float64, relevance string) models.SuggestedLaw {
+	return models.SuggestedLaw{
+		SectionID:   section.ID,
+		Section:     section.Section,
+		Act:         section.Act,
+		Description: section.Title,
+		Confidence:  confidence,
+		Relevance:   relevance,
+	}
+}
 
-APPLICABLE SECTIONS:
-Based on AI analysis, the following sections may be applicable:
-- Section 354 of Indian Penal Code
-- Section 509 of Indian Penal Code
+// TranslateDocument adds a translation of a rendered document into
+// language, paragraph by paragraph. When language is the one the
+// complainant spoke, their own words, original, are used in place of a
+// translation of the drafted narrative.
+func (s *AIService) TranslateDocument(ctx context.Context, document *models.GeneratedFIR, narrative, original, sourceLanguage, language string) error {
+	type paragraph struct {
+		english    string
+		translated string
+	}
+	var paragraphs []paragraph
+	split := func(text string) {
+		for _, p := range strings.Split(text, "\n\n") {
+			if p = strings.TrimSpace(p); p != "" {
+				paragraphs = append(paragraphs, paragraph{english: p})
+			}
+		}
+	}
 
-This FIR has been generated with AI assistance and should be reviewed by the investigating officer.
-	`
+	at := -1
+	if language == sourceLanguage && narrative != "" && strings.TrimSpace(original) != "" {
+		at = strings.Index(document.Text, narrative)
+	}
+	if at >= 0 {
+		split(document.Text[:at])
+		paragraphs = append(paragraphs, paragraph{english: narrative, translated: strings.TrimSpace(original)})
+		split(document.Text[at+len(narrative):])
+	} else {
+		split(document.Text)
+	}
 
-	generatedFIR := fmt.Sprintf(template,
-		req.IncidentLocation,
-		req.IncidentDate,
-		req.ComplainantName,
-		req.IncidentDate,
-		req.IncidentDescription,
-	)
+	document.Language = language
+	document.Sections = nil
+	var translated []string
+	for _, p := range paragraphs {
+		if p.translated == "" {
+			text, err := s.translator.Translate(ctx, p.english, "en", language)
+			if err != nil {
+				return err
+			}
+			p.translated = text
+		}
+		document.Sections = append(document.Sections, models.GeneratedFIRSection{English: p.english, Translated: p.translated})
+		translated = append(translated, p.translated)
+	}
+	document.TranslatedText = strings.Join(translated, "\n\n")
+	return nil
+}
 
-	return strings.TrimSpace(generatedFIR), nil
+// firRegistrationProvision names the provision an FIR is registered under.
+// Procedure follows the date of registration, not of the incident.
+func firRegistrationProvision(registeredAt time.Time) string {
+	if CodesForDate(registeredAt).Procedure == models.ActBNSS {
+		return "Section 173 of the Bharatiya Nagarik Suraksha Sanhita, 2023"
+	}
+	return "Section 154 of the Code of Criminal Procedure, 1973"
 }
 
-func (s *AIService) TranscribeAudio(audioData []byte) (string, error) {
-	// Mock transcription - in production, use Google Speech API or Whisper
-	return "This is a mock transcription of the recorded audio. In a real application, this would be processed by your speech-to-text service.", nil
+// formatLaw describes a suggested section and its equivalents in the other
+// code, for example "Section 304 of Bharatiya Nyaya Sanhita (corresponds
+// to Section 379 of Indian Penal Code)".
+func formatLaw(law models.SuggestedLaw) string {
+	text := fmt.Sprintf("Section %s of %s", law.Section, law.Act)
+	var equivalents []string
+	for _, ref := range law.Equivalents {
+		equivalents = append(equivalents, fmt.Sprintf("Section %s of %s", ref.Section, ref.Act))
+	}
+	if len(equivalents) > 0 {
+		text += " (corresponds to " + strings.Join(equivalents, ", ") + ")"
+	}
+	return text
 }
 
 func (s *AIService) calculateConfidence(description string) float64 {
@@ -104,7 +301,7 @@ func (s *AIService) calculateConfidence(description string) float64 {
 func (s *AIService) extractEntities(description string) []string {
 	// Mock entity extraction
 	entities := []string{"person", "location", "incident"}
-	
+
 	desc := strings.ToLower(description)
 	if strings.Contains(desc, "woman") || strings.Contains(desc, "girl") {
 		entities = append(entities, "female victim")
@@ -112,13 +309,13 @@ func (s *AIService) extractEntities(description string) []string {
 	if strings.Contains(desc, "phone") || strings.Contains(desc, "mobile") {
 		entities = append(entities, "electronic device")
 	}
-	
+
 	return entities
 }
 
 func (s *AIService) determineCrimeType(description string) string {
 	desc := strings.ToLower(description)
-	
+
 	if strings.Contains(desc, "assault") || strings.Contains(desc, "attack") {
 		return "assault"
 	}
@@ -128,73 +325,33 @@ func (s *AIService) determineCrimeType(description string) string {
 	if strings.Contains(desc, "fraud") || strings.Contains(desc, "cheat") {
 		return "fraud"
 	}
-	
+
 	return "miscellaneous"
 }
 
-func (s *AIService) getSuggestedLaws(description string) []models.SuggestedLaw {
-	// Mock law suggestions based on keywords
-	desc := strings.ToLower(description)
-	var laws []models.SuggestedLaw
-
-	if strings.Contains(desc, "woman") || strings.Contains(desc, "modesty") {
-		laws = append(laws, models.SuggestedLaw{
-			Section:     "354",
-			Act:         "Indian Penal Code",
-			Description: "Assault or criminal force to woman with intent to outrage her modesty",
-			Confidence:  85.0,
-			Relevance:   "high",
-		})
-		laws = append(laws, models.SuggestedLaw{
-			Section:     "509",
-			Act:         "Indian Penal Code",
-			Description: "Word, gesture or act intended to insult the modesty of a woman",
-			Confidence:  78.0,
-			Relevance:   "medium",
-		})
-	}
-
-	if strings.Contains(desc, "theft") || strings.Contains(desc, "steal") {
-		laws = append(laws, models.SuggestedLaw{
-			Section:     "379",
-			Act:         "Indian Penal Code",
-			Description: "Punishment for theft",
-			Confidence:  90.0,
-			Relevance:   "high",
-		})
+// draftNarrative asks the LLM to restate the complainant's account in the
+// formal register of an FIR. The original text is used when no model is
+// available or the call fails.
+func (s *AIService) draftNarrative(ctx context.Context, description string) string {
+	narrative, err := s.complete(ctx, CompletionRequest{
+		System: "You are assisting an Indian police officer in drafting a First Information Report. " +
+			"Rewrite the complainant's account as a clear, factual, third-person narrative. " +
+			"Do not add facts, names, dates or legal sections that are not in the account.",
+		Prompt:      description,
+		Temperature: 0.2,
+	})
+	if err != nil || strings.TrimSpace(narrative) == "" {
+		return description
 	}
-
-	if strings.Contains(desc, "fraud") || strings.Contains(desc, "cheat") {
-		laws = append(laws, models.SuggestedLaw{
-			Section:     "420",
-			Act:         "Indian Penal Code",
-			Description: "Cheating and dishonestly inducing delivery of property",
-			Confidence:  88.0,
-			Relevance:   "high",
-		})
-	}
-
-	return laws
+	return strings.TrimSpace(narrative)
 }
 
-// Real OpenAI integration (for when API key is available)
-func (s *AIService) callOpenAI(prompt string) (string, error) {
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-		},
-	)
-
-	if err != nil {
-		return "", err
+// complete sends a prompt to the configured LLM provider. Failures other than
+// a disabled provider are logged so a misconfigured backend is visible.
+func (s *AIService) complete(ctx context.Context, req CompletionRequest) (string, error) {
+	resp, err := s.llm.Complete(ctx, req)
+	if err != nil && !errors.Is(err, ErrLLMDisabled) {
+		log.Printf("LLM request to %s (%s) failed: %v", s.llm.Name(), s.llm.Model(), err)
 	}
-
-	return resp.Choices[0].Message.Content, nil
-}
\ No newline at end of file
+	return resp, err
+}
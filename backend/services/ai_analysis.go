@@ -0,0 +1,274 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/models"
+)
+
+// maxAnalysisAttempts bounds how many times the LLM is asked for a valid
+// analysis before falling back to the keyword analyzer.
+const maxAnalysisAttempts = 2
+
+const analysisSystemPrompt = `You are a legal analysis assistant for Indian police officers drafting First Information Reports.
+Analyse the incident and reply with a single JSON object and nothing else. The object must have exactly these fields:
+
+{
+  "crime_type": string,            // short lowercase label, e.g. "theft", "assault", "cheating"
+  "key_entities": [string],        // people, places, objects and amounts mentioned in the incident
+  "suggested_sections": [          // candidate sections that apply, most relevant first
+    {
+      "section_id": string,        // the id of a section from the candidate list, copied exactly
+      "confidence": number,        // 0 to 100, a percentage rather than a fraction
+      "relevance": string          // "high", "medium" or "low"
+    }
+  ],
+  "confidence": number,            // 0 to 100, overall confidence in the analysis as a percentage
+  "recommendations": [string]      // concrete next steps for the investigating officer
+}
+
+Only use facts present in the incident description. Only suggest sections from the candidate list; if none apply,
+return an empty suggested_sections array. Do not include comments in the JSON.`
+
+// llmAnalysis is the JSON document the LLM is asked to produce.
+type llmAnalysis struct {
+	CrimeType         string          `json:"crime_type"`
+	KeyEntities       []string        `json:"key_entities"`
+	SuggestedSections []llmSuggestion `json:"suggested_sections"`
+	Confidence        float64         `json:"confidence"`
+	Recommendations   []string        `json:"recommendations"`
+}
+
+type llmSuggestion struct {
+	SectionID  string  `json:"section_id"`
+	Confidence float64 `json:"confidence"`
+	Relevance  string  `json:"relevance"`
+}
+
+// analyzeWithLLM requests a structured analysis and decodes it. The model
+// re-ranks the retrieved candidates and may not cite anything else. Output
+// that cannot be repaired is fed back to the model with the validation error
+// so it can correct itself.
+func (s *AIService) analyzeWithLLM(ctx context.Context, description string, candidates []SectionCandidate) (models.AIAnalysis, []models.SuggestedLaw, error) {
+	sectionsByID := make(map[string]models.LegalSection, len(candidates))
+	for _, candidate := range candidates {
+		sectionsByID[candidate.Section.ID.Hex()] = candidate.Section
+	}
+
+	basePrompt := "Incident description:\n" + description + "\n\n" + formatCandidates(candidates)
+	prompt := basePrompt
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAnalysisAttempts; attempt++ {
+		if lastErr != nil {
+			prompt = fmt.Sprintf("%s\n\nYour previous reply was rejected: %v\n"+
+				"Reply again with only the JSON object described in the instructions.", basePrompt, lastErr)
+		}
+
+		raw, err := s.complete(ctx, CompletionRequest{
+			System:      analysisSystemPrompt,
+			Prompt:      prompt,
+			Temperature: 0,
+			JSON:        true,
+		})
+		if err != nil {
+			return models.AIAnalysis{}, nil, err
+		}
+
+		parsed, err := parseAnalysis(raw, sectionsByID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		analysis := models.AIAnalysis{
+			Confidence:      parsed.Confidence,
+			KeyEntities:     parsed.KeyEntities,
+			CrimeType:       parsed.CrimeType,
+			Recommendations: parsed.Recommendations,
+			Source:          models.AnalysisSourceLLM,
+			Model:           s.llm.Model(),
+			ProcessedAt:     time.Now(),
+		}
+
+		var laws []models.SuggestedLaw
+		for _, suggestion := range parsed.SuggestedSections {
+			section := sectionsByID[suggestion.SectionID]
+			laws = append(laws, suggestionFromSection(section, suggestion.Confidence, suggestion.Relevance))
+		}
+
+		return analysis, laws, nil
+	}
+
+	return models.AIAnalysis{}, nil, fmt.Errorf("no valid analysis after %d attempts: %w", maxAnalysisAttempts, lastErr)
+}
+
+// formatCandidates lists the retrieved sections for the prompt.
+func formatCandidates(candidates []SectionCandidate) string {
+	if len(candidates) == 0 {
+		return "Candidate sections: none were found in the legal database."
+	}
+
+	var b strings.Builder
+	b.WriteString("Candidate sections (id | act | section | title):\n")
+	for _, candidate := range candidates {
+		section := candidate.Section
+		fmt.Fprintf(&b, "%s | %s | %s | %s\n", section.ID.Hex(), section.Act, section.Section, section.Title)
+	}
+	return b.String()
+}
+
+// parseAnalysis repairs common formatting problems in raw model output,
+// decodes it strictly and validates the result against the candidates.
+func parseAnalysis(raw string, sectionsByID map[string]models.LegalSection) (*llmAnalysis, error) {
+	repaired := repairJSON(raw)
+	if repaired == "" {
+		return nil, errors.New("reply did not contain a JSON object")
+	}
+
+	decoder := json.NewDecoder(bytes.NewBufferString(repaired))
+	decoder.DisallowUnknownFields()
+
+	var parsed llmAnalysis
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if err := parsed.validate(sectionsByID); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+var (
+	codeFencePattern     = regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)```")
+	trailingCommaPattern = regexp.MustCompile(`,\s*([}\]])`)
+)
+
+// repairJSON extracts the JSON object from a reply that may be wrapped in
+// markdown fences or surrounding prose, and drops trailing commas.
+func repairJSON(raw string) string {
+	text := strings.TrimSpace(raw)
+	if match := codeFencePattern.FindStringSubmatch(text); match != nil {
+		text = strings.TrimSpace(match[1])
+	}
+
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end < start {
+		return ""
+	}
+	text = text[start : end+1]
+
+	return trailingCommaPattern.ReplaceAllString(text, "$1")
+}
+
+// validate checks required fields, rejects sections outside the candidate
+// set and normalises values the model commonly gets slightly wrong, such as
+// the case of relevance labels. Confidences must be on the 0-100 scale the
+// prompt asks for; a reply that gives them as fractions is rejected so the
+// model is asked again, rather than guessing which scale each value is on.
+// Repeated sections keep their first position.
+func (a *llmAnalysis) validate(sectionsByID map[string]models.LegalSection) error {
+	a.CrimeType = strings.ToLower(strings.TrimSpace(a.CrimeType))
+	if a.CrimeType == "" {
+		return errors.New("crime_type is required")
+	}
+
+	if err := checkConfidence(a.Confidence); err != nil {
+		return fmt.Errorf("confidence: %w", err)
+	}
+	confidences := []float64{a.Confidence}
+
+	a.KeyEntities = compactStrings(a.KeyEntities)
+	a.Recommendations = compactStrings(a.Recommendations)
+	if len(a.Recommendations) == 0 {
+		return errors.New("recommendations must contain at least one entry")
+	}
+
+	seen := make(map[string]bool)
+	suggestions := a.SuggestedSections[:0]
+	for i, suggestion := range a.SuggestedSections {
+		suggestion.SectionID = strings.TrimSpace(suggestion.SectionID)
+		if _, ok := sectionsByID[suggestion.SectionID]; !ok {
+			return fmt.Errorf("suggested_sections[%d].section_id %q is not in the candidate list", i, suggestion.SectionID)
+		}
+		if seen[suggestion.SectionID] {
+			continue
+		}
+		seen[suggestion.SectionID] = true
+
+		if err := checkConfidence(suggestion.Confidence); err != nil {
+			return fmt.Errorf("suggested_sections[%d].confidence: %w", i, err)
+		}
+		confidences = append(confidences, suggestion.Confidence)
+
+		suggestion.Relevance = strings.ToLower(strings.TrimSpace(suggestion.Relevance))
+		switch suggestion.Relevance {
+		case "high", "medium", "low":
+		case "":
+			suggestion.Relevance = relevanceForConfidence(suggestion.Confidence)
+		default:
+			return fmt.Errorf("suggested_sections[%d].relevance must be high, medium or low", i)
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+	a.SuggestedSections = suggestions
+
+	if fractionalConfidences(confidences) {
+		return errors.New("confidences must be on the 0 to 100 scale, not 0 to 1")
+	}
+	return nil
+}
+
+func checkConfidence(value float64) error {
+	if value < 0 || value > 100 {
+		return fmt.Errorf("%v is outside 0-100", value)
+	}
+	return nil
+}
+
+// fractionalConfidences reports whether a reply's confidences look like
+// they were given on a 0-1 scale: none is above 1 and at least one is a
+// fraction between 0 and 1.
+func fractionalConfidences(values []float64) bool {
+	fraction := false
+	for _, value := range values {
+		if value > 1 {
+			return false
+		}
+		if value > 0 && value < 1 {
+			fraction = true
+		}
+	}
+	return fraction
+}
+
+func relevanceForConfidence(confidence float64) string {
+	switch {
+	case confidence >= 80:
+		return "high"
+	case confidence >= 50:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func compactStrings(values []string) []string {
+	var out []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"plain object", `{"a": 1}`, `{"a": 1}`},
+		{"surrounding prose", `Here is the analysis: {"a": 1} Hope this helps.`, `{"a": 1}`},
+		{"json fence", "```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"bare fence", "Sure.\n```\n{\"a\": 1}\n```\nDone.", `{"a": 1}`},
+		{"trailing commas", `{"a": [1, 2,], "b": {"c": 3,},}`, `{"a": [1, 2], "b": {"c": 3}}`},
+		{"nested braces", `x {"a": {"b": 1}} y`, `{"a": {"b": 1}}`},
+		{"no object", "I cannot help with that.", ""},
+		{"closing brace first", "} {", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repairJSON(tt.raw); got != tt.want {
+				t.Errorf("repairJSON(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAnalysis(t *testing.T) {
+	theft := models.LegalSection{ID: primitive.NewObjectID(), Act: models.ActBNS, Section: "303", Title: "Theft"}
+	cheating := models.LegalSection{ID: primitive.NewObjectID(), Act: models.ActBNS, Section: "318", Title: "Cheating"}
+	sectionsByID := map[string]models.LegalSection{
+		theft.ID.Hex():    theft,
+		cheating.ID.Hex(): cheating,
+	}
+	reply := func(suggestions, confidence string) string {
+		return `{"crime_type": " Theft ", "key_entities": ["phone", " "], "suggested_sections": [` + suggestions +
+			`], "confidence": ` + confidence + `, "recommendations": ["Record the complainant's statement"]}`
+	}
+	suggestion := func(section models.LegalSection, confidence, relevance string) string {
+		return `{"section_id": "` + section.ID.Hex() + `", "confidence": ` + confidence + `, "relevance": "` + relevance + `"}`
+	}
+
+	tests := []struct {
+		name      string
+		raw       string
+		wantErr   string
+		wantIDs   []string
+		wantLevel []string
+	}{
+		{
+			name:      "valid",
+			raw:       reply(suggestion(theft, "90", "HIGH")+","+suggestion(cheating, "40", ""), "85"),
+			wantIDs:   []string{theft.ID.Hex(), cheating.ID.Hex()},
+			wantLevel: []string{"high", "low"},
+		},
+		{
+			name:      "repeated section keeps first",
+			raw:       reply(suggestion(theft, "70", "")+","+suggestion(theft, "95", "high"), "70"),
+			wantIDs:   []string{theft.ID.Hex()},
+			wantLevel: []string{"medium"},
+		},
+		{
+			name:    "no sections",
+			raw:     "```json\n" + reply("", "60") + "\n```",
+			wantIDs: []string{},
+		},
+		{
+			name:    "unknown section",
+			raw:     reply(suggestion(models.LegalSection{ID: primitive.NewObjectID()}, "90", "high"), "80"),
+			wantErr: "not in the candidate list",
+		},
+		{
+			name:    "fractional scale",
+			raw:     reply(suggestion(theft, "0.9", "high"), "0.8"),
+			wantErr: "0 to 100 scale",
+		},
+		{
+			name:    "out of range",
+			raw:     reply(suggestion(theft, "120", "high"), "80"),
+			wantErr: "outside 0-100",
+		},
+		{
+			name:    "bad relevance",
+			raw:     reply(suggestion(theft, "90", "certain"), "80"),
+			wantErr: "relevance must be",
+		},
+		{
+			name:    "unknown field",
+			raw:     `{"crime_type": "theft", "severity": "high", "recommendations": ["x"]}`,
+			wantErr: "invalid JSON",
+		},
+		{
+			name:    "missing crime type",
+			raw:     `{"crime_type": "", "confidence": 50, "recommendations": ["x"]}`,
+			wantErr: "crime_type is required",
+		},
+		{
+			name:    "no recommendations",
+			raw:     `{"crime_type": "theft", "confidence": 50, "recommendations": [" "]}`,
+			wantErr: "recommendations",
+		},
+		{
+			name:    "not json",
+			raw:     "The incident is a theft.",
+			wantErr: "did not contain a JSON object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseAnalysis(tt.raw, sectionsByID)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseAnalysis() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAnalysis() error = %v", err)
+			}
+			if parsed.CrimeType != "theft" {
+				t.Errorf("CrimeType = %q, want %q", parsed.CrimeType, "theft")
+			}
+			if len(parsed.KeyEntities) != 1 {
+				t.Errorf("KeyEntities = %q, want blank entries dropped", parsed.KeyEntities)
+			}
+			if len(parsed.SuggestedSections) != len(tt.wantIDs) {
+				t.Fatalf("got %d suggestions, want %d", len(parsed.SuggestedSections), len(tt.wantIDs))
+			}
+			for i, suggestion := range parsed.SuggestedSections {
+				if suggestion.SectionID != tt.wantIDs[i] {
+					t.Errorf("suggestion %d = %s, want %s", i, suggestion.SectionID, tt.wantIDs[i])
+				}
+				if suggestion.Relevance != tt.wantLevel[i] {
+					t.Errorf("suggestion %d relevance = %q, want %q", i, suggestion.Relevance, tt.wantLevel[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConfidence(t *testing.T) {
+	tests := []struct {
+		value   float64
+		wantErr bool
+	}{
+		{0, false},
+		{0.5, false},
+		{1, false},
+		{55, false},
+		{100, false},
+		{-1, true},
+		{100.5, true},
+	}
+	for _, tt := range tests {
+		if err := checkConfidence(tt.value); (err != nil) != tt.wantErr {
+			t.Errorf("checkConfidence(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFractionalConfidences(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   bool
+	}{
+		{"percentages", []float64{85, 90, 40}, false},
+		{"fractions", []float64{0.85, 0.9, 0.4}, true},
+		{"fractions with a one", []float64{1, 0.6}, true},
+		{"percentage with a small value", []float64{80, 0.5}, false},
+		{"zeros and ones", []float64{0, 1, 1}, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fractionalConfidences(tt.values); got != tt.want {
+				t.Errorf("fractionalConfidences(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AuditService appends records of who read FIR data, and under what
+// scope, to the audit_log collection.
+type AuditService struct {
+	collection string
+}
+
+func NewAuditService() *AuditService {
+	return &AuditService{collection: "audit_log"}
+}
+
+// EnsureIndexes creates the indexes audit queries by user and by FIR use.
+func (s *AuditService) EnsureIndexes(ctx context.Context) error {
+	_, err := database.GetCollection(s.collection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "fir_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
+
+// Record appends an audit entry made by user.
+func (s *AuditService) Record(ctx context.Context, user *models.User, action string, scope models.AccessScope, firID *primitive.ObjectID, results int64) error {
+	_, err := database.GetCollection(s.collection).InsertOne(ctx, models.AuditEntry{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		Role:      user.Role,
+		Action:    action,
+		FIRID:     firID,
+		Scope:     scope,
+		Results:   results,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
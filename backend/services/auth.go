@@ -3,17 +3,25 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"legalassist-ai-backend/database"
 	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
 	"legalassist-ai-backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrUserNotFound is returned when no user has the requested ID.
+var ErrUserNotFound = errors.New("user not found")
+
 type AuthService struct {
 	collection string
 }
@@ -56,8 +64,8 @@ func (s *AuthService) Register(req models.RegisterRequest) (*models.User, error)
 		District:    req.District,
 		State:       req.State,
 		IsActive:    true,
-		Role:        "officer",
-		Permissions: []string{"fir:create", "fir:read", "fir:update"},
+		Role:        policy.RoleOfficer,
+		Permissions: []string{},
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -102,14 +110,16 @@ func (s *AuthService) Login(req models.LoginRequest) (*models.LoginResponse, err
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateJWT(user.ID.Hex(), user.Email, user.Role)
+	// Permissions are resolved now, so requests can be authorised from
+	// the token alone.
+	token, err := utils.GenerateJWT(user.ID.Hex(), user.Email, user.Role, policy.Permissions(user.Role, user.Permissions), user.AccessVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	// Clear password before returning
 	user.Password = ""
-	
+
 	return &models.LoginResponse{
 		Token: token,
 		User:  user,
@@ -136,6 +146,61 @@ func (s *AuthService) GetUserByID(userID string) (*models.User, error) {
 	return &user, nil
 }
 
+// AccessVersion returns a user's current access version, which tokens
+// issued to them must carry.
+func (s *AuthService) AccessVersion(ctx context.Context, userID string) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, ErrUserNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	err = database.GetCollection(s.collection).FindOne(ctx,
+		bson.M{"_id": objectID},
+		options.FindOne().SetProjection(bson.M{"access_version": 1}),
+	).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return user.AccessVersion, nil
+}
+
+// UserIDsPostedIn returns the IDs of users currently posted in the
+// station, district or state of scope.
+func (s *AuthService) UserIDsPostedIn(ctx context.Context, scope models.AccessScope) ([]primitive.ObjectID, error) {
+	filter := bson.M{}
+	switch scope.Level {
+	case models.ScopeStation:
+		filter["station"] = scope.Station
+	case models.ScopeDistrict:
+		filter["state"], filter["district"] = scope.State, scope.District
+	case models.ScopeState:
+		filter["state"] = scope.State
+	default:
+		return nil, nil
+	}
+	cursor, err := database.GetCollection(s.collection).Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids, nil
+}
+
 func (s *AuthService) UpdateProfile(userID string, updates bson.M) error {
 	collection := database.GetCollection(s.collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -154,4 +219,84 @@ func (s *AuthService) UpdateProfile(userID string, updates bson.M) error {
 	)
 
 	return err
-}
\ No newline at end of file
+}
+
+// UpdateAccess sets a user's role, the permissions granted to them on top
+// of it and any part of their posting given in req. The user's existing
+// tokens stop working, so the change takes effect from their next login.
+func (s *AuthService) UpdateAccess(ctx context.Context, userID string, req models.UpdateUserAccessRequest) (*models.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	set, problems := accessUpdates(req)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	set["updated_at"] = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := database.GetCollection(s.collection).UpdateOne(ctx,
+		bson.M{"_id": objectID},
+		bson.M{
+			"$set": set,
+			"$inc": bson.M{"access_version": 1},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrUserNotFound
+	}
+	return s.GetUserByID(userID)
+}
+
+// accessUpdates validates an access request and converts it to a $set
+// document. A posting is entered by the user at registration, so
+// permissions scoped by it need the admin to set or confirm the parts of
+// it they cover: granting them takes those fields in the same request.
+func accessUpdates(req models.UpdateUserAccessRequest) (bson.M, []string) {
+	var problems []string
+	if !policy.ValidRole(req.Role) {
+		problems = append(problems, fmt.Sprintf("unknown role %q", req.Role))
+	}
+	permissions := []string{}
+	for _, permission := range req.Permissions {
+		if !policy.ValidPermission(permission) {
+			problems = append(problems, fmt.Sprintf("unknown permission %q", permission))
+			continue
+		}
+		permissions = append(permissions, permission)
+	}
+	set := bson.M{"role": req.Role, "permissions": permissions}
+
+	needStation, needDistrict, needState := policy.PostingRequired(req.Role, permissions)
+	posting := []struct {
+		field    string
+		value    *string
+		required bool
+	}{
+		{"station", req.Station, needStation},
+		{"district", req.District, needDistrict},
+		{"state", req.State, needState},
+	}
+	for _, p := range posting {
+		if p.value == nil {
+			if p.required {
+				problems = append(problems, fmt.Sprintf("%s is required to confirm the posting these permissions are scoped by", p.field))
+			}
+			continue
+		}
+		value := strings.TrimSpace(*p.value)
+		if value == "" && p.required {
+			problems = append(problems, p.field+" cannot be empty")
+			continue
+		}
+		set[p.field] = value
+	}
+	return set, problems
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAccessUpdates(t *testing.T) {
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		req          models.UpdateUserAccessRequest
+		want         bson.M
+		wantProblems []string
+	}{
+		{
+			"officer without a posting",
+			models.UpdateUserAccessRequest{Role: policy.RoleOfficer},
+			bson.M{"role": policy.RoleOfficer, "permissions": []string{}},
+			nil,
+		},
+		{
+			"officer moved to another station",
+			models.UpdateUserAccessRequest{Role: policy.RoleOfficer, Station: text(" Karol Bagh ")},
+			bson.M{"role": policy.RoleOfficer, "permissions": []string{}, "station": "Karol Bagh"},
+			nil,
+		},
+		{
+			"SHO with a station",
+			models.UpdateUserAccessRequest{Role: policy.RoleSHO, Station: text("Connaught Place")},
+			bson.M{"role": policy.RoleSHO, "permissions": []string{}, "station": "Connaught Place"},
+			nil,
+		},
+		{
+			"SHO without a station",
+			models.UpdateUserAccessRequest{Role: policy.RoleSHO},
+			nil,
+			[]string{"station is required to confirm the posting these permissions are scoped by"},
+		},
+		{
+			"supervisor with an empty district",
+			models.UpdateUserAccessRequest{Role: policy.RoleSupervisor, Station: text("Connaught Place"), District: text(" "), State: text("Delhi")},
+			nil,
+			[]string{"district cannot be empty"},
+		},
+		{
+			"supervisor with a full posting",
+			models.UpdateUserAccessRequest{Role: policy.RoleSupervisor, Station: text("Connaught Place"), District: text("New Delhi"), State: text("Delhi")},
+			bson.M{"role": policy.RoleSupervisor, "permissions": []string{}, "station": "Connaught Place", "district": "New Delhi", "state": "Delhi"},
+			nil,
+		},
+		{
+			"state read granted to an officer",
+			models.UpdateUserAccessRequest{Role: policy.RoleOfficer, Permissions: []string{policy.FIRReadState}},
+			nil,
+			[]string{"state is required to confirm the posting these permissions are scoped by"},
+		},
+		{
+			"admin",
+			models.UpdateUserAccessRequest{Role: policy.RoleAdmin, Permissions: []string{policy.LedgerVerify}},
+			bson.M{"role": policy.RoleAdmin, "permissions": []string{policy.LedgerVerify}},
+			nil,
+		},
+		{
+			"unknown role and permission",
+			models.UpdateUserAccessRequest{Role: "chief", Permissions: []string{"fir:erase"}},
+			nil,
+			[]string{`unknown role "chief"`, `unknown permission "fir:erase"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problems := accessUpdates(tt.req)
+			if !reflect.DeepEqual(problems, tt.wantProblems) {
+				t.Fatalf("accessUpdates() problems = %q, want %q", problems, tt.wantProblems)
+			}
+			if tt.wantProblems == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("accessUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
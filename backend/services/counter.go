@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"legalassist-ai-backend/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CounterService hands out sequence numbers from the counters collection.
+// Each counter is a single document incremented atomically, so concurrent
+// callers never receive the same number.
+type CounterService struct {
+	collection string
+}
+
+func NewCounterService() *CounterService {
+	return &CounterService{
+		collection: "counters",
+	}
+}
+
+// Next increments the counter named key and returns its new value,
+// creating the counter at 1 if it does not exist.
+func (s *CounterService) Next(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := database.GetCollection(s.collection).FindOneAndUpdate(ctx,
+		bson.M{"_id": key},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+// NextFIRNumber returns the next FIR number for a station in the year of
+// at, in the form PS-CODE/2026/000123. Numbering restarts at 1 every year.
+func (s *CounterService) NextFIRNumber(ctx context.Context, station string, at time.Time) (string, error) {
+	code := StationCode(station)
+	if code == "" {
+		return "", fmt.Errorf("station is required to number an FIR")
+	}
+
+	year := at.Year()
+	seq, err := s.Next(ctx, fmt.Sprintf("fir:%s:%d", code, year))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%d/%06d", code, year, seq), nil
+}
+
+// StationCode turns a station name into the code used in its FIR numbers:
+// upper-case letters and digits, with words joined by hyphens, so
+// "Connaught Place" becomes "CONNAUGHT-PLACE".
+func StationCode(station string) string {
+	words := strings.FieldsFunc(strings.ToUpper(station), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
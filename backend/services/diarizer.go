@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/config"
+)
+
+// SpeakerSpan is a stretch of a recording attributed to one speaker. Start
+// and End are in seconds.
+type SpeakerSpan struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker"`
+}
+
+// Diarizer works out who is speaking when in a recording and labels each
+// segment of its transcript with a speaker.
+type Diarizer interface {
+	Name() string
+	Diarize(ctx context.Context, req TranscriptionRequest, transcript *Transcript) error
+}
+
+// NewDiarizer builds the diarizer selected by cfg.DiarizerProvider. When
+// none is configured, a diarizer URL selects the HTTP diarizer, and the fake
+// diarizer is used alongside the fake transcriber. Otherwise it returns nil
+// and segments keep whatever speakers the transcriber reported.
+func NewDiarizer(cfg *config.Config) Diarizer {
+	name := strings.ToLower(cfg.DiarizerProvider)
+	if name == "" {
+		switch {
+		case cfg.DiarizerURL != "":
+			name = "http"
+		case transcriberName(cfg) == "fake":
+			name = "fake"
+		default:
+			name = "none"
+		}
+	}
+
+	switch name {
+	case "http":
+		return NewHTTPDiarizer(cfg.DiarizerURL, cfg.TranscriberTimeout)
+	case "fake":
+		return NewFakeDiarizer()
+	case "none":
+		return nil
+	default:
+		log.Printf("Unknown diarizer %q, diarization disabled", cfg.DiarizerProvider)
+		return nil
+	}
+}
+
+// HTTPDiarizer sends recordings to a diarization service, such as a
+// pyannote.audio wrapper, that accepts a multipart "file" upload and
+// responds with {"segments": [{"start", "end", "speaker"}]}.
+type HTTPDiarizer struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewHTTPDiarizer(url string, timeout time.Duration) *HTTPDiarizer {
+	if timeout <= 0 {
+		timeout = defaultTranscriberTimeout
+	}
+	return &HTTPDiarizer{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (d *HTTPDiarizer) Name() string {
+	return "http"
+}
+
+func (d *HTTPDiarizer) Diarize(ctx context.Context, req TranscriptionRequest, transcript *Transcript) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "audio."+req.Format)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, req.Audio); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, &body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := d.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("diarization failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("diarizer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Segments []SpeakerSpan `json:"segments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("diarization: %w", err)
+	}
+
+	assignSpeakers(transcript.Segments, result.Segments)
+	return nil
+}
+
+// assignSpeakers labels each segment with the speaker whose spans overlap
+// it most. Segments that no span overlaps keep their current speaker.
+func assignSpeakers(segments []TranscriptSegment, spans []SpeakerSpan) {
+	for i := range segments {
+		overlaps := map[string]float64{}
+		best, bestOverlap := "", 0.0
+		for _, span := range spans {
+			overlap := math.Min(segments[i].End, span.End) - math.Max(segments[i].Start, span.Start)
+			if overlap <= 0 {
+				continue
+			}
+			overlaps[span.Speaker] += overlap
+			if overlaps[span.Speaker] > bestOverlap {
+				best, bestOverlap = span.Speaker, overlaps[span.Speaker]
+			}
+		}
+		if best != "" {
+			segments[i].Speaker = best
+		}
+	}
+}
+
+// FakeDiarizer labels segments without analysing the audio, alternating
+// between Speakers one segment at a time, so the fake transcriber's output
+// has turns to map.
+type FakeDiarizer struct {
+	Speakers []string
+}
+
+func NewFakeDiarizer() *FakeDiarizer {
+	return &FakeDiarizer{
+		Speakers: []string{"SPEAKER_1", "SPEAKER_2"},
+	}
+}
+
+func (d *FakeDiarizer) Name() string {
+	return "fake"
+}
+
+func (d *FakeDiarizer) Diarize(ctx context.Context, req TranscriptionRequest, transcript *Transcript) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i := range transcript.Segments {
+		transcript.Segments[i].Speaker = d.Speakers[i%len(d.Speakers)]
+	}
+	return nil
+}
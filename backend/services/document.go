@@ -0,0 +1,387 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTemplateNotFound is returned when a document template does not exist.
+var ErrTemplateNotFound = errors.New("document template not found")
+
+// maxTemplateSize caps the length of a template body.
+const maxTemplateSize = 64 << 10
+
+const templateInsertAttempts = 3
+
+//go:embed data/templates/fir.tmpl
+var builtinFIRTemplate string
+
+// builtinTemplates are used for kinds that have no stored template.
+var builtinTemplates = map[string]string{
+	models.TemplateKindFIR: builtinFIRTemplate,
+}
+
+// DocumentOfficer is the registering officer as templates see it.
+type DocumentOfficer struct {
+	Name     string
+	Badge    string
+	Rank     string
+	Station  string
+	District string
+	State    string
+}
+
+// DocumentData is the data a document template is executed with.
+// Narrative is the incident description as it should appear in the
+// document, Witnesses the FIR's witness details split one per line and
+// Sections the acts and sections it is registered under.
+type DocumentData struct {
+	FIR          *models.FIR
+	Officer      DocumentOfficer
+	Narrative    string
+	Witnesses    []string
+	Sections     []string
+	Provision    string
+	RegisteredAt time.Time
+	GeneratedAt  time.Time
+}
+
+// NewDocumentData builds template data for an FIR registered by officer.
+//
+// A submitted FIR is printed from its sealed content, so that every copy
+// that verifies against the ledger reads the same: the narrative is the
+// original description, the sections are its applicable sections under the
+// penal code in force on the incident date, and the registration date is
+// when it was submitted. A draft uses narrative, falling back to its
+// English description and then its original one, and its suggested laws.
+func NewDocumentData(fir *models.FIR, officer *models.User, narrative string) DocumentData {
+	now := time.Now()
+	data := DocumentData{
+		FIR:          fir,
+		Witnesses:    []string{},
+		Sections:     []string{},
+		RegisteredAt: now,
+		GeneratedAt:  now,
+	}
+	if fir.SubmittedAt != nil {
+		data.RegisteredAt = *fir.SubmittedAt
+		narrative = fir.IncidentDescription
+		penal := CodesForDate(fir.IncidentDate).Penal
+		for _, section := range fir.ApplicableSections {
+			data.Sections = append(data.Sections, formatLaw(models.SuggestedLaw{
+				Act:         penal,
+				Section:     section,
+				Equivalents: equivalentSections(penal, section),
+			}))
+		}
+	} else {
+		for _, law := range fir.SuggestedLaws {
+			data.Sections = append(data.Sections, formatLaw(law))
+		}
+	}
+	if narrative == "" {
+		narrative = fir.DescriptionEnglish
+	}
+	if narrative == "" {
+		narrative = fir.IncidentDescription
+	}
+	data.Narrative = strings.TrimSpace(narrative)
+	data.Provision = firRegistrationProvision(data.RegisteredAt)
+	if officer != nil {
+		data.Officer = DocumentOfficer{
+			Name:     officer.Name,
+			Badge:    officer.Badge,
+			Rank:     officer.Rank,
+			Station:  officer.Station,
+			District: officer.District,
+			State:    officer.State,
+		}
+	}
+	for _, line := range strings.Split(fir.WitnessDetails, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			data.Witnesses = append(data.Witnesses, line)
+		}
+	}
+	return data
+}
+
+// templateFuncs are the functions available to document templates.
+var templateFuncs = template.FuncMap{
+	"date":     formatDocumentDate("02/01/2006"),
+	"datetime": formatDocumentDate("02/01/2006 15:04"),
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"join":     strings.Join,
+	"add":      func(a, b int) int { return a + b },
+	"law":      formatLaw,
+	"default": func(fallback string, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// formatDocumentDate returns a template function that formats a time or
+// time pointer in IST, rendering unset times as "".
+func formatDocumentDate(layout string) func(interface{}) string {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	return func(value interface{}) string {
+		var t time.Time
+		switch v := value.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v != nil {
+				t = *v
+			}
+		}
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(ist).Format(layout)
+	}
+}
+
+type DocumentService struct {
+	collection string
+}
+
+func NewDocumentService() *DocumentService {
+	return &DocumentService{
+		collection: "document_templates",
+	}
+}
+
+// EnsureIndexes creates the unique index that keeps template versions from
+// being reused.
+func (s *DocumentService) EnsureIndexes(ctx context.Context) error {
+	_, err := database.GetCollection(s.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "state", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// ListTemplates returns every stored version of a kind's templates,
+// newest first within each state. An empty state lists all states.
+func (s *DocumentService) ListTemplates(ctx context.Context, kind, state string) ([]models.DocumentTemplate, error) {
+	filter := bson.M{"kind": templateKind(kind)}
+	if state != "" {
+		filter["state"] = normalizeState(state)
+	}
+
+	cursor, err := database.GetCollection(s.collection).Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "state", Value: 1}, {Key: "version", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.DocumentTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *DocumentService) GetTemplate(ctx context.Context, id string) (*models.DocumentTemplate, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	var tmpl models.DocumentTemplate
+	err = database.GetCollection(s.collection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&tmpl)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// CreateTemplate validates a template by rendering it against a sample FIR
+// and saves it as the next version for its kind and state.
+func (s *DocumentService) CreateTemplate(ctx context.Context, adminID string, req models.CreateTemplateRequest) (*models.DocumentTemplate, error) {
+	adminObjectID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := models.DocumentTemplate{
+		Kind:      templateKind(req.Kind),
+		State:     normalizeState(req.State),
+		Body:      req.Body,
+		Notes:     strings.TrimSpace(req.Notes),
+		CreatedBy: adminObjectID,
+	}
+	if _, ok := builtinTemplates[tmpl.Kind]; !ok {
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("unknown template kind %q", tmpl.Kind)}}
+	}
+	if _, err := s.Render(&tmpl, sampleDocumentData()); err != nil {
+		return nil, err
+	}
+
+	collection := database.GetCollection(s.collection)
+	for attempt := 1; ; attempt++ {
+		latest, err := s.latest(ctx, tmpl.Kind, tmpl.State)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.ID = primitive.NewObjectID()
+		tmpl.Version = 1
+		if latest != nil {
+			tmpl.Version = latest.Version + 1
+		}
+		tmpl.CreatedAt = time.Now()
+
+		_, err = collection.InsertOne(ctx, tmpl)
+		if mongo.IsDuplicateKeyError(err) && attempt < templateInsertAttempts {
+			// Another admin saved a version at the same time.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &tmpl, nil
+	}
+}
+
+// ResolveTemplate returns the template in use for a kind in a state: the
+// state's latest version, else the latest default version, else the
+// built-in template.
+func (s *DocumentService) ResolveTemplate(ctx context.Context, kind, state string) (*models.DocumentTemplate, error) {
+	kind = templateKind(kind)
+	states := []string{""}
+	if state = normalizeState(state); state != "" {
+		states = []string{state, ""}
+	}
+	for _, candidate := range states {
+		tmpl, err := s.latest(ctx, kind, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl != nil {
+			return tmpl, nil
+		}
+	}
+
+	body, ok := builtinTemplates[kind]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	return &models.DocumentTemplate{Kind: kind, Body: body}, nil
+}
+
+func (s *DocumentService) latest(ctx context.Context, kind, state string) (*models.DocumentTemplate, error) {
+	var tmpl models.DocumentTemplate
+	err := database.GetCollection(s.collection).FindOne(ctx,
+		bson.M{"kind": kind, "state": state},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(&tmpl)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// Render executes a template. Parse and execution errors, such as a
+// reference to a field that does not exist, are returned as a
+// ValidationError so they can be shown to the template's author.
+func (s *DocumentService) Render(tmpl *models.DocumentTemplate, data DocumentData) (string, error) {
+	if len(tmpl.Body) > maxTemplateSize {
+		return "", &ValidationError{Problems: []string{fmt.Sprintf("template is larger than %d KB", maxTemplateSize>>10)}}
+	}
+
+	parsed, err := template.New(tmpl.Kind).Funcs(templateFuncs).Option("missingkey=error").Parse(tmpl.Body)
+	if err != nil {
+		return "", &ValidationError{Problems: []string{err.Error()}}
+	}
+
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", &ValidationError{Problems: []string{err.Error()}}
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// templateRef describes tmpl for a rendered document.
+func templateRef(tmpl *models.DocumentTemplate) *models.TemplateRef {
+	ref := &models.TemplateRef{Kind: tmpl.Kind, State: tmpl.State, Version: tmpl.Version}
+	if !tmpl.ID.IsZero() {
+		id := tmpl.ID
+		ref.ID = &id
+	}
+	return ref
+}
+
+func templateKind(kind string) string {
+	if kind = strings.ToLower(strings.TrimSpace(kind)); kind == "" {
+		return models.TemplateKindFIR
+	}
+	return kind
+}
+
+func normalizeState(state string) string {
+	return strings.ToLower(strings.Join(strings.Fields(state), " "))
+}
+
+// sampleDocumentData is a complete FIR used to validate and preview
+// templates.
+func sampleDocumentData() DocumentData {
+	submittedAt := time.Date(2026, time.March, 14, 11, 30, 0, 0, time.UTC)
+	fir := &models.FIR{
+		ID:                  primitive.NewObjectID(),
+		FIRNumber:           "CONNAUGHT-PLACE/2026/000123",
+		Station:             "Connaught Place",
+		ComplainantName:     "Ravi Kumar",
+		ComplainantAddress:  "12 Janpath, New Delhi",
+		ComplainantPhone:    "9876543210",
+		IncidentDate:        time.Date(2026, time.March, 13, 0, 0, 0, 0, time.UTC),
+		IncidentTime:        "21:15",
+		IncidentLocation:    "Inner Circle, Connaught Place",
+		IncidentDescription: "Two men on a motorcycle snatched the complainant's mobile phone and fled towards Barakhamba Road.",
+		WitnessDetails:      "Anil Sharma, shopkeeper, Block B\nMeena Gupta, passer-by",
+		EvidenceDetails:     "CCTV footage from Block B",
+		OfficerRemarks:      "Complainant identified the motorcycle as a black Pulsar.",
+		Language:            "english",
+		Status:              models.FIRStatusSubmitted,
+		ApplicableSections:  []string{"304"},
+		SuggestedLaws: []models.SuggestedLaw{{
+			Section:     "304",
+			Act:         models.ActBNS,
+			Description: "Snatching",
+			Confidence:  90,
+			Relevance:   "high",
+			Equivalents: []models.SectionRef{{Act: models.ActIPC, Section: "379"}},
+		}},
+		SubmittedAt: &submittedAt,
+	}
+	return NewDocumentData(fir, &models.User{
+		Name:     "Priya Singh",
+		Badge:    "DL-4521",
+		Rank:     "Sub-Inspector",
+		Station:  "Connaught Place",
+		District: "New Delhi",
+		State:    "Delhi",
+	}, "")
+}
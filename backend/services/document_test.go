@@ -0,0 +1,118 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"legalassist-ai-backend/models"
+)
+
+func TestNewDocumentData(t *testing.T) {
+	submittedAt := time.Date(2026, time.March, 14, 11, 30, 0, 0, time.UTC)
+	fir := func(submitted bool) *models.FIR {
+		fir := &models.FIR{
+			IncidentDate:        time.Date(2026, time.March, 13, 0, 0, 0, 0, time.UTC),
+			IncidentDescription: "मेरा फोन छीन लिया गया।",
+			DescriptionEnglish:  "My phone was snatched.",
+			ApplicableSections:  []string{"303(2)"},
+			SuggestedLaws: []models.SuggestedLaw{
+				{Section: "303(2)", Act: models.ActBNS},
+				{Section: "304", Act: models.ActBNS},
+			},
+		}
+		if submitted {
+			fir.SubmittedAt = &submittedAt
+		}
+		return fir
+	}
+
+	tests := []struct {
+		name             string
+		fir              *models.FIR
+		narrative        string
+		wantNarrative    string
+		wantSections     []string
+		wantRegisteredAt time.Time
+	}{
+		{
+			"submitted",
+			fir(true),
+			"",
+			"मेरा फोन छीन लिया गया।",
+			[]string{"Section 303(2) of Bharatiya Nyaya Sanhita (corresponds to Section 379 of Indian Penal Code)"},
+			submittedAt,
+		},
+		{
+			"submitted ignores a drafted narrative",
+			fir(true),
+			"The complainant reports that his phone was snatched.",
+			"मेरा फोन छीन लिया गया।",
+			[]string{"Section 303(2) of Bharatiya Nyaya Sanhita (corresponds to Section 379 of Indian Penal Code)"},
+			submittedAt,
+		},
+		{
+			"draft",
+			fir(false),
+			"",
+			"My phone was snatched.",
+			[]string{"Section 303(2) of Bharatiya Nyaya Sanhita", "Section 304 of Bharatiya Nyaya Sanhita"},
+			time.Time{},
+		},
+		{
+			"draft with a drafted narrative",
+			fir(false),
+			"The complainant reports that his phone was snatched.",
+			"The complainant reports that his phone was snatched.",
+			[]string{"Section 303(2) of Bharatiya Nyaya Sanhita", "Section 304 of Bharatiya Nyaya Sanhita"},
+			time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := NewDocumentData(tt.fir, nil, tt.narrative)
+			if data.Narrative != tt.wantNarrative {
+				t.Errorf("Narrative = %q, want %q", data.Narrative, tt.wantNarrative)
+			}
+			if !reflect.DeepEqual(data.Sections, tt.wantSections) {
+				t.Errorf("Sections = %q, want %q", data.Sections, tt.wantSections)
+			}
+			if tt.wantRegisteredAt.IsZero() {
+				if !data.RegisteredAt.Equal(data.GeneratedAt) {
+					t.Errorf("RegisteredAt = %v, want the render time %v", data.RegisteredAt, data.GeneratedAt)
+				}
+			} else if !data.RegisteredAt.Equal(tt.wantRegisteredAt) {
+				t.Errorf("RegisteredAt = %v, want %v", data.RegisteredAt, tt.wantRegisteredAt)
+			}
+		})
+	}
+}
+
+func TestBuiltinTemplatePrintsSealedContent(t *testing.T) {
+	fir := sampleDocumentData().FIR
+	fir.DescriptionEnglish = "An English translation that may be refreshed."
+	fir.SuggestedLaws = append(fir.SuggestedLaws, models.SuggestedLaw{Section: "115", Act: models.ActBNS})
+	fir.ApplicableSections = []string{"303(2)"}
+	data := NewDocumentData(fir, nil, "A narrative drafted for this copy.")
+	data.GeneratedAt = time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	text, err := NewDocumentService().Render(&models.DocumentTemplate{Kind: models.TemplateKindFIR, Body: builtinFIRTemplate}, data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		"Date of Report: 14/03/2026",
+		data.FIR.IncidentDescription,
+		"- Section 303(2) of Bharatiya Nyaya Sanhita (corresponds to Section 379 of Indian Penal Code)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("document does not contain %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"01/10/2026", "drafted for this copy", "English translation", "Section 304", "Section 115"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("document contains unsealed %q:\n%s", unwanted, text)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"legalassist-ai-backend/config"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// ErrEncryptionNotConfigured is returned when encrypted data is needed
+// without ENCRYPTION_KEY being set.
+var ErrEncryptionNotConfigured = errors.New("encryption is not configured; set ENCRYPTION_KEY")
+
+// Keyring holds the secrets data is encrypted under, by key ID. New data
+// is always encrypted under the current secret, ENCRYPTION_KEY. Secrets
+// listed in ENCRYPTION_OLD_KEYS can still decrypt data until
+// cmd/rotate-keys has re-encrypted it under the current one.
+type Keyring struct {
+	currentID string
+	secrets   map[string]string
+}
+
+func NewKeyring(cfg *config.Config) *Keyring {
+	keyring := &Keyring{currentID: cfg.EncryptionKeyID, secrets: map[string]string{}}
+	for i, entry := range strings.Split(cfg.EncryptionOldKeys, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || secret == "" {
+			// The entry is not logged, since it may hold a secret.
+			log.Printf("Ignoring malformed ENCRYPTION_OLD_KEYS entry %d; expected id:secret", i+1)
+			continue
+		}
+		keyring.secrets[id] = secret
+	}
+	if cfg.EncryptionKey != "" {
+		keyring.secrets[keyring.currentID] = cfg.EncryptionKey
+	}
+	return keyring
+}
+
+// configured reports whether the current secret is set.
+func (k *Keyring) configured() bool {
+	_, ok := k.secrets[k.currentID]
+	return ok
+}
+
+// key derives the key for one purpose from the secret with the given ID.
+func (k *Keyring) key(id, purpose string) ([]byte, error) {
+	secret, ok := k.secrets[id]
+	if !ok {
+		if id == k.currentID {
+			return nil, ErrEncryptionNotConfigured
+		}
+		return nil, fmt.Errorf("encryption key %q is not configured; add it to ENCRYPTION_OLD_KEYS", id)
+	}
+	return deriveKey(secret, purpose), nil
+}
+
+// ids returns every configured key ID, the current one first.
+func (k *Keyring) ids() []string {
+	var ids []string
+	if k.configured() {
+		ids = append(ids, k.currentID)
+	}
+	for id := range k.secrets {
+		if id != k.currentID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// deriveKey derives a 256-bit key for one purpose from a configured
+// secret, so one secret can protect several kinds of data independently.
+func deriveKey(secret, purpose string) []byte {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(purpose)), key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// sealGCM encrypts plaintext with AES-256-GCM. The nonce is prepended to
+// the ciphertext, and aad is authenticated with it so ciphertext copied to
+// another record fails to decrypt.
+func sealGCM(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func openGCM(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
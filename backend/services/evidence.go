@@ -0,0 +1,640 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
+	"legalassist-ai-backend/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrEvidenceNotFound is returned when an evidence item does not exist
+	// or belongs to another FIR.
+	ErrEvidenceNotFound = errors.New("evidence not found")
+	// ErrEvidenceTooLarge is returned when an upload exceeds the configured
+	// maximum size.
+	ErrEvidenceTooLarge = errors.New("evidence file is too large")
+	// ErrUploadComplete is returned when a chunk is sent for an upload that
+	// has already finished.
+	ErrUploadComplete = errors.New("upload is already complete")
+	// ErrUploadIncomplete is returned when an evidence file is read before
+	// its upload has finished.
+	ErrUploadIncomplete = errors.New("upload has not finished")
+	// ErrChecksumMismatch is returned when a completed resumable upload does
+	// not match the SHA-256 declared when it started. The upload is reset so
+	// it can be sent again.
+	ErrChecksumMismatch = errors.New("uploaded file does not match the declared SHA-256")
+)
+
+// OffsetMismatchError is returned when a chunk does not start where the
+// upload left off. Offset is where the next chunk must start.
+type OffsetMismatchError struct {
+	Offset int64
+}
+
+func (e *OffsetMismatchError) Error() string {
+	return fmt.Sprintf("upload offset mismatch: next chunk must start at byte %d", e.Offset)
+}
+
+// IntegrityError is returned when a stored evidence file no longer matches
+// the hash recorded at ingest.
+type IntegrityError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("evidence integrity check failed: expected SHA-256 %s, got %s", e.Expected, e.Actual)
+}
+
+// CustodyActor identifies who is handling evidence, for the custody log.
+type CustodyActor struct {
+	OfficerID string
+	ClientIP  string
+}
+
+// EvidenceUpload describes a file sent in a single request.
+type EvidenceUpload struct {
+	FileName    string
+	ContentType string
+	Kind        string
+	Description string
+}
+
+// EvidenceVerification is the result of rehashing a stored evidence file.
+type EvidenceVerification struct {
+	EvidenceID string `json:"evidence_id"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Valid      bool   `json:"valid"`
+}
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type EvidenceService struct {
+	collection        string
+	custodyCollection string
+	store             storage.BlobStore
+	maxSize           int64
+	firService        *FIRService
+}
+
+func NewEvidenceService(cfg *config.Config) *EvidenceService {
+	return &EvidenceService{
+		collection:        "evidence",
+		custodyCollection: "evidence_custody",
+		store:             storage.NewLocalStore(cfg.EvidenceStoragePath),
+		maxSize:           cfg.EvidenceMaxSize,
+		firService:        NewFIRService(cfg),
+	}
+}
+
+// MaxSize is the largest evidence file accepted, in bytes.
+func (s *EvidenceService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload stores a file sent in one request, hashing it as it is written.
+func (s *EvidenceService) Upload(ctx context.Context, firID string, actor CustodyActor, upload EvidenceUpload, r io.Reader) (*models.Evidence, error) {
+	fir, officerID, err := s.actorFIR(ctx, firID, actor, policy.EvidenceWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := s.newEvidence(ctx, fir, officerID, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so an oversized file is detected
+	// without storing more than that.
+	hash := sha256.New()
+	size, err := s.store.Put(ctx, evidence.StorageKey, io.TeeReader(io.LimitReader(r, s.maxSize+1), hash))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		s.store.Delete(ctx, evidence.StorageKey)
+		return nil, ErrEvidenceTooLarge
+	}
+
+	now := time.Now()
+	evidence.Size = size
+	evidence.Offset = size
+	evidence.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	evidence.Status = models.EvidenceStatusAvailable
+	evidence.CompletedAt = &now
+
+	if _, err := database.GetCollection(s.collection).InsertOne(ctx, evidence); err != nil {
+		s.store.Delete(ctx, evidence.StorageKey)
+		return nil, err
+	}
+	if err := s.recordCustody(ctx, evidence, models.CustodyUploaded, actor, officerID, "", evidence.SHA256); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// StartUpload opens a resumable upload. Chunks are then sent with
+// AppendChunk until Size bytes have arrived.
+func (s *EvidenceService) StartUpload(ctx context.Context, firID string, actor CustodyActor, req models.StartEvidenceUploadRequest) (*models.Evidence, error) {
+	var problems []string
+	if req.Size <= 0 {
+		problems = append(problems, "size must be positive")
+	}
+	expected := strings.ToLower(strings.TrimSpace(req.SHA256))
+	if expected != "" && !sha256Pattern.MatchString(expected) {
+		problems = append(problems, "sha256 must be 64 hexadecimal characters")
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	if req.Size > s.maxSize {
+		return nil, ErrEvidenceTooLarge
+	}
+
+	fir, officerID, err := s.actorFIR(ctx, firID, actor, policy.EvidenceWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := s.newEvidence(ctx, fir, officerID, EvidenceUpload{
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		Kind:        req.Kind,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	evidence.Size = req.Size
+	evidence.ExpectedSHA256 = expected
+	evidence.Status = models.EvidenceStatusUploading
+
+	if _, err := database.GetCollection(s.collection).InsertOne(ctx, evidence); err != nil {
+		return nil, err
+	}
+	details := fmt.Sprintf("expecting %d bytes", req.Size)
+	if err := s.recordCustody(ctx, evidence, models.CustodyUploadStarted, actor, officerID, details, ""); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// AppendChunk adds the next chunk of a resumable upload starting at
+// offset. When the last byte arrives the file is hashed and, if a SHA-256
+// was declared, checked against it.
+func (s *EvidenceService) AppendChunk(ctx context.Context, firID, evidenceID string, actor CustodyActor, offset int64, r io.Reader) (*models.Evidence, error) {
+	evidence, officerID, err := s.actorEvidence(ctx, firID, evidenceID, actor, policy.EvidenceWrite)
+	if err != nil {
+		return nil, err
+	}
+	if evidence.Status != models.EvidenceStatusUploading {
+		return nil, ErrUploadComplete
+	}
+	if offset != evidence.Offset {
+		return nil, &OffsetMismatchError{Offset: evidence.Offset}
+	}
+
+	remaining := evidence.Size - offset
+	newOffset, err := s.store.Append(ctx, evidence.StorageKey, offset, io.LimitReader(r, remaining))
+	if errors.Is(err, storage.ErrOffsetMismatch) {
+		// The blob and the record disagree, e.g. after a crash mid-chunk.
+		// Trust the blob, which is what the next chunk is appended to.
+		s.setOffset(ctx, evidence, newOffset)
+		return nil, &OffsetMismatchError{Offset: newOffset}
+	}
+	if err != nil {
+		// Keep whatever arrived so the client can resume from there.
+		s.setOffset(ctx, evidence, newOffset)
+		return nil, err
+	}
+	if extra, _ := r.Read(make([]byte, 1)); extra > 0 {
+		s.setOffset(ctx, evidence, newOffset)
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("chunk extends past the declared size of %d bytes", evidence.Size)}}
+	}
+
+	if newOffset < evidence.Size {
+		if err := s.setOffset(ctx, evidence, newOffset); err != nil {
+			return nil, err
+		}
+		return evidence, nil
+	}
+
+	actual, err := s.hashBlob(ctx, evidence.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	collection := database.GetCollection(s.collection)
+	if evidence.ExpectedSHA256 != "" && actual != evidence.ExpectedSHA256 {
+		s.store.Delete(ctx, evidence.StorageKey)
+		s.setOffset(ctx, evidence, 0)
+		details := "declared SHA-256 " + evidence.ExpectedSHA256 + ", upload reset"
+		if err := s.recordCustody(ctx, evidence, models.CustodyIntegrityFailed, actor, officerID, details, actual); err != nil {
+			return nil, err
+		}
+		return nil, ErrChecksumMismatch
+	}
+
+	now := time.Now()
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": evidence.ID}, bson.M{"$set": bson.M{
+		"offset":       newOffset,
+		"sha256":       actual,
+		"status":       models.EvidenceStatusAvailable,
+		"completed_at": now,
+		"updated_at":   now,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	evidence.Offset = newOffset
+	evidence.SHA256 = actual
+	evidence.Status = models.EvidenceStatusAvailable
+	evidence.CompletedAt = &now
+	evidence.UpdatedAt = now
+
+	if err := s.recordCustody(ctx, evidence, models.CustodyUploaded, actor, officerID, "", actual); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// ListEvidence returns the evidence attached to an FIR in the actor's
+// jurisdiction, oldest first.
+func (s *EvidenceService) ListEvidence(ctx context.Context, firID string, actor CustodyActor) ([]models.Evidence, error) {
+	fir, _, err := s.readableFIR(ctx, firID, actor, models.AuditEvidenceList)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.GetCollection(s.collection).Find(ctx,
+		bson.M{"fir_id": fir.ID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	evidence := []models.Evidence{}
+	if err := cursor.All(ctx, &evidence); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// GetEvidence returns an evidence item's metadata.
+func (s *EvidenceService) GetEvidence(ctx context.Context, firID, evidenceID string, actor CustodyActor) (*models.Evidence, error) {
+	evidence, _, err := s.readableEvidence(ctx, firID, evidenceID, actor, models.AuditEvidenceGet)
+	return evidence, err
+}
+
+// Download verifies an evidence file against its ingest hash and returns
+// it for reading. The file is hashed and read from the same handle, so the
+// bytes returned are the bytes checked. A file that fails the check is not
+// returned and the failure is logged.
+func (s *EvidenceService) Download(ctx context.Context, firID, evidenceID string, actor CustodyActor) (*models.Evidence, io.ReadCloser, error) {
+	evidence, officerID, err := s.readableEvidence(ctx, firID, evidenceID, actor, models.AuditEvidenceDownload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if evidence.Status != models.EvidenceStatusAvailable {
+		return nil, nil, ErrUploadIncomplete
+	}
+
+	_, file, err := s.verify(ctx, evidence, officerID, actor, models.CustodyDownloaded)
+	if err != nil {
+		return nil, nil, err
+	}
+	return evidence, file, nil
+}
+
+// VerifyEvidence rehashes a stored evidence file and records the result in
+// its custody log.
+func (s *EvidenceService) VerifyEvidence(ctx context.Context, firID, evidenceID string, actor CustodyActor) (*EvidenceVerification, error) {
+	evidence, officerID, err := s.actorEvidence(ctx, firID, evidenceID, actor, policy.EvidenceWrite)
+	if err != nil {
+		return nil, err
+	}
+	if evidence.Status != models.EvidenceStatusAvailable {
+		return nil, ErrUploadIncomplete
+	}
+
+	actual, file, err := s.verify(ctx, evidence, officerID, actor, models.CustodyVerified)
+	if file != nil {
+		file.Close()
+	}
+	var integrityErr *IntegrityError
+	if err != nil && !errors.As(err, &integrityErr) {
+		return nil, err
+	}
+	return &EvidenceVerification{
+		EvidenceID: evidence.ID.Hex(),
+		Expected:   evidence.SHA256,
+		Actual:     actual,
+		Valid:      err == nil,
+	}, nil
+}
+
+// TransferEvidence hands an evidence item to a new custodian.
+func (s *EvidenceService) TransferEvidence(ctx context.Context, firID, evidenceID string, actor CustodyActor, req models.TransferEvidenceRequest) (*models.Evidence, error) {
+	to := strings.TrimSpace(req.To)
+	reason := strings.TrimSpace(req.Reason)
+	var problems []string
+	if to == "" {
+		problems = append(problems, "to is required")
+	}
+	if reason == "" {
+		problems = append(problems, "reason is required")
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	evidence, officerID, err := s.actorEvidence(ctx, firID, evidenceID, actor, policy.EvidenceWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_, err = database.GetCollection(s.collection).UpdateOne(ctx,
+		bson.M{"_id": evidence.ID},
+		bson.M{"$set": bson.M{"custodian": to, "updated_at": now}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("from %s to %s: %s", evidence.Custodian, to, reason)
+	evidence.Custodian = to
+	evidence.UpdatedAt = now
+	if err := s.recordCustody(ctx, evidence, models.CustodyTransferred, actor, officerID, details, ""); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// CustodyLog returns an evidence item's chain-of-custody log, oldest
+// first.
+func (s *EvidenceService) CustodyLog(ctx context.Context, firID, evidenceID string, actor CustodyActor) ([]models.CustodyEvent, error) {
+	evidence, _, err := s.readableEvidence(ctx, firID, evidenceID, actor, models.AuditEvidenceCustody)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.GetCollection(s.custodyCollection).Find(ctx,
+		bson.M{"evidence_id": evidence.ID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	events := []models.CustodyEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// verify rehashes an evidence file and logs the access as action, or as
+// an integrity failure when the hash no longer matches. It returns the
+// hash it computed and, when that matches, the file rewound to its start
+// for the caller to read and close.
+func (s *EvidenceService) verify(ctx context.Context, evidence *models.Evidence, officerID primitive.ObjectID, actor CustodyActor, action string) (actual string, file io.ReadSeekCloser, err error) {
+	blob, err := s.store.Open(ctx, evidence.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		if err := s.recordCustody(ctx, evidence, models.CustodyIntegrityFailed, actor, officerID, "file is missing from storage", ""); err != nil {
+			return "", nil, err
+		}
+		return "", nil, &IntegrityError{Expected: evidence.SHA256, Actual: ""}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		if err != nil {
+			blob.Close()
+		}
+	}()
+
+	actual, err = hashReader(blob)
+	if err != nil {
+		return "", nil, err
+	}
+	if actual != evidence.SHA256 {
+		if err := s.recordCustody(ctx, evidence, models.CustodyIntegrityFailed, actor, officerID, "attempted "+action, actual); err != nil {
+			return "", nil, err
+		}
+		return actual, nil, &IntegrityError{Expected: evidence.SHA256, Actual: actual}
+	}
+	if _, err := blob.Seek(0, io.SeekStart); err != nil {
+		return "", nil, err
+	}
+	if err := s.recordCustody(ctx, evidence, action, actor, officerID, "", actual); err != nil {
+		return "", nil, err
+	}
+	return actual, blob, nil
+}
+
+// newEvidence builds the record for a new upload, filling in the content
+// type and kind from the file name when they are not given.
+func (s *EvidenceService) newEvidence(ctx context.Context, fir *models.FIR, officerID primitive.ObjectID, upload EvidenceUpload) (*models.Evidence, error) {
+	fileName := filepath.Base(strings.TrimSpace(upload.FileName))
+	contentType := strings.TrimSpace(upload.ContentType)
+	if contentType == "" || contentType == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(filepath.Ext(fileName)); byExtension != "" {
+			contentType = byExtension
+		} else {
+			contentType = "application/octet-stream"
+		}
+	}
+	kind := strings.TrimSpace(upload.Kind)
+	if kind == "" {
+		kind = evidenceKind(contentType)
+	}
+
+	var problems []string
+	if fileName == "" || fileName == "." || fileName == "/" {
+		problems = append(problems, "file name is required")
+	}
+	switch kind {
+	case models.EvidenceKindPhoto, models.EvidenceKindVideo, models.EvidenceKindDocument, models.EvidenceKindAudio:
+	default:
+		problems = append(problems, fmt.Sprintf("kind %q must be photo, video, document or audio", kind))
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	officer, err := s.firService.authService.GetUserByID(officerID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	id := primitive.NewObjectID()
+	now := time.Now()
+	return &models.Evidence{
+		ID:          id,
+		FIRID:       fir.ID,
+		Kind:        kind,
+		FileName:    fileName,
+		ContentType: contentType,
+		Description: strings.TrimSpace(upload.Description),
+		StorageKey:  fmt.Sprintf("evidence/%s/%s", fir.ID.Hex(), id.Hex()),
+		Custodian:   fmt.Sprintf("%s (%s)", officer.Name, officer.Badge),
+		UploadedBy:  officerID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// evidenceKind infers the kind of evidence from its content type.
+func evidenceKind(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return models.EvidenceKindPhoto
+	case strings.HasPrefix(contentType, "video/"):
+		return models.EvidenceKindVideo
+	case strings.HasPrefix(contentType, "audio/"):
+		return models.EvidenceKindAudio
+	default:
+		return models.EvidenceKindDocument
+	}
+}
+
+// actorFIR loads an FIR the actor may use permission on: their own, or
+// any at their station if they manage it. The actor's ID is returned for
+// the custody log.
+func (s *EvidenceService) actorFIR(ctx context.Context, firID string, actor CustodyActor, permission string) (*models.FIR, primitive.ObjectID, error) {
+	firObjectID, officerObjectID, err := parseFIRIDs(firID, actor.OfficerID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	fir, err := s.firService.managedFIR(ctx, firObjectID, actor.OfficerID, permission)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	return fir, officerObjectID, nil
+}
+
+// readableFIR loads an FIR in the actor's jurisdiction and records the
+// read as action in the audit log. The actor's ID is returned for the
+// custody log.
+func (s *EvidenceService) readableFIR(ctx context.Context, firID string, actor CustodyActor, action string) (*models.FIR, primitive.ObjectID, error) {
+	_, officerObjectID, err := parseFIRIDs(firID, actor.OfficerID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	fir, err := s.firService.scopedFIR(ctx, firID, actor.OfficerID, action, false)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	return fir, officerObjectID, nil
+}
+
+// actorEvidence loads an evidence item of an FIR the actor may use
+// permission on.
+func (s *EvidenceService) actorEvidence(ctx context.Context, firID, evidenceID string, actor CustodyActor, permission string) (*models.Evidence, primitive.ObjectID, error) {
+	fir, officerID, err := s.actorFIR(ctx, firID, actor, permission)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	evidence, err := s.findEvidence(ctx, fir, evidenceID)
+	return evidence, officerID, err
+}
+
+// readableEvidence loads an evidence item of an FIR in the actor's
+// jurisdiction, recording the read as action.
+func (s *EvidenceService) readableEvidence(ctx context.Context, firID, evidenceID string, actor CustodyActor, action string) (*models.Evidence, primitive.ObjectID, error) {
+	fir, officerID, err := s.readableFIR(ctx, firID, actor, action)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	evidence, err := s.findEvidence(ctx, fir, evidenceID)
+	return evidence, officerID, err
+}
+
+func (s *EvidenceService) findEvidence(ctx context.Context, fir *models.FIR, evidenceID string) (*models.Evidence, error) {
+	evidenceObjectID, err := primitive.ObjectIDFromHex(evidenceID)
+	if err != nil {
+		return nil, ErrEvidenceNotFound
+	}
+
+	var evidence models.Evidence
+	err = database.GetCollection(s.collection).FindOne(ctx, bson.M{"_id": evidenceObjectID, "fir_id": fir.ID}).Decode(&evidence)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrEvidenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &evidence, nil
+}
+
+func (s *EvidenceService) setOffset(ctx context.Context, evidence *models.Evidence, offset int64) error {
+	now := time.Now()
+	_, err := database.GetCollection(s.collection).UpdateOne(ctx,
+		bson.M{"_id": evidence.ID},
+		bson.M{"$set": bson.M{"offset": offset, "updated_at": now}},
+	)
+	if err == nil {
+		evidence.Offset = offset
+		evidence.UpdatedAt = now
+	}
+	return err
+}
+
+func (s *EvidenceService) hashBlob(ctx context.Context, key string) (string, error) {
+	file, err := s.store.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return hashReader(file)
+}
+
+// hashReader returns the hex SHA-256 of everything read from r.
+func hashReader(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *EvidenceService) recordCustody(ctx context.Context, evidence *models.Evidence, action string, actor CustodyActor, officerID primitive.ObjectID, details, hash string) error {
+	_, err := database.GetCollection(s.custodyCollection).InsertOne(ctx, models.CustodyEvent{
+		ID:         primitive.NewObjectID(),
+		EvidenceID: evidence.ID,
+		FIRID:      evidence.FIRID,
+		Action:     action,
+		ActorID:    officerID,
+		ClientIP:   actor.ClientIP,
+		Details:    details,
+		SHA256:     hash,
+		CreatedAt:  time.Now(),
+	})
+	return err
+}
@@ -2,33 +2,104 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"legalassist-ai-backend/config"
 	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/jobs"
 	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
 	"legalassist-ai-backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type FIRService struct {
-	collection string
-	aiService  *AIService
+	collection          string
+	revisionsCollection string
+	ledgerCollection    string
+	aiService           *AIService
+	authService         *AuthService
+	auditService        *AuditService
+	counterService      *CounterService
+	documentService     *DocumentService
+	pdfRenderer         *PDFRenderer
+	keystore            *Keystore
+	keyring             *Keyring
+	publicURL           string
+	queue               *jobs.Queue
 }
 
-func NewFIRService() *FIRService {
+func NewFIRService(cfg *config.Config) *FIRService {
 	return &FIRService{
-		collection: "firs",
-		aiService:  NewAIService(),
+		collection:          "firs",
+		revisionsCollection: "fir_revisions",
+		ledgerCollection:    "fir_ledger",
+		aiService:           NewAIService(cfg),
+		authService:         NewAuthService(),
+		auditService:        NewAuditService(),
+		counterService:      NewCounterService(),
+		documentService:     NewDocumentService(),
+		pdfRenderer:         NewPDFRenderer(cfg.PDFFontDir),
+		keystore:            NewKeystore(cfg),
+		keyring:             NewKeyring(cfg),
+		publicURL:           cfg.PublicURL,
+		queue:               jobs.NewQueue(),
 	}
 }
 
-func (s *FIRService) CreateFIR(req models.CreateFIRRequest, officerID string) (*models.FIR, error) {
+// EnsureIndexes creates the unique indexes that keep FIR numbers, revision
+// numbers and ledger sequence numbers from being reused, and the indexes
+// complainant lookups search.
+func (s *FIRService) EnsureIndexes(ctx context.Context) error {
+	indexes := []struct {
+		collection string
+		model      mongo.IndexModel
+	}{
+		// Drafts have no number yet, so only assigned numbers are unique.
+		{s.collection, mongo.IndexModel{
+			Keys: bson.D{{Key: "fir_number", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"fir_number": bson.M{"$gt": ""},
+			}),
+		}},
+		{s.collection, mongo.IndexModel{
+			Keys: bson.D{{Key: "complainant_phone_index", Value: 1}},
+		}},
+		{s.collection, mongo.IndexModel{
+			Keys: bson.D{{Key: "complainant_name_index", Value: 1}},
+		}},
+		{s.revisionsCollection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "fir_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}},
+		{s.ledgerCollection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "station", Value: 1}, {Key: "seq", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}},
+		{s.ledgerCollection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "fir_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}},
+	}
+
+	for _, index := range indexes {
+		if _, err := database.GetCollection(index.collection).Indexes().CreateOne(ctx, index.model); err != nil {
+			return fmt.Errorf("%s: %w", index.collection, err)
+		}
+	}
+	return nil
+}
+
+func (s *FIRService) CreateFIR(ctx context.Context, req models.CreateFIRRequest, officerID string) (*models.FIR, error) {
 	collection := database.GetCollection(s.collection)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(officerID)
@@ -42,16 +113,17 @@ func (s *FIRService) CreateFIR(req models.CreateFIRRequest, officerID string) (*
 		return nil, fmt.Errorf("invalid incident date format")
 	}
 
-	// Generate FIR number
-	firNumber := utils.GenerateFIRNumber()
-
-	// Analyze incident with AI
-	aiAnalysis, suggestedLaws := s.aiService.AnalyzeIncident(req.IncidentDescription)
+	officer, err := s.authService.GetUserByID(officerID)
+	if err != nil {
+		return nil, err
+	}
 
 	fir := models.FIR{
 		ID:                  primitive.NewObjectID(),
-		FIRNumber:           firNumber,
 		OfficerID:           objectID,
+		Station:             officer.Station,
+		District:            officer.District,
+		State:               officer.State,
 		ComplainantName:     req.ComplainantName,
 		ComplainantAddress:  req.ComplainantAddress,
 		ComplainantPhone:    req.ComplainantPhone,
@@ -63,39 +135,51 @@ func (s *FIRService) CreateFIR(req models.CreateFIRRequest, officerID string) (*
 		EvidenceDetails:     req.EvidenceDetails,
 		OfficerRemarks:      req.OfficerRemarks,
 		Language:            req.Language,
-		Status:              "draft",
+		Status:              models.FIRStatusDraft,
 		Priority:            s.determinePriority(req.IncidentDescription),
-		SuggestedLaws:       suggestedLaws,
-		AIAnalysis:          aiAnalysis,
+		ApplicableSections:  []string{},
+		SuggestedLaws:       []models.SuggestedLaw{},
+		AIAnalysis:          models.AIAnalysis{Status: models.AnalysisStatusPending},
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
 
-	// Extract applicable sections
-	for _, law := range suggestedLaws {
-		fir.ApplicableSections = append(fir.ApplicableSections, law.Section)
+	sealed, err := s.sealFIR(&fir)
+	if err != nil {
+		return nil, err
 	}
-
-	_, err = collection.InsertOne(ctx, fir)
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		if _, err := collection.InsertOne(ctx, sealed); err != nil {
+			return err
+		}
+		return s.recordRevision(ctx, nil, &fir, models.RevisionCreated, objectID, "")
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &fir, nil
+	// The AI analysis runs in the background and is filled in later.
+	return s.queueAnalysis(ctx, &fir)
 }
 
-func (s *FIRService) GetFIRs(officerID string, page, limit int) ([]models.FIR, int64, error) {
-	collection := database.GetCollection(s.collection)
+// GetFIRs lists the FIRs in the user's jurisdiction: their own, or for
+// supervisors every FIR in their station, district or state.
+func (s *FIRService) GetFIRs(userID string, page, limit int) (*FIRList, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	objectID, err := primitive.ObjectIDFromHex(officerID)
+	user, scope, filter, err := s.readScope(ctx, userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	return s.listScoped(ctx, user, scope, models.AuditFIRList, filter, page, limit)
+}
+
+// listFIRs returns a page of the FIRs matching filter, newest first, and
+// how many match in total.
+func (s *FIRService) listFIRs(ctx context.Context, filter bson.M, page, limit int) ([]models.FIR, int64, error) {
+	collection := database.GetCollection(s.collection)
 
-	filter := bson.M{"officer_id": objectID}
-	
 	// Count total documents
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -103,9 +187,10 @@ func (s *FIRService) GetFIRs(officerID string, page, limit int) ([]models.FIR, i
 	}
 
 	// Find with pagination
+	offset, limit := utils.Paginate(page, limit)
 	opts := options.Find()
-	opts.SetSort(bson.D{{"created_at", -1}})
-	opts.SetSkip(int64((page - 1) * limit))
+	opts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	opts.SetSkip(int64(offset))
 	opts.SetLimit(int64(limit))
 
 	cursor, err := collection.Find(ctx, filter, opts)
@@ -119,89 +204,522 @@ func (s *FIRService) GetFIRs(officerID string, page, limit int) ([]models.FIR, i
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := s.openFIRs(firs); err != nil {
+		return nil, 0, err
+	}
 
 	return firs, total, nil
 }
 
-func (s *FIRService) GetFIRByID(firID, officerID string) (*models.FIR, error) {
-	collection := database.GetCollection(s.collection)
+// GetFIRByID returns an FIR in the user's jurisdiction. Reads are recorded
+// in the audit log whether or not the FIR is found.
+func (s *FIRService) GetFIRByID(firID, userID string) (*models.FIR, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	firObjectID, err := primitive.ObjectIDFromHex(firID)
+	return s.scopedFIR(ctx, firID, userID, models.AuditFIRGet, false)
+}
+
+// UpdateFIR applies the fields set in req to a draft FIR. Changing the
+// incident description or date queues a new AI analysis.
+func (s *FIRService) UpdateFIR(ctx context.Context, firID, officerID string, req models.UpdateFIRRequest) (*models.FIR, error) {
+	firObjectID, officerObjectID, err := parseFIRIDs(firID, officerID)
 	if err != nil {
 		return nil, err
 	}
 
-	officerObjectID, err := primitive.ObjectIDFromHex(officerID)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	fir, err := s.managedFIR(ctx, firObjectID, officerID, policy.FIRUpdate)
 	if err != nil {
 		return nil, err
 	}
+	if fir.Status != models.FIRStatusDraft {
+		return nil, ErrFIRNotDraft
+	}
 
-	var fir models.FIR
-	err = collection.FindOne(ctx, bson.M{
-		"_id":       firObjectID,
-		"officer_id": officerObjectID,
-	}).Decode(&fir)
+	updates, err := firUpdates(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return &fir, nil
-}
+	reanalyze := req.IncidentDescription != nil || req.IncidentDate != nil
+	if reanalyze {
+		description := fir.IncidentDescription
+		if value, ok := updates["incident_description"].(string); ok {
+			description = value
+		}
+
+		updates["ai_analysis"] = models.AIAnalysis{Status: models.AnalysisStatusPending}
+		updates["description_english"] = ""
+		updates["suggested_laws"] = []models.SuggestedLaw{}
+		updates["applicable_sections"] = []string{}
+		updates["priority"] = s.determinePriority(description)
+	}
+	updates["updated_at"] = time.Now()
+	if err := s.sealUpdates(fir, updates); err != nil {
+		return nil, err
+	}
 
-func (s *FIRService) UpdateFIR(firID, officerID string, updates bson.M) error {
 	collection := database.GetCollection(s.collection)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	var updated *models.FIR
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		result, err := collection.UpdateOne(ctx, bson.M{
+			"_id":        firObjectID,
+			"officer_id": fir.OfficerID,
+			"status":     models.FIRStatusDraft,
+			"deleted_at": nil,
+		}, bson.M{"$set": updates})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			// Submitted or deleted since it was read.
+			return ErrFIRNotDraft
+		}
+
+		updated, err = s.recordChange(ctx, fir, models.RevisionUpdated, officerObjectID, strings.TrimSpace(req.Reason))
+		return err
+	})
+	if err != nil || !reanalyze {
+		return updated, err
+	}
+	return s.queueAnalysis(ctx, updated)
+}
+
+// firUpdates validates an update request and converts it to a $set
+// document.
+func firUpdates(req models.UpdateFIRRequest) (bson.M, error) {
+	updates := bson.M{}
+	var problems []string
+
+	required := []struct {
+		field string
+		value *string
+	}{
+		{"complainant_name", req.ComplainantName},
+		{"complainant_address", req.ComplainantAddress},
+		{"complainant_phone", req.ComplainantPhone},
+		{"incident_time", req.IncidentTime},
+		{"incident_location", req.IncidentLocation},
+		{"incident_description", req.IncidentDescription},
+	}
+	for _, f := range required {
+		if f.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*f.value)
+		if value == "" {
+			problems = append(problems, f.field+" cannot be empty")
+			continue
+		}
+		updates[f.field] = value
+	}
+
+	optional := []struct {
+		field string
+		value *string
+	}{
+		{"witness_details", req.WitnessDetails},
+		{"evidence_details", req.EvidenceDetails},
+		{"officer_remarks", req.OfficerRemarks},
+		{"language", req.Language},
+	}
+	for _, f := range optional {
+		if f.value != nil {
+			updates[f.field] = strings.TrimSpace(*f.value)
+		}
+	}
+
+	if req.IncidentDate != nil {
+		incidentDate, err := time.Parse("2006-01-02", strings.TrimSpace(*req.IncidentDate))
+		if err != nil {
+			problems = append(problems, "incident_date must be in YYYY-MM-DD format")
+		} else {
+			updates["incident_date"] = incidentDate
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	if len(updates) == 0 {
+		return nil, &ValidationError{Problems: []string{"no fields to update"}}
+	}
+	return updates, nil
+}
+
+// DeleteFIR soft-deletes a draft FIR. Deleted FIRs are hidden from every
+// query but kept in the collection, along with their revisions.
+func (s *FIRService) DeleteFIR(ctx context.Context, firID, officerID, reason string) error {
+	firObjectID, officerObjectID, err := parseFIRIDs(firID, officerID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	firObjectID, err := primitive.ObjectIDFromHex(firID)
+	fir, err := s.managedFIR(ctx, firObjectID, officerID, policy.FIRDelete)
 	if err != nil {
 		return err
 	}
+	if fir.Status != models.FIRStatusDraft {
+		return ErrFIRNotDraft
+	}
 
-	officerObjectID, err := primitive.ObjectIDFromHex(officerID)
+	now := time.Now()
+	collection := database.GetCollection(s.collection)
+	return database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		result, err := collection.UpdateOne(ctx, bson.M{
+			"_id":        firObjectID,
+			"officer_id": fir.OfficerID,
+			"status":     models.FIRStatusDraft,
+			"deleted_at": nil,
+		}, bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrFIRNotDraft
+		}
+
+		deleted := *fir
+		deleted.DeletedAt = &now
+		deleted.UpdatedAt = now
+		return s.recordRevision(ctx, fir, &deleted, models.RevisionDeleted, officerObjectID, strings.TrimSpace(reason))
+	})
+}
+
+// ChangeStatus moves an FIR to a new status if the state machine allows
+// it and records the change in the FIR's status history. Only the
+// registering officer can submit an FIR, since submitting signs it with
+// their key.
+func (s *FIRService) ChangeStatus(ctx context.Context, firID, officerID, status, reason string) (*models.FIR, error) {
+	firObjectID, officerObjectID, err := parseFIRIDs(firID, officerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	permission := policy.FIRStatus
+	if status == models.FIRStatusSubmitted {
+		permission = policy.FIRSubmit
+	}
+	fir, err := s.managedFIR(ctx, firObjectID, officerID, permission)
 	if err != nil {
+		return nil, err
+	}
+	if status == models.FIRStatusSubmitted && fir.OfficerID != officerObjectID {
+		return nil, ErrNotRegisteringOfficer
+	}
+	if err := checkTransition(fir.Status, status); err != nil {
+		return nil, err
+	}
+	if status == models.FIRStatusSubmitted {
+		if err := checkSubmittable(fir); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	set := bson.M{"status": status, "updated_at": now}
+	change := models.StatusChange{
+		From:      fir.Status,
+		To:        status,
+		ChangedBy: officerObjectID,
+		Reason:    strings.TrimSpace(reason),
+		ChangedAt: now,
+	}
+	if status == models.FIRStatusSubmitted {
+		return s.submitFIR(ctx, fir, set, change)
+	}
+
+	var updated *models.FIR
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		updated, err = s.applyStatus(ctx, fir, set, change)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return updated, nil
+}
 
-	updates["updated_at"] = time.Now()
-	_, err = collection.UpdateOne(
-		ctx,
-		bson.M{"_id": firObjectID, "officer_id": officerObjectID},
-		bson.M{"$set": updates},
-	)
+// submitFIR numbers, signs and submits a draft and appends it to its
+// station's ledger. All of it happens in one transaction, so a submission
+// that fails or loses a race gives its number back instead of leaving a
+// gap in the station's register, and no FIR is submitted without a ledger
+// entry.
+func (s *FIRService) submitFIR(ctx context.Context, fir *models.FIR, set bson.M, change models.StatusChange) (*models.FIR, error) {
+	officer, err := s.authService.GetUserByID(fir.OfficerID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	now := change.ChangedAt
+	set["submitted_at"] = now
+	// FIRs drafted before stations were recorded take the officer's
+	// station, which numbers them and chains them in the ledger.
+	station := fir.Station
+	if station == "" {
+		station = officer.Station
+		set["station"] = station
+	}
+	if fir.State == "" {
+		set["district"] = officer.District
+		set["state"] = officer.State
+	}
+	// Load or create the officer's signing key first, so a missing
+	// ENCRYPTION_KEY fails the submission before anything is numbered.
+	if _, _, err := s.keystore.Signer(ctx, officer.ID); err != nil {
+		return nil, err
+	}
 
-	return err
+	var updated *models.FIR
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		// Numbers are assigned on submission so drafts that are never
+		// submitted do not leave gaps in the station's register.
+		firNumber, err := s.counterService.NextFIRNumber(ctx, station, now)
+		if err != nil {
+			return err
+		}
+		set["fir_number"] = firNumber
+
+		// The officer signs the FIR as it will be stored, and the
+		// signature is saved in the same update that submits it.
+		submitted := *fir
+		submitted.Station = station
+		submitted.FIRNumber = firNumber
+		submitted.SubmittedAt = &now
+		signature, err := s.signFIR(ctx, &submitted, officer, models.SignatureRoleRegistering)
+		if err != nil {
+			return err
+		}
+		set["signature"] = signature
+
+		updated, err = s.applyStatus(ctx, fir, set, change)
+		if err != nil {
+			return err
+		}
+		return s.appendLedger(ctx, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
-func (s *FIRService) GenerateFIR(req models.GenerateFIRRequest) (string, error) {
-	return s.aiService.GenerateFIRDocument(req)
+// applyStatus writes a status change and records it as a revision, in the
+// caller's transaction. Matching on the current status makes concurrent
+// changes fail instead of skipping a state, and a submission fails if an
+// edit has queued a new analysis since the draft was read.
+func (s *FIRService) applyStatus(ctx context.Context, fir *models.FIR, set bson.M, change models.StatusChange) (*models.FIR, error) {
+	filter := bson.M{
+		"_id":        fir.ID,
+		"officer_id": fir.OfficerID,
+		"status":     fir.Status,
+		"deleted_at": nil,
+	}
+	if change.To == models.FIRStatusSubmitted {
+		filter["ai_analysis.status"] = bson.M{"$ne": models.AnalysisStatusPending}
+	}
+
+	collection := database.GetCollection(s.collection)
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set, "$push": bson.M{"status_history": change}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		current, err := s.findFIR(ctx, fir.ID, fir.OfficerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTransition(current.Status, change.To); err != nil {
+			return nil, err
+		}
+		return nil, checkSubmittable(current)
+	}
+
+	return s.recordChange(ctx, fir, models.RevisionStatusChanged, change.ChangedBy, change.Reason)
 }
 
-func (s *FIRService) SubmitFIR(firID, officerID string) error {
-	now := time.Now()
-	return s.UpdateFIR(firID, officerID, bson.M{
-		"status":       "submitted",
-		"submitted_at": now,
+// amendableFields are the FIR fields an amendment may correct.
+var amendableFields = map[string]bool{
+	"complainant_name":     true,
+	"complainant_address":  true,
+	"complainant_phone":    true,
+	"incident_date":        true,
+	"incident_time":        true,
+	"incident_location":    true,
+	"incident_description": true,
+	"witness_details":      true,
+	"evidence_details":     true,
+	"officer_remarks":      true,
+}
+
+// AddAmendment appends a correction or supplementary statement to an FIR
+// that has been submitted. The original text is left unchanged.
+func (s *FIRService) AddAmendment(ctx context.Context, firID, officerID string, req models.AmendFIRRequest) (*models.FIRAmendment, error) {
+	firObjectID, officerObjectID, err := parseFIRIDs(firID, officerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	field := strings.TrimSpace(req.Field)
+	if field != "" && !amendableFields[field] {
+		problems = append(problems, fmt.Sprintf("field %q cannot be amended", field))
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		problems = append(problems, "text is required")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		problems = append(problems, "reason is required")
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	fir, err := s.managedFIR(ctx, firObjectID, officerID, policy.FIRUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	amendment := models.FIRAmendment{
+		ID:        primitive.NewObjectID(),
+		Field:     field,
+		Text:      text,
+		Reason:    reason,
+		OfficerID: officerObjectID,
+		CreatedAt: time.Now(),
+	}
+
+	collection := database.GetCollection(s.collection)
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		result, err := collection.UpdateOne(ctx, bson.M{
+			"_id":        firObjectID,
+			"officer_id": fir.OfficerID,
+			"status":     bson.M{"$in": []string{models.FIRStatusSubmitted, models.FIRStatusUnderInvestigation}},
+			"deleted_at": nil,
+		}, bson.M{
+			"$push": bson.M{"amendments": amendment},
+			"$set":  bson.M{"updated_at": amendment.CreatedAt},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			if _, err := s.findFIR(ctx, firObjectID, fir.OfficerID); err != nil {
+				return err
+			}
+			return ErrFIRNotAmendable
+		}
+
+		_, err = s.recordChange(ctx, fir, models.RevisionAmended, officerObjectID, reason)
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &amendment, nil
+}
+
+// recordChange reloads an FIR after a change to before and records the
+// change as a revision. It must run in the transaction that made the
+// change, so the change is never applied without its revision and the
+// reload sees that change and no later one: a concurrent write to the FIR
+// conflicts with the transaction and is retried after it.
+func (s *FIRService) recordChange(ctx context.Context, before *models.FIR, action string, officerID primitive.ObjectID, reason string) (*models.FIR, error) {
+	after, err := s.findFIR(ctx, before.ID, before.OfficerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, before, after, action, officerID, reason); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// findFIR loads an officer's FIR, ignoring deleted ones.
+func (s *FIRService) findFIR(ctx context.Context, firID, officerID primitive.ObjectID) (*models.FIR, error) {
+	return s.loadFIR(ctx, bson.M{"_id": firID, "officer_id": officerID, "deleted_at": nil})
+}
+
+// managedFIR loads an FIR the user may use permission on: their own, or
+// any at their station if they manage it. Other FIRs are not found.
+func (s *FIRService) managedFIR(ctx context.Context, firID primitive.ObjectID, userID, permission string) (*models.FIR, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	fir, err := s.loadFIR(ctx, bson.M{"_id": firID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	if !policy.FIRAllows(user, permission, fir) {
+		return nil, ErrFIRNotFound
+	}
+	return fir, nil
+}
+
+// loadFIR loads and decrypts the FIR matching filter.
+func (s *FIRService) loadFIR(ctx context.Context, filter bson.M) (*models.FIR, error) {
+	var fir models.FIR
+	err := database.GetCollection(s.collection).FindOne(ctx, filter).Decode(&fir)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrFIRNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.openFIR(&fir, fir.DataKey); err != nil {
+		return nil, err
+	}
+
+	return &fir, nil
 }
 
-func (s *FIRService) GetDashboardStats(officerID string) (map[string]interface{}, error) {
+func parseFIRIDs(firID, officerID string) (primitive.ObjectID, primitive.ObjectID, error) {
+	firObjectID, err := primitive.ObjectIDFromHex(firID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, ErrFIRNotFound
+	}
+	officerObjectID, err := primitive.ObjectIDFromHex(officerID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+	return firObjectID, officerObjectID, nil
+}
+
+func (s *FIRService) SubmitFIR(ctx context.Context, firID, officerID string) error {
+	_, err := s.ChangeStatus(ctx, firID, officerID, models.FIRStatusSubmitted, "")
+	return err
+}
+
+// GetDashboardStats summarises the FIRs in the user's jurisdiction.
+func (s *FIRService) GetDashboardStats(userID string) (map[string]interface{}, error) {
 	collection := database.GetCollection(s.collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	objectID, err := primitive.ObjectIDFromHex(officerID)
+	user, scope, filter, err := s.readScope(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Aggregate stats
 	pipeline := []bson.M{
-		{"$match": bson.M{"officer_id": objectID}},
+		{"$match": filter},
 		{"$group": bson.M{
-			"_id": "$status",
+			"_id":   "$status",
 			"count": bson.M{"$sum": 1},
 		}},
 	}
@@ -223,7 +741,7 @@ func (s *FIRService) GetDashboardStats(officerID string) (map[string]interface{}
 	}
 
 	// Get recent cases
-	recentCases, _, err := s.GetFIRs(officerID, 1, 5)
+	recentCases, _, err := s.listFIRs(ctx, filter, 1, 5)
 	if err != nil {
 		return nil, err
 	}
@@ -232,25 +750,33 @@ func (s *FIRService) GetDashboardStats(officerID string) (map[string]interface{}
 	for _, count := range statusCounts {
 		total += count
 	}
+	if err := s.auditService.Record(ctx, user, models.AuditDashboardStats, scope, nil, int64(total)); err != nil {
+		return nil, err
+	}
 
 	// Calculate accuracy rate (mock calculation)
 	accuracyRate := 87 // In real implementation, this would be calculated based on AI predictions vs actual outcomes
 
 	return map[string]interface{}{
 		"totalFIRs":     total,
-		"pendingFIRs":   statusCounts["draft"] + statusCounts["submitted"],
-		"completedFIRs": statusCounts["closed"],
+		"pendingFIRs":   statusCounts[models.FIRStatusDraft] + statusCounts[models.FIRStatusSubmitted] + statusCounts[models.FIRStatusUnderInvestigation],
+		"completedFIRs": statusCounts[models.FIRStatusClosed],
 		"accuracyRate":  accuracyRate,
 		"recentCases":   s.formatRecentCases(recentCases),
+		"scope":         scope,
 	}, nil
 }
 
 func (s *FIRService) formatRecentCases(firs []models.FIR) []map[string]interface{} {
 	var cases []map[string]interface{}
 	for _, fir := range firs {
+		firNumber := fir.FIRNumber
+		if firNumber == "" {
+			firNumber = "Draft"
+		}
 		cases = append(cases, map[string]interface{}{
 			"id":         fir.ID.Hex(),
-			"title":      fmt.Sprintf("%s - %s", fir.FIRNumber, fir.ComplainantName),
+			"title":      fmt.Sprintf("%s - %s", firNumber, fir.ComplainantName),
 			"status":     fir.Status,
 			"date":       fir.CreatedAt.Format("2006-01-02"),
 			"confidence": fir.AIAnalysis.Confidence,
@@ -263,21 +789,21 @@ func (s *FIRService) determinePriority(description string) string {
 	// Simple priority determination based on keywords
 	// In a real implementation, this would use more sophisticated NLP
 	description = utils.ToLower(description)
-	
+
 	highPriorityKeywords := []string{"murder", "rape", "kidnapping", "terrorism", "bomb", "weapon", "gun"}
 	mediumPriorityKeywords := []string{"assault", "theft", "burglary", "fraud", "harassment"}
-	
+
 	for _, keyword := range highPriorityKeywords {
 		if utils.Contains(description, keyword) {
 			return "high"
 		}
 	}
-	
+
 	for _, keyword := range mediumPriorityKeywords {
 		if utils.Contains(description, keyword) {
 			return "medium"
 		}
 	}
-	
+
 	return "low"
-}
\ No newline at end of file
+}
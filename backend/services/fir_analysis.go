@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/jobs"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// JobAnalyzeFIR is the job type that runs the AI analysis of an FIR.
+const JobAnalyzeFIR = "fir.analyze"
+
+// errFIRChanged is returned when an FIR is edited or submitted while its
+// analysis is running. The job is retried against the new content.
+var errFIRChanged = errors.New("FIR changed during analysis")
+
+type analyzeFIRPayload struct {
+	FIRID string `json:"fir_id"`
+}
+
+// AnalysisResult is the result stored on a completed analysis job.
+type AnalysisResult struct {
+	FIRID   string `json:"fir_id"`
+	Status  string `json:"status"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// RegisterJobs registers the FIR job handlers with pool.
+func (s *FIRService) RegisterJobs(pool *jobs.Pool) {
+	pool.Register(JobAnalyzeFIR, s.runAnalysisJob)
+}
+
+// queueAnalysis enqueues the AI analysis of an FIR whose analysis is
+// pending and records the job on the FIR. If the job cannot be queued the
+// analysis is run inline instead, so the FIR is never left pending.
+func (s *FIRService) queueAnalysis(ctx context.Context, fir *models.FIR) (*models.FIR, error) {
+	job, err := s.queue.Enqueue(ctx, JobAnalyzeFIR, fir.OfficerID, analyzeFIRPayload{FIRID: fir.ID.Hex()})
+	if err != nil {
+		log.Printf("Failed to queue analysis of FIR %s, analysing inline: %v", fir.ID.Hex(), err)
+		analyzed, err := s.analyzeFIR(ctx, fir, nil)
+		if err != nil && !errors.Is(err, errFIRChanged) {
+			// There is no job to retry an inline analysis.
+			log.Printf("Analysis of FIR %s failed: %v", fir.ID.Hex(), err)
+			s.failAnalysis(fir.ID)
+			fir.AIAnalysis.Status = models.AnalysisStatusFailed
+			return fir, nil
+		}
+		return analyzed, err
+	}
+
+	// The job may already have finished, in which case its result is left
+	// alone.
+	_, err = database.GetCollection(s.collection).UpdateOne(ctx, bson.M{
+		"_id":                fir.ID,
+		"ai_analysis.status": models.AnalysisStatusPending,
+	}, bson.M{"$set": bson.M{"ai_analysis.job_id": job.ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	fir.AIAnalysis.JobID = &job.ID
+	return fir, nil
+}
+
+// runAnalysisJob analyses the FIR named in the job's payload. FIRs whose
+// analysis is no longer pending were already handled by a later job and
+// are skipped.
+func (s *FIRService) runAnalysisJob(ctx context.Context, job *jobs.Job) (interface{}, error) {
+	var payload analyzeFIRPayload
+	if err := job.Decode(&payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+	firID, err := primitive.ObjectIDFromHex(payload.FIRID)
+	if err != nil {
+		return nil, jobs.Permanent(ErrFIRNotFound)
+	}
+
+	fir, err := s.findFIR(ctx, firID, job.OwnerID)
+	if errors.Is(err, ErrFIRNotFound) {
+		return nil, jobs.Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fir.AIAnalysis.Status != models.AnalysisStatusPending {
+		return AnalysisResult{FIRID: payload.FIRID, Status: fir.AIAnalysis.Status, Skipped: true}, nil
+	}
+
+	if _, err := s.analyzeFIR(ctx, fir, &job.ID); err != nil {
+		if job.LastAttempt() {
+			s.failAnalysis(fir.ID)
+		}
+		return nil, err
+	}
+	return AnalysisResult{FIRID: payload.FIRID, Status: models.AnalysisStatusCompleted}, nil
+}
+
+// analyzeFIR detects the language of an FIR's incident description,
+// translates it to English and runs the AI analysis on the English text.
+// The English text is stored alongside the original unless the original is
+// English. A failed translation fails the analysis so the job is retried:
+// the keyword analyzer only understands English, and analysing the
+// original would complete with sections that are never revisited. The
+// language and applicable sections are only replaced while the FIR is a
+// draft, since they are part of the content sealed into the ledger on
+// submission.
+func (s *FIRService) analyzeFIR(ctx context.Context, fir *models.FIR, jobID *primitive.ObjectID) (*models.FIR, error) {
+	english, language, err := s.aiService.EnglishText(ctx, fir.IncidentDescription, fir.Language)
+	if err != nil {
+		return nil, fmt.Errorf("translating FIR %s from %s: %w", fir.ID.Hex(), language, err)
+	}
+	analysis, suggestedLaws := s.aiService.AnalyzeIncident(ctx, english, fir.IncidentDate)
+	if language == "en" {
+		// The original is already English.
+		english = ""
+	}
+	analysis.Status = models.AnalysisStatusCompleted
+	analysis.JobID = jobID
+
+	updates := bson.M{
+		"ai_analysis":         analysis,
+		"suggested_laws":      suggestedLaws,
+		"description_english": english,
+		"updated_at":          time.Now(),
+	}
+	if fir.Status == models.FIRStatusDraft {
+		updates["language"] = languageName(language)
+		applicableSections := []string{}
+		for _, law := range suggestedLaws {
+			applicableSections = append(applicableSections, law.Section)
+		}
+		updates["applicable_sections"] = applicableSections
+	}
+
+	var updated *models.FIR
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		result, err := database.GetCollection(s.collection).UpdateOne(ctx, bson.M{
+			"_id":                  fir.ID,
+			"deleted_at":           nil,
+			"status":               fir.Status,
+			"incident_description": fir.IncidentDescription,
+			"incident_date":        fir.IncidentDate,
+			"ai_analysis.status":   models.AnalysisStatusPending,
+		}, bson.M{"$set": updates})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errFIRChanged
+		}
+
+		updated, err = s.recordChange(ctx, fir, models.RevisionAnalyzed, fir.OfficerID, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// failAnalysis marks a pending analysis as failed once its job has given
+// up.
+func (s *FIRService) failAnalysis(firID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := database.GetCollection(s.collection).UpdateOne(ctx, bson.M{
+		"_id":                firID,
+		"ai_analysis.status": models.AnalysisStatusPending,
+	}, bson.M{"$set": bson.M{
+		"ai_analysis.status": models.AnalysisStatusFailed,
+		"updated_at":         time.Now(),
+	}})
+	if err != nil {
+		log.Printf("Failed to mark analysis of FIR %s as failed: %v", firID.Hex(), err)
+	}
+}
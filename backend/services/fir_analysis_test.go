@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errTranslatorDown = errors.New("translator unavailable")
+
+// failingTranslator detects languages by script but cannot translate.
+type failingTranslator struct{}
+
+func (failingTranslator) Name() string { return "failing" }
+
+func (failingTranslator) Detect(ctx context.Context, text, hint string) (string, error) {
+	return NewFakeTranslator().Detect(ctx, text, hint)
+}
+
+func (failingTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return "", errTranslatorDown
+}
+
+func TestAnalyzeFIRTranslationFailure(t *testing.T) {
+	service := &FIRService{aiService: &AIService{translator: failingTranslator{}}}
+
+	tests := []struct {
+		name         string
+		description  string
+		wantLanguage string
+	}{
+		{"Hindi", "मेरा फोन छीन लिया गया।", "hi"},
+		{"Tamil", "என் கைபேசி திருடப்பட்டது.", "ta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, language, err := service.aiService.EnglishText(context.Background(), tt.description, "")
+			if !errors.Is(err, errTranslatorDown) || language != tt.wantLanguage {
+				t.Errorf("EnglishText() language, error = %q, %v, want %q, %v", language, err, tt.wantLanguage, errTranslatorDown)
+			}
+
+			fir := &models.FIR{ID: primitive.NewObjectID(), IncidentDescription: tt.description}
+			if _, err := service.analyzeFIR(context.Background(), fir, nil); !errors.Is(err, errTranslatorDown) {
+				t.Errorf("analyzeFIR() error = %v, want the translation error so the job is retried", err)
+			}
+		})
+	}
+}
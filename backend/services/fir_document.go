@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GenerateFIR drafts an FIR document from an incident description in any
+// supported language, without saving an FIR. The document is rendered from
+// the template for the officer's state, with the officer's station and the
+// sections the analysis found.
+func (s *FIRService) GenerateFIR(ctx context.Context, officerID string, req models.GenerateFIRRequest) (*models.GeneratedFIR, error) {
+	officer, err := s.authService.GetUserByID(officerID)
+	if err != nil {
+		return nil, err
+	}
+
+	incidentDate, err := time.Parse("2006-01-02", req.IncidentDate)
+	if err != nil {
+		incidentDate = time.Now()
+	}
+
+	// Analysis and drafting work on the English text. If translation fails
+	// the original is drafted, still recorded in the language it was
+	// detected as.
+	description, sourceLanguage, err := s.aiService.EnglishText(ctx, req.IncidentDescription, req.Language)
+	if err != nil {
+		log.Printf("Translating incident description from %s failed, using the original: %v", sourceLanguage, err)
+		description = req.IncidentDescription
+	}
+	_, suggestedLaws := s.aiService.AnalyzeIncident(ctx, description, incidentDate)
+
+	fir := &models.FIR{
+		OfficerID:           officer.ID,
+		Station:             officer.Station,
+		ComplainantName:     req.ComplainantName,
+		ComplainantAddress:  req.ComplainantAddress,
+		ComplainantPhone:    req.ComplainantPhone,
+		IncidentDate:        incidentDate,
+		IncidentTime:        req.IncidentTime,
+		IncidentLocation:    req.IncidentLocation,
+		IncidentDescription: req.IncidentDescription,
+		WitnessDetails:      req.WitnessDetails,
+		EvidenceDetails:     req.EvidenceDetails,
+		OfficerRemarks:      req.OfficerRemarks,
+		Language:            languageName(sourceLanguage),
+		Status:              models.FIRStatusDraft,
+		SuggestedLaws:       suggestedLaws,
+	}
+	if description != req.IncidentDescription {
+		fir.DescriptionEnglish = description
+	}
+	narrative := s.aiService.draftNarrative(ctx, description)
+
+	return s.renderFIR(ctx, fir, officer, narrative, req.Bilingual, req.Language)
+}
+
+// Document renders a saved FIR with the template for its officer's state.
+// With bilingual set, the document is also produced in language, or in the
+// FIR's own language when language is empty.
+func (s *FIRService) Document(ctx context.Context, firID, officerID string, bilingual bool, language string) (*models.GeneratedFIR, error) {
+	fir, err := s.scopedFIR(ctx, firID, officerID, models.AuditFIRDocument, false)
+	if err != nil {
+		return nil, err
+	}
+	officer, err := s.authService.GetUserByID(fir.OfficerID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return s.renderFIR(ctx, fir, officer, "", bilingual, language)
+}
+
+func (s *FIRService) renderFIR(ctx context.Context, fir *models.FIR, officer *models.User, narrative string, bilingual bool, language string) (*models.GeneratedFIR, error) {
+	tmpl, err := s.documentService.ResolveTemplate(ctx, models.TemplateKindFIR, officer.State)
+	if err != nil {
+		return nil, err
+	}
+	data := NewDocumentData(fir, officer, narrative)
+	text, err := s.documentService.Render(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	document := &models.GeneratedFIR{Text: text, Template: templateRef(tmpl)}
+
+	sourceLanguage := languageCode(fir.Language)
+	if fir.DescriptionEnglish == "" {
+		sourceLanguage = "en"
+	}
+	if language = languageCode(language); language == "" {
+		language = sourceLanguage
+	}
+	if !bilingual || language == "en" {
+		return document, nil
+	}
+
+	err = s.aiService.TranslateDocument(ctx, document, data.Narrative, fir.IncidentDescription, sourceLanguage, language)
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// PreviewTemplate renders a template for an admin without saving anything.
+// Any FIR can be used as data, whoever registered it.
+func (s *FIRService) PreviewTemplate(ctx context.Context, req models.PreviewTemplateRequest) (*models.GeneratedFIR, error) {
+	var tmpl *models.DocumentTemplate
+	var err error
+	switch {
+	case req.Body != "":
+		tmpl = &models.DocumentTemplate{Kind: templateKind(req.Kind), State: normalizeState(req.State), Body: req.Body}
+	case req.TemplateID != "":
+		tmpl, err = s.documentService.GetTemplate(ctx, req.TemplateID)
+	default:
+		tmpl, err = s.documentService.ResolveTemplate(ctx, req.Kind, req.State)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := sampleDocumentData()
+	if req.FIRID != "" {
+		firObjectID, err := primitive.ObjectIDFromHex(req.FIRID)
+		if err != nil {
+			return nil, ErrFIRNotFound
+		}
+		firs, err := s.firsByID(ctx, bson.M{"_id": firObjectID, "deleted_at": nil})
+		if err != nil {
+			return nil, err
+		}
+		fir, ok := firs[firObjectID]
+		if !ok {
+			return nil, ErrFIRNotFound
+		}
+		officer, err := s.authService.GetUserByID(fir.OfficerID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		data = NewDocumentData(fir, officer, "")
+	}
+
+	text, err := s.documentService.Render(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GeneratedFIR{Text: text, Template: templateRef(tmpl)}, nil
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Purposes the FIR encryption keys are derived for.
+const (
+	firDataKeysPurpose  = "fir-data-keys"
+	blindIndexPurpose   = "complainant-blind-index"
+	encryptedFieldLabel = "enc:v1:"
+)
+
+// encryptedFIRFields returns the FIR fields that are stored encrypted, by
+// BSON name.
+func encryptedFIRFields(fir *models.FIR) map[string]*string {
+	return map[string]*string{
+		"complainant_name":    &fir.ComplainantName,
+		"complainant_address": &fir.ComplainantAddress,
+		"complainant_phone":   &fir.ComplainantPhone,
+	}
+}
+
+// KeyRotationReport counts the records cmd/rotate-keys re-encrypted under
+// the current encryption key.
+type KeyRotationReport struct {
+	KeyID string `json:"key_id"`
+	// RewrappedFIRs had their data key re-encrypted and EncryptedFIRs were
+	// stored before encryption and have now been encrypted.
+	RewrappedFIRs int `json:"rewrapped_firs"`
+	EncryptedFIRs int `json:"encrypted_firs"`
+	OfficerKeys   int `json:"officer_keys"`
+}
+
+// sealFIR returns a copy of fir as it is stored: the designated fields are
+// encrypted under the FIR's data key and the blind indexes are set. An FIR
+// without a data key is given one. Without ENCRYPTION_KEY the fields are
+// stored as they are.
+func (s *FIRService) sealFIR(fir *models.FIR) (*models.FIR, error) {
+	sealed := *fir
+	if !s.keyring.configured() {
+		return &sealed, nil
+	}
+
+	if fir.DataKey == nil {
+		dataKey := make([]byte, 32)
+		if _, err := rand.Read(dataKey); err != nil {
+			return nil, err
+		}
+		wrapped, err := s.wrapDataKey(dataKey, fir.ID)
+		if err != nil {
+			return nil, err
+		}
+		fir.DataKey = wrapped
+	}
+	dataKey, err := s.unwrapDataKey(fir.DataKey, fir.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed.DataKey = fir.DataKey
+	for field, value := range encryptedFIRFields(&sealed) {
+		if *value, err = sealField(dataKey, fir.ID, field, *value); err != nil {
+			return nil, err
+		}
+	}
+	sealed.ComplainantNameIndex = s.blindIndex(s.keyring.currentID, "complainant_name", fir.ComplainantName)
+	sealed.ComplainantPhoneIndex = s.blindIndex(s.keyring.currentID, "complainant_phone", fir.ComplainantPhone)
+	return &sealed, nil
+}
+
+// sealUpdates encrypts the designated fields in a $set document for fir
+// and updates its blind indexes to match. The other designated fields are
+// re-encrypted with them, so an FIR stored before encryption is encrypted
+// as a whole the first time one of them changes.
+func (s *FIRService) sealUpdates(fir *models.FIR, updates bson.M) error {
+	updated := *fir
+	changed := false
+	for field, value := range encryptedFIRFields(&updated) {
+		if update, ok := updates[field].(string); ok {
+			*value = update
+			changed = true
+		}
+	}
+	if !changed || !s.keyring.configured() {
+		return nil
+	}
+
+	sealed, err := s.sealFIR(&updated)
+	if err != nil {
+		return err
+	}
+	for field, value := range encryptedFIRFields(sealed) {
+		updates[field] = *value
+	}
+	updates["complainant_name_index"] = sealed.ComplainantNameIndex
+	updates["complainant_phone_index"] = sealed.ComplainantPhoneIndex
+	updates["data_key"] = sealed.DataKey
+	return nil
+}
+
+// openFIR decrypts fir's designated fields in place with the data key
+// wrapped. Values stored before encryption are left as they are.
+func (s *FIRService) openFIR(fir *models.FIR, wrapped *models.WrappedKey) error {
+	var dataKey []byte
+	for field, value := range encryptedFIRFields(fir) {
+		if !strings.HasPrefix(*value, encryptedFieldLabel) {
+			continue
+		}
+		if dataKey == nil {
+			if wrapped == nil {
+				return fmt.Errorf("FIR %s has encrypted fields but no data key", fir.ID.Hex())
+			}
+			var err error
+			if dataKey, err = s.unwrapDataKey(wrapped, fir.ID); err != nil {
+				return err
+			}
+		}
+		plaintext, err := openField(dataKey, fir.ID, field, *value)
+		if err != nil {
+			return err
+		}
+		*value = plaintext
+	}
+	return nil
+}
+
+// openFIRs decrypts a list of FIRs in place.
+func (s *FIRService) openFIRs(firs []models.FIR) error {
+	for i := range firs {
+		if err := s.openFIR(&firs[i], firs[i].DataKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotFIR returns a copy of an FIR to store in a revision. The
+// snapshot is encrypted under the FIR's data key but does not carry it, so
+// rotating the encryption key never touches the hash-chained revisions.
+// FIRs stored before encryption have no data key and are kept as they are.
+func (s *FIRService) snapshotFIR(fir *models.FIR) (*models.FIR, error) {
+	if fir.DataKey == nil {
+		snapshot := *fir
+		return &snapshot, nil
+	}
+	snapshot, err := s.sealFIR(fir)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.DataKey = nil
+	return snapshot, nil
+}
+
+// openSnapshots decrypts revision snapshots of one FIR with the FIR's
+// current data key.
+func (s *FIRService) openSnapshots(ctx context.Context, firID primitive.ObjectID, snapshots ...*models.FIR) error {
+	var fir models.FIR
+	err := database.GetCollection(s.collection).FindOne(ctx,
+		bson.M{"_id": firID},
+		options.FindOne().SetProjection(bson.M{"data_key": 1}),
+	).Decode(&fir)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		if err := s.openFIR(snapshot, fir.DataKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindFIRsByComplainant returns the FIRs in the user's jurisdiction whose
+// complainant has the given phone number or name, newest first. Only the
+// blind indexes are searched, so both must match exactly once normalised:
+// the last ten digits of a phone number, and a name in lower case with
+// single spaces.
+func (s *FIRService) FindFIRsByComplainant(userID, phone, name string, page, limit int) (*FIRList, error) {
+	if !s.keyring.configured() {
+		return nil, ErrEncryptionNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, scope, filter, err := s.readScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	lookups := []struct {
+		field string
+		value string
+	}{
+		{"complainant_phone", phone},
+		{"complainant_name", name},
+	}
+	for _, lookup := range lookups {
+		if strings.TrimSpace(lookup.value) == "" {
+			continue
+		}
+		// Records not yet re-encrypted after a key rotation are indexed
+		// under an older key.
+		var indexes []string
+		for _, id := range s.keyring.ids() {
+			if index := s.blindIndex(id, lookup.field, lookup.value); index != "" {
+				indexes = append(indexes, index)
+			}
+		}
+		if len(indexes) == 0 {
+			return nil, &ValidationError{Problems: []string{lookup.field + " has nothing to search for"}}
+		}
+		filter[lookup.field+"_index"] = bson.M{"$in": indexes}
+	}
+
+	return s.listScoped(ctx, user, scope, models.AuditFIRLookup, filter, page, limit)
+}
+
+// RotateKeys re-encrypts every FIR data key and officer signing key that is
+// not under the current encryption key, recomputing the FIRs' blind
+// indexes, and encrypts FIRs stored before encryption was enabled.
+func (s *FIRService) RotateKeys(ctx context.Context) (*KeyRotationReport, error) {
+	if !s.keyring.configured() {
+		return nil, ErrEncryptionNotConfigured
+	}
+	report := &KeyRotationReport{KeyID: s.keyring.currentID}
+
+	collection := database.GetCollection(s.collection)
+	cursor, err := collection.Find(ctx, bson.M{"data_key.key_id": bson.M{"$ne": s.keyring.currentID}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var stored models.FIR
+		if err := cursor.Decode(&stored); err != nil {
+			return report, err
+		}
+		fir := stored
+		if err := s.openFIR(&fir, fir.DataKey); err != nil {
+			return report, err
+		}
+
+		var set bson.M
+		if fir.DataKey == nil {
+			sealed, err := s.sealFIR(&fir)
+			if err != nil {
+				return report, err
+			}
+			set = bson.M{"data_key": sealed.DataKey}
+			for field, value := range encryptedFIRFields(sealed) {
+				set[field] = *value
+			}
+		} else {
+			dataKey, err := s.unwrapDataKey(fir.DataKey, fir.ID)
+			if err != nil {
+				return report, err
+			}
+			wrapped, err := s.wrapDataKey(dataKey, fir.ID)
+			if err != nil {
+				return report, err
+			}
+			set = bson.M{"data_key": wrapped}
+		}
+		set["complainant_name_index"] = s.blindIndex(s.keyring.currentID, "complainant_name", fir.ComplainantName)
+		set["complainant_phone_index"] = s.blindIndex(s.keyring.currentID, "complainant_phone", fir.ComplainantPhone)
+
+		// Matching on the stored data key and fields leaves an FIR alone
+		// if its complainant details were edited since it was read. The
+		// edit sealed them under the current key, and a data key still
+		// under an old one is re-encrypted by the next run.
+		filter := bson.M{"_id": stored.ID, "data_key": stored.DataKey}
+		for field, value := range encryptedFIRFields(&stored) {
+			filter[field] = *value
+		}
+		result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+		if err != nil {
+			return report, err
+		}
+		if result.MatchedCount == 0 {
+			continue
+		}
+		if stored.DataKey == nil {
+			report.EncryptedFIRs++
+		} else {
+			report.RewrappedFIRs++
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return report, err
+	}
+
+	report.OfficerKeys, err = s.keystore.Rotate(ctx)
+	return report, err
+}
+
+// wrapDataKey encrypts an FIR's data key under the current encryption key.
+func (s *FIRService) wrapDataKey(dataKey []byte, firID primitive.ObjectID) (*models.WrappedKey, error) {
+	key, err := s.keyring.key(s.keyring.currentID, firDataKeysPurpose)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := sealGCM(key, dataKey, firID[:])
+	if err != nil {
+		return nil, err
+	}
+	return &models.WrappedKey{KeyID: s.keyring.currentID, Key: wrapped}, nil
+}
+
+func (s *FIRService) unwrapDataKey(wrapped *models.WrappedKey, firID primitive.ObjectID) ([]byte, error) {
+	key, err := s.keyring.key(wrapped.KeyID, firDataKeysPurpose)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := openGCM(key, wrapped.Key, firID[:])
+	if err != nil {
+		return nil, fmt.Errorf("data key of FIR %s cannot be decrypted with encryption key %q", firID.Hex(), wrapped.KeyID)
+	}
+	return dataKey, nil
+}
+
+// sealField encrypts one field of an FIR. The FIR ID and field name are
+// authenticated with it, so a value copied to another field or FIR fails
+// to decrypt. Empty values are left empty.
+func sealField(dataKey []byte, firID primitive.ObjectID, field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	sealed, err := sealGCM(dataKey, []byte(value), fieldAAD(firID, field))
+	if err != nil {
+		return "", err
+	}
+	return encryptedFieldLabel + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func openField(dataKey []byte, firID primitive.ObjectID, field, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedFieldLabel))
+	if err != nil {
+		return "", fmt.Errorf("%s of FIR %s is not valid ciphertext", field, firID.Hex())
+	}
+	plaintext, err := openGCM(dataKey, sealed, fieldAAD(firID, field))
+	if err != nil {
+		return "", fmt.Errorf("%s of FIR %s cannot be decrypted", field, firID.Hex())
+	}
+	return string(plaintext), nil
+}
+
+func fieldAAD(firID primitive.ObjectID, field string) []byte {
+	return append(firID[:], field...)
+}
+
+// blindIndex is the HMAC-SHA256 of a normalised name or phone number under
+// a key derived from the encryption key id, or "" if there is nothing to
+// index.
+func (s *FIRService) blindIndex(id, field, value string) string {
+	switch field {
+	case "complainant_phone":
+		value = normalizePhone(value)
+	default:
+		value = normalizeName(value)
+	}
+	if value == "" {
+		return ""
+	}
+	key, err := s.keyring.key(id, blindIndexPurpose)
+	if err != nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(field + "\x00" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalizePhone keeps a phone number's digits and drops any country or
+// trunk prefix by keeping the last ten.
+func normalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+	return digits
+}
+
+// normalizeName lower-cases a name and collapses its punctuation and
+// spacing to single spaces.
+func normalizeName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testEncryptionService(cfg config.Config) *FIRService {
+	return &FIRService{keyring: NewKeyring(&cfg)}
+}
+
+func TestSealOpenField(t *testing.T) {
+	dataKey := make([]byte, 32)
+	rand.Read(dataKey)
+	otherKey := make([]byte, 32)
+	rand.Read(otherKey)
+	firID := primitive.NewObjectID()
+
+	sealed, err := sealField(dataKey, firID, "complainant_name", "Ravi Kumar")
+	if err != nil {
+		t.Fatalf("sealField() error = %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedFieldLabel) || strings.Contains(sealed, "Ravi") {
+		t.Fatalf("sealField() = %q, want labelled ciphertext", sealed)
+	}
+	again, _ := sealField(dataKey, firID, "complainant_name", "Ravi Kumar")
+	if again == sealed {
+		t.Errorf("sealField() is deterministic; want a fresh nonce each time")
+	}
+
+	tests := []struct {
+		name    string
+		key     []byte
+		firID   primitive.ObjectID
+		field   string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"round trip", dataKey, firID, "complainant_name", sealed, "Ravi Kumar", false},
+		{"other field", dataKey, firID, "complainant_address", sealed, "", true},
+		{"other FIR", dataKey, primitive.NewObjectID(), "complainant_name", sealed, "", true},
+		{"other key", otherKey, firID, "complainant_name", sealed, "", true},
+		{"tampered", dataKey, firID, "complainant_name", sealed[:len(sealed)-4] + "AAA=", "", true},
+		{"not base64", dataKey, firID, "complainant_name", encryptedFieldLabel + "%%%", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := openField(tt.key, tt.firID, tt.field, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("openField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if empty, err := sealField(dataKey, firID, "complainant_phone", ""); empty != "" || err != nil {
+		t.Errorf("sealField() of an empty value = %q, %v, want it left empty", empty, err)
+	}
+}
+
+func TestSealOpenFIR(t *testing.T) {
+	current := config.Config{EncryptionKey: "secret one", EncryptionKeyID: "k1"}
+	rotated := config.Config{EncryptionKey: "secret two", EncryptionKeyID: "k2", EncryptionOldKeys: "k1:secret one"}
+
+	tests := []struct {
+		name        string
+		sealWith    config.Config
+		openWith    config.Config
+		wantSealed  bool
+		wantOpenErr bool
+	}{
+		{"same key", current, current, true, false},
+		{"after rotation", current, rotated, true, false},
+		{"old key dropped", current, config.Config{EncryptionKey: "secret two", EncryptionKeyID: "k2"}, true, true},
+		{"encryption not configured", config.Config{EncryptionKeyID: "k1"}, current, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fir := &models.FIR{
+				ID:                 primitive.NewObjectID(),
+				ComplainantName:    "Ravi Kumar",
+				ComplainantAddress: "12 MG Road, Bengaluru",
+				ComplainantPhone:   "+91 98450 12345",
+				IncidentLocation:   "MG Road",
+			}
+			stored, err := testEncryptionService(tt.sealWith).sealFIR(fir)
+			if err != nil {
+				t.Fatalf("sealFIR() error = %v", err)
+			}
+			if sealed := strings.HasPrefix(stored.ComplainantName, encryptedFieldLabel); sealed != tt.wantSealed {
+				t.Fatalf("complainant name sealed = %v, want %v", sealed, tt.wantSealed)
+			}
+			if stored.IncidentLocation != "MG Road" {
+				t.Errorf("IncidentLocation = %q, want it stored as it is", stored.IncidentLocation)
+			}
+			if tt.wantSealed && (stored.ComplainantNameIndex == "" || stored.ComplainantPhoneIndex == "") {
+				t.Errorf("blind indexes not set")
+			}
+
+			err = testEncryptionService(tt.openWith).openFIR(stored, stored.DataKey)
+			if (err != nil) != tt.wantOpenErr {
+				t.Fatalf("openFIR() error = %v, wantErr %v", err, tt.wantOpenErr)
+			}
+			if err == nil && (stored.ComplainantName != fir.ComplainantName || stored.ComplainantAddress != fir.ComplainantAddress || stored.ComplainantPhone != fir.ComplainantPhone) {
+				t.Errorf("openFIR() = %q, %q, %q, want the original values", stored.ComplainantName, stored.ComplainantAddress, stored.ComplainantPhone)
+			}
+		})
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	service := testEncryptionService(config.Config{EncryptionKey: "secret one", EncryptionKeyID: "k1", EncryptionOldKeys: "k0:secret zero"})
+	index := func(id, field, value string) string {
+		return service.blindIndex(id, field, value)
+	}
+
+	tests := []struct {
+		name     string
+		a, b     string
+		wantSame bool
+	}{
+		{"phone with country code", index("k1", "complainant_phone", "+91 98450-12345"), index("k1", "complainant_phone", "9845012345"), true},
+		{"phone with trunk prefix", index("k1", "complainant_phone", "098450 12345"), index("k1", "complainant_phone", "9845012345"), true},
+		{"different phones", index("k1", "complainant_phone", "9845012345"), index("k1", "complainant_phone", "9845012346"), false},
+		{"name case and spacing", index("k1", "complainant_name", "  RAVI   Kumar. "), index("k1", "complainant_name", "ravi kumar"), true},
+		{"Devanagari name", index("k1", "complainant_name", "रवि  कुमार"), index("k1", "complainant_name", "रवि कुमार"), true},
+		{"different names", index("k1", "complainant_name", "Ravi Kumar"), index("k1", "complainant_name", "Ravi Kumari"), false},
+		{"same value in another field", index("k1", "complainant_name", "9845012345"), index("k1", "complainant_phone", "9845012345"), false},
+		{"same value under another key", index("k0", "complainant_phone", "9845012345"), index("k1", "complainant_phone", "9845012345"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a == "" || tt.b == "" {
+				t.Fatalf("blindIndex() returned an empty index")
+			}
+			if same := tt.a == tt.b; same != tt.wantSame {
+				t.Errorf("indexes equal = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "  ", "-- --"} {
+		if got := index("k1", "complainant_name", value); got != "" {
+			t.Errorf("blindIndex(%q) = %q, want no index", value, got)
+		}
+	}
+	if got := index("k9", "complainant_name", "Ravi Kumar"); got != "" {
+		t.Errorf("blindIndex() under an unknown key = %q, want no index", got)
+	}
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrFIRNotSubmitted is returned when a ledger check targets a draft.
+var ErrFIRNotSubmitted = errors.New("FIR has not been submitted")
+
+// canonicalFIR is the content of an FIR that is fixed once it is
+// submitted. Status, amendments and the AI analysis legitimately change
+// afterwards and are left out. Field order is part of the hash.
+type canonicalFIR struct {
+	ID                  string   `json:"id"`
+	FIRNumber           string   `json:"fir_number"`
+	Station             string   `json:"station"`
+	OfficerID           string   `json:"officer_id"`
+	ComplainantName     string   `json:"complainant_name"`
+	ComplainantAddress  string   `json:"complainant_address"`
+	ComplainantPhone    string   `json:"complainant_phone"`
+	IncidentDate        string   `json:"incident_date"`
+	IncidentTime        string   `json:"incident_time"`
+	IncidentLocation    string   `json:"incident_location"`
+	IncidentDescription string   `json:"incident_description"`
+	WitnessDetails      string   `json:"witness_details"`
+	EvidenceDetails     string   `json:"evidence_details"`
+	OfficerRemarks      string   `json:"officer_remarks"`
+	Language            string   `json:"language"`
+	ApplicableSections  []string `json:"applicable_sections"`
+	SubmittedAt         string   `json:"submitted_at"`
+}
+
+// LedgerProblem is a check that failed while verifying a ledger. Seq is 0
+// for a submitted FIR that has no ledger entry.
+type LedgerProblem struct {
+	Seq       int    `json:"seq"`
+	FIRID     string `json:"fir_id"`
+	FIRNumber string `json:"fir_number"`
+	Problem   string `json:"problem"`
+}
+
+// LedgerReport is the result of recomputing one station's ledger.
+type LedgerReport struct {
+	Station  string          `json:"station"`
+	Entries  int             `json:"entries"`
+	Valid    bool            `json:"valid"`
+	Problems []LedgerProblem `json:"problems"`
+}
+
+// FIRVerification reports whether a submitted FIR and the ledger entries
+// up to and including its own are unaltered.
+type FIRVerification struct {
+	FIRID       string          `json:"fir_id"`
+	FIRNumber   string          `json:"fir_number"`
+	Station     string          `json:"station"`
+	Seq         int             `json:"seq,omitempty"`
+	ContentHash string          `json:"content_hash,omitempty"`
+	Hash        string          `json:"hash,omitempty"`
+	Valid       bool            `json:"valid"`
+	Problems    []LedgerProblem `json:"problems"`
+}
+
+// appendLedger hashes a submitted FIR and chains it onto its station's
+// ledger. It runs in the submission's transaction: two submissions at one
+// station that race for the next sequence number conflict, and the
+// transaction retries the loser.
+func (s *FIRService) appendLedger(ctx context.Context, fir *models.FIR) error {
+	collection := database.GetCollection(s.ledgerCollection)
+
+	entry := models.LedgerEntry{
+		ID:          primitive.NewObjectID(),
+		Station:     fir.Station,
+		Seq:         1,
+		FIRID:       fir.ID,
+		FIRNumber:   fir.FIRNumber,
+		ContentHash: firContentHash(fir),
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	var last models.LedgerEntry
+	err := collection.FindOne(ctx,
+		bson.M{"station": fir.Station},
+		options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}}),
+	).Decode(&last)
+	switch {
+	case err == nil:
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return err
+	}
+	entry.Hash = ledgerEntryHash(entry)
+
+	_, err = collection.InsertOne(ctx, entry)
+	return err
+}
+
+// VerifyFIR recomputes a submitted FIR's content hash and its station's
+// ledger up to the FIR's entry.
+func (s *FIRService) VerifyFIR(ctx context.Context, firID, userID string) (*FIRVerification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	fir, err := s.scopedFIR(ctx, firID, userID, models.AuditFIRVerify, false)
+	if err != nil {
+		return nil, err
+	}
+	if fir.SubmittedAt == nil {
+		return nil, ErrFIRNotSubmitted
+	}
+
+	verification := &FIRVerification{
+		FIRID:     fir.ID.Hex(),
+		FIRNumber: fir.FIRNumber,
+		Station:   fir.Station,
+	}
+
+	var entry models.LedgerEntry
+	err = database.GetCollection(s.ledgerCollection).FindOne(ctx, bson.M{"fir_id": fir.ID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		verification.Problems = []LedgerProblem{missingFromLedger(fir)}
+		return verification, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.verifyStation(ctx, entry.Station, entry.Seq)
+	if err != nil {
+		return nil, err
+	}
+	verification.Seq = entry.Seq
+	verification.ContentHash = entry.ContentHash
+	verification.Hash = entry.Hash
+	verification.Valid = report.Valid
+	verification.Problems = report.Problems
+	return verification, nil
+}
+
+// VerifyLedger recomputes the ledger of one station, or of every station
+// when station is empty.
+func (s *FIRService) VerifyLedger(ctx context.Context, station string) ([]LedgerReport, error) {
+	stations := []string{station}
+	if station == "" {
+		var err error
+		if stations, err = s.ledgerStations(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := []LedgerReport{}
+	for _, station := range stations {
+		report, err := s.verifyStation(ctx, station, 0)
+		if err != nil {
+			return nil, fmt.Errorf("station %s: %w", station, err)
+		}
+		reports = append(reports, *report)
+	}
+	return reports, nil
+}
+
+// ledgerStations lists every station with a ledger entry or a submitted
+// FIR.
+func (s *FIRService) ledgerStations(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+
+	fromLedger, err := database.GetCollection(s.ledgerCollection).Distinct(ctx, "station", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	fromFIRs, err := database.GetCollection(s.collection).Distinct(ctx, "station", bson.M{"submitted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range append(fromLedger, fromFIRs...) {
+		if station, ok := value.(string); ok {
+			seen[station] = true
+		}
+	}
+
+	stations := make([]string, 0, len(seen))
+	for station := range seen {
+		stations = append(stations, station)
+	}
+	sort.Strings(stations)
+	return stations, nil
+}
+
+// verifyStation loads a station's ledger and checks it with checkLedger.
+// upTo stops the walk at that sequence number; when it is 0 the whole
+// ledger is checked and submitted FIRs missing from it are reported too.
+func (s *FIRService) verifyStation(ctx context.Context, station string, upTo int) (*LedgerReport, error) {
+	filter := bson.M{"station": station}
+	if upTo > 0 {
+		filter["seq"] = bson.M{"$lte": upTo}
+	}
+
+	cursor, err := database.GetCollection(s.ledgerCollection).Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.LedgerEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	firIDs := make([]primitive.ObjectID, len(entries))
+	for i, entry := range entries {
+		firIDs[i] = entry.FIRID
+	}
+	firs, err := s.firsByID(ctx, bson.M{"_id": bson.M{"$in": firIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LedgerReport{Station: station, Entries: len(entries), Problems: checkLedger(entries, firs)}
+
+	if upTo == 0 {
+		unrecorded, err := s.firsByID(ctx, bson.M{
+			"station":      station,
+			"submitted_at": bson.M{"$ne": nil},
+			"_id":          bson.M{"$nin": firIDs},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, fir := range unrecorded {
+			report.Problems = append(report.Problems, missingFromLedger(fir))
+		}
+	}
+
+	report.Valid = len(report.Problems) == 0
+	return report, nil
+}
+
+// checkLedger checks a station's ledger entries, in sequence order, and
+// the FIRs they record: each entry's hash, its link to the previous entry
+// and the content hash of its FIR.
+func checkLedger(entries []models.LedgerEntry, firs map[primitive.ObjectID]*models.FIR) []LedgerProblem {
+	problems := []LedgerProblem{}
+	problem := func(entry models.LedgerEntry, format string, args ...interface{}) {
+		problems = append(problems, LedgerProblem{
+			Seq:       entry.Seq,
+			FIRID:     entry.FIRID.Hex(),
+			FIRNumber: entry.FIRNumber,
+			Problem:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.Seq != i+1 {
+			problem(entry, "expected sequence number %d", i+1)
+		}
+		if entry.PrevHash != prevHash {
+			problem(entry, "previous hash does not match entry %d", i)
+		}
+		if ledgerEntryHash(entry) != entry.Hash {
+			problem(entry, "ledger entry has been altered")
+		}
+		prevHash = entry.Hash
+
+		fir, ok := firs[entry.FIRID]
+		switch {
+		case !ok:
+			problem(entry, "FIR is missing")
+		case fir.DeletedAt != nil:
+			problem(entry, "FIR was deleted after submission")
+		case firContentHash(fir) != entry.ContentHash:
+			problem(entry, "FIR content has been altered since submission")
+		}
+	}
+	return problems
+}
+
+// firsByID loads FIRs matching filter, including deleted ones, keyed by ID.
+func (s *FIRService) firsByID(ctx context.Context, filter bson.M) (map[primitive.ObjectID]*models.FIR, error) {
+	cursor, err := database.GetCollection(s.collection).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var firs []models.FIR
+	if err := cursor.All(ctx, &firs); err != nil {
+		return nil, err
+	}
+	if err := s.openFIRs(firs); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*models.FIR, len(firs))
+	for i := range firs {
+		byID[firs[i].ID] = &firs[i]
+	}
+	return byID, nil
+}
+
+func missingFromLedger(fir *models.FIR) LedgerProblem {
+	return LedgerProblem{
+		FIRID:     fir.ID.Hex(),
+		FIRNumber: fir.FIRNumber,
+		Problem:   "submitted FIR is missing from the ledger",
+	}
+}
+
+// firContentHash is the SHA-256 of an FIR's canonical content.
+func firContentHash(fir *models.FIR) string {
+	sum := sha256.Sum256(canonicalFIRJSON(fir))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalFIRJSON is the JSON encoding of an FIR's canonical content, the
+// bytes that are hashed into the ledger and signed.
+func canonicalFIRJSON(fir *models.FIR) []byte {
+	content := canonicalFIR{
+		ID:                  fir.ID.Hex(),
+		FIRNumber:           fir.FIRNumber,
+		Station:             fir.Station,
+		OfficerID:           fir.OfficerID.Hex(),
+		ComplainantName:     fir.ComplainantName,
+		ComplainantAddress:  fir.ComplainantAddress,
+		ComplainantPhone:    fir.ComplainantPhone,
+		IncidentDate:        fir.IncidentDate.UTC().Format("2006-01-02"),
+		IncidentTime:        fir.IncidentTime,
+		IncidentLocation:    fir.IncidentLocation,
+		IncidentDescription: fir.IncidentDescription,
+		WitnessDetails:      fir.WitnessDetails,
+		EvidenceDetails:     fir.EvidenceDetails,
+		OfficerRemarks:      fir.OfficerRemarks,
+		Language:            fir.Language,
+		ApplicableSections:  fir.ApplicableSections,
+	}
+	if content.ApplicableSections == nil {
+		content.ApplicableSections = []string{}
+	}
+	if fir.SubmittedAt != nil {
+		content.SubmittedAt = fir.SubmittedAt.UTC().Truncate(time.Millisecond).Format(time.RFC3339Nano)
+	}
+
+	payload, _ := json.Marshal(content)
+	return payload
+}
+
+// ledgerEntryHash is the SHA-256 of a ledger entry's fields, excluding
+// Hash and the document ID.
+func ledgerEntryHash(entry models.LedgerEntry) string {
+	payload := strings.Join([]string{
+		entry.Station,
+		strconv.Itoa(entry.Seq),
+		entry.FIRID.Hex(),
+		entry.FIRNumber,
+		entry.ContentHash,
+		entry.PrevHash,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testSubmittedFIR(number string) *models.FIR {
+	submittedAt := time.Date(2024, time.August, 2, 10, 30, 0, 123456789, time.UTC)
+	return &models.FIR{
+		ID:                  primitive.NewObjectID(),
+		FIRNumber:           number,
+		Station:             "Koramangala",
+		OfficerID:           primitive.NewObjectID(),
+		ComplainantName:     "Ravi Kumar",
+		IncidentDate:        time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		IncidentDescription: "My phone was snatched near the bus stop.",
+		Status:              models.FIRStatusSubmitted,
+		ApplicableSections:  []string{"BNS 304"},
+		SubmittedAt:         &submittedAt,
+	}
+}
+
+func TestCanonicalFIRJSON(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	base := testSubmittedFIR("KOR/2024/0001")
+	baseJSON := canonicalFIRJSON(base)
+
+	tests := []struct {
+		name     string
+		change   func(fir *models.FIR)
+		wantSame bool
+	}{
+		{"status", func(fir *models.FIR) { fir.Status = models.FIRStatusUnderInvestigation }, true},
+		{"amendments", func(fir *models.FIR) { fir.Amendments = []models.FIRAmendment{{Text: "Correction"}} }, true},
+		{"AI analysis", func(fir *models.FIR) { fir.AIAnalysis.CrimeType = "robbery" }, true},
+		{"submission time in another zone", func(fir *models.FIR) {
+			at := fir.SubmittedAt.In(ist)
+			fir.SubmittedAt = &at
+		}, true},
+		{"submission time below a millisecond", func(fir *models.FIR) {
+			at := fir.SubmittedAt.Add(500 * time.Microsecond)
+			fir.SubmittedAt = &at
+		}, true},
+		{"description", func(fir *models.FIR) { fir.IncidentDescription += " " }, false},
+		{"complainant", func(fir *models.FIR) { fir.ComplainantName = "Ravi K." }, false},
+		{"sections", func(fir *models.FIR) { fir.ApplicableSections = append(fir.ApplicableSections, "BNS 115") }, false},
+		{"FIR number", func(fir *models.FIR) { fir.FIRNumber = "KOR/2024/0002" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fir := *base
+			fir.ApplicableSections = append([]string{}, base.ApplicableSections...)
+			tt.change(&fir)
+			if same := bytes.Equal(canonicalFIRJSON(&fir), baseJSON); same != tt.wantSame {
+				t.Errorf("canonical content unchanged = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+
+	t.Run("no sections", func(t *testing.T) {
+		fir := *base
+		fir.ApplicableSections = nil
+		if !bytes.Contains(canonicalFIRJSON(&fir), []byte(`"applicable_sections":[]`)) {
+			t.Errorf("canonicalFIRJSON() = %s, want an empty applicable_sections array", canonicalFIRJSON(&fir))
+		}
+	})
+}
+
+// testLedger chains firs into a valid ledger.
+func testLedger(firs []*models.FIR) []models.LedgerEntry {
+	var entries []models.LedgerEntry
+	prevHash := ""
+	for i, fir := range firs {
+		entry := models.LedgerEntry{
+			ID:          primitive.NewObjectID(),
+			Station:     fir.Station,
+			Seq:         i + 1,
+			FIRID:       fir.ID,
+			FIRNumber:   fir.FIRNumber,
+			ContentHash: firContentHash(fir),
+			PrevHash:    prevHash,
+			CreatedAt:   fir.SubmittedAt.Add(time.Duration(i) * time.Second),
+		}
+		entry.Hash = ledgerEntryHash(entry)
+		prevHash = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLedgerEntryHash(t *testing.T) {
+	entry := testLedger([]*models.FIR{testSubmittedFIR("KOR/2024/0001")})[0]
+
+	tests := []struct {
+		name     string
+		change   func(entry *models.LedgerEntry)
+		wantSame bool
+	}{
+		{"document ID", func(entry *models.LedgerEntry) { entry.ID = primitive.NewObjectID() }, true},
+		{"creation time zone", func(entry *models.LedgerEntry) { entry.CreatedAt = entry.CreatedAt.In(time.FixedZone("IST", 19800)) }, true},
+		{"station", func(entry *models.LedgerEntry) { entry.Station = "Indiranagar" }, false},
+		{"sequence", func(entry *models.LedgerEntry) { entry.Seq = 2 }, false},
+		{"FIR", func(entry *models.LedgerEntry) { entry.FIRID = primitive.NewObjectID() }, false},
+		{"FIR number", func(entry *models.LedgerEntry) { entry.FIRNumber = "KOR/2024/0009" }, false},
+		{"content hash", func(entry *models.LedgerEntry) { entry.ContentHash = strings.Repeat("0", 64) }, false},
+		{"previous hash", func(entry *models.LedgerEntry) { entry.PrevHash = "x" }, false},
+		{"creation time", func(entry *models.LedgerEntry) { entry.CreatedAt = entry.CreatedAt.Add(time.Nanosecond) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := entry
+			tt.change(&changed)
+			if same := ledgerEntryHash(changed) == entry.Hash; same != tt.wantSame {
+				t.Errorf("hash unchanged = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestCheckLedger(t *testing.T) {
+	type problem struct {
+		seq     int
+		problem string
+	}
+	tests := []struct {
+		name   string
+		tamper func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry
+		want   []problem
+	}{
+		{
+			name:   "valid",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry { return entries },
+		},
+		{
+			name: "FIR content altered",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				firs[1].IncidentDescription = "Nothing happened."
+				return entries
+			},
+			want: []problem{{2, "FIR content has been altered since submission"}},
+		},
+		{
+			name: "entry altered",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				entries[0].FIRNumber = "KOR/2024/0099"
+				return entries
+			},
+			want: []problem{{1, "ledger entry has been altered"}},
+		},
+		{
+			name: "entry rehashed after altering",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				entries[0].CreatedAt = entries[0].CreatedAt.Add(time.Hour)
+				entries[0].Hash = ledgerEntryHash(entries[0])
+				return entries
+			},
+			want: []problem{{2, "previous hash does not match entry 1"}},
+		},
+		{
+			name: "entry removed",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+			want: []problem{
+				{3, "expected sequence number 2"},
+				{3, "previous hash does not match entry 1"},
+			},
+		},
+		{
+			name: "FIR deleted",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				now := time.Now()
+				firs[2].DeletedAt = &now
+				return entries
+			},
+			want: []problem{{3, "FIR was deleted after submission"}},
+		},
+		{
+			name: "FIR missing",
+			tamper: func(entries []models.LedgerEntry, firs []*models.FIR) []models.LedgerEntry {
+				entries[1].FIRID = primitive.NewObjectID()
+				entries[1].Hash = ledgerEntryHash(entries[1])
+				return entries
+			},
+			want: []problem{
+				{2, "FIR is missing"},
+				{3, "previous hash does not match entry 2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firs := []*models.FIR{
+				testSubmittedFIR("KOR/2024/0001"),
+				testSubmittedFIR("KOR/2024/0002"),
+				testSubmittedFIR("KOR/2024/0003"),
+			}
+			entries := tt.tamper(testLedger(firs), firs)
+			byID := make(map[primitive.ObjectID]*models.FIR, len(firs))
+			for _, fir := range firs {
+				byID[fir.ID] = fir
+			}
+
+			var got []problem
+			for _, p := range checkLedger(entries, byID) {
+				got = append(got, problem{p.Seq, p.Problem})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkLedger() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrInvalidVerificationCode is returned for a verification code that was
+// not printed by this system.
+var ErrInvalidVerificationCode = errors.New("invalid verification code")
+
+var contentHashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// FIRPDF is a registered FIR printed for the complainant.
+type FIRPDF struct {
+	FileName string
+	Data     []byte
+}
+
+// CopyVerification is the public result of checking a printed FIR against
+// the stored record. It carries no complainant details.
+type CopyVerification struct {
+	FIRNumber    string          `json:"fir_number"`
+	Station      string          `json:"station"`
+	Status       string          `json:"status"`
+	RegisteredAt *time.Time      `json:"registered_at"`
+	Valid        bool            `json:"valid"`
+	Problem      string          `json:"problem,omitempty"`
+	Signatures   []CopySignature `json:"signatures"`
+}
+
+// CopySignature is a signature on a verified FIR, as shown publicly.
+type CopySignature struct {
+	Role       string    `json:"role"`
+	SignerName string    `json:"signer_name"`
+	SignerRank string    `json:"signer_rank"`
+	KeyID      string    `json:"key_id"`
+	SignedAt   time.Time `json:"signed_at"`
+	Valid      bool      `json:"valid"`
+}
+
+// verificationCode identifies a printed FIR by its number and the hash of
+// its content. FIR numbers contain slashes, so the number is base64url
+// encoded to keep the code a single path segment.
+func verificationCode(fir *models.FIR) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fir.FIRNumber)) + "." + firContentHash(fir)
+}
+
+func parseVerificationCode(code string) (firNumber, contentHash string, err error) {
+	encoded, contentHash, ok := strings.Cut(code, ".")
+	if !ok || !contentHashPattern.MatchString(contentHash) {
+		return "", "", ErrInvalidVerificationCode
+	}
+	number, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(number) == 0 {
+		return "", "", ErrInvalidVerificationCode
+	}
+	return string(number), contentHash, nil
+}
+
+// PDF prints a registered FIR for the complainant. The copy carries a QR
+// code linking to the public verification endpoint with its verification
+// code, which changes if any of the FIR's sealed content does.
+func (s *FIRService) PDF(ctx context.Context, firID, officerID string, bilingual bool, language string) (*FIRPDF, error) {
+	fir, err := s.scopedFIR(ctx, firID, officerID, models.AuditFIRPDF, false)
+	if err != nil {
+		return nil, err
+	}
+	if fir.SubmittedAt == nil {
+		return nil, ErrFIRNotSubmitted
+	}
+	officer, err := s.authService.GetUserByID(fir.OfficerID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := s.renderFIR(ctx, fir, officer, "", bilingual, language)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := s.verifySignatures(ctx, fir)
+	if err != nil {
+		return nil, err
+	}
+
+	code := verificationCode(fir)
+	verifyURL := strings.TrimRight(s.publicURL, "/") + "/api/verify/" + code
+	data, err := s.pdfRenderer.Render(PDFDocument{
+		Title:      "FIR " + fir.FIRNumber,
+		Text:       document.Text,
+		Sections:   document.Sections,
+		Signatures: signatureBlock(signatures),
+		Footer:     "FIR No. " + fir.FIRNumber,
+		QRContent:  verifyURL,
+		QRCaption:  fmt.Sprintf("Scan to verify this copy, or visit:\n%s", verifyURL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FIRPDF{
+		FileName: "FIR-" + strings.ReplaceAll(fir.FIRNumber, "/", "-") + ".pdf",
+		Data:     data,
+	}, nil
+}
+
+// VerifyCopy checks a printed FIR's verification code against the stored
+// FIR and its ledger entry. A code for an unknown FIR number is
+// ErrFIRNotFound; otherwise the result says whether the copy matches.
+func (s *FIRService) VerifyCopy(ctx context.Context, code string) (*CopyVerification, error) {
+	firNumber, contentHash, err := parseVerificationCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var fir models.FIR
+	err = database.GetCollection(s.collection).FindOne(ctx, bson.M{
+		"fir_number": firNumber,
+		"deleted_at": nil,
+	}).Decode(&fir)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrFIRNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.openFIR(&fir, fir.DataKey); err != nil {
+		return nil, err
+	}
+
+	verification := &CopyVerification{
+		FIRNumber:    fir.FIRNumber,
+		Station:      fir.Station,
+		Status:       fir.Status,
+		RegisteredAt: fir.SubmittedAt,
+	}
+
+	var entry models.LedgerEntry
+	err = database.GetCollection(s.ledgerCollection).FindOne(ctx, bson.M{"fir_id": fir.ID}).Decode(&entry)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		verification.Problem = "the FIR is missing from the station ledger"
+	case err != nil:
+		return nil, err
+	case ledgerEntryHash(entry) != entry.Hash || firContentHash(&fir) != entry.ContentHash:
+		verification.Problem = "the stored FIR does not match the station ledger"
+	case contentHash != entry.ContentHash:
+		verification.Problem = "this copy does not match the registered FIR"
+	default:
+		verification.Valid = true
+	}
+
+	signatures, err := s.verifySignatures(ctx, &fir)
+	if err != nil {
+		return nil, err
+	}
+	verification.Signatures = []CopySignature{}
+	for _, check := range signatures.Signatures {
+		verification.Signatures = append(verification.Signatures, CopySignature{
+			Role:       check.Role,
+			SignerName: check.SignerName,
+			SignerRank: check.SignerRank,
+			KeyID:      check.KeyID,
+			SignedAt:   check.SignedAt,
+			Valid:      check.Valid,
+		})
+	}
+	return verification, nil
+}
+
+// signatureBlock lists an FIR's valid signatures for its printed copy.
+// Signatures that no longer verify are left off.
+func signatureBlock(verification *SignatureVerification) string {
+	formatTime := formatDocumentDate("02/01/2006 15:04")
+	lines := []string{"DIGITAL SIGNATURES"}
+	for _, check := range verification.Signatures {
+		if !check.Valid {
+			continue
+		}
+		role := "Registering officer"
+		if check.Role == models.SignatureRoleCountersign {
+			role = "Countersigned by"
+		}
+		lines = append(lines,
+			fmt.Sprintf("%s: %s, %s (badge %s)", role, check.SignerName, check.SignerRank, check.SignerBadge),
+			fmt.Sprintf("Signed %s IST with %s key %s", formatTime(check.SignedAt), check.Algorithm, check.KeyID),
+			"Signature: "+check.Signature,
+		)
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "This FIR has no valid digital signature.")
+	}
+	return strings.Join(lines, "\n")
+}
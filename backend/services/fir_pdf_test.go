@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerificationCode(t *testing.T) {
+	fir := testSubmittedFIR("KOR/2024/0017")
+	code := verificationCode(fir)
+	if strings.Contains(code, "/") {
+		t.Fatalf("verificationCode() = %q, want a single path segment", code)
+	}
+
+	number, contentHash, err := parseVerificationCode(code)
+	if err != nil {
+		t.Fatalf("parseVerificationCode(%q) error = %v", code, err)
+	}
+	if number != fir.FIRNumber || contentHash != firContentHash(fir) {
+		t.Errorf("parseVerificationCode() = %q, %q, want %q, %q", number, contentHash, fir.FIRNumber, firContentHash(fir))
+	}
+}
+
+func TestParseVerificationCodeRejects(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"empty", ""},
+		{"no separator", "S09SLzIwMjQvMDAxNw" + hash},
+		{"empty number", "." + hash},
+		{"bad base64", "S09S*IwMjQ." + hash},
+		{"short hash", "S09SLzIwMjQvMDAxNw." + hash[:63]},
+		{"upper-case hash", "S09SLzIwMjQvMDAxNw." + strings.ToUpper(hash)},
+		{"padded base64", "S09SLzIwMjQvMDAxNw==." + hash},
+		{"extra part", "S09SLzIwMjQvMDAxNw." + hash + ".x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := parseVerificationCode(tt.code); !errors.Is(err, ErrInvalidVerificationCode) {
+				t.Errorf("parseVerificationCode(%q) error = %v, want ErrInvalidVerificationCode", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrRevisionNotFound is returned when an FIR has no revision with the
+// requested number or none at the requested time.
+var ErrRevisionNotFound = errors.New("FIR revision not found")
+
+// RevisionHistory is an FIR's revisions, oldest first, and whether their
+// hash chain is intact. BrokenAt is the first revision whose hash does not
+// match its contents or whose PrevHash does not match the revision before.
+type RevisionHistory struct {
+	Data       []models.FIRRevision `json:"data"`
+	Total      int                  `json:"total"`
+	ChainValid bool                 `json:"chain_valid"`
+	BrokenAt   *int                 `json:"broken_at,omitempty"`
+}
+
+// FieldChange is a field that differs between two revisions.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionDiff lists the fields that differ between two revisions of an
+// FIR.
+type RevisionDiff struct {
+	FIRID   string        `json:"fir_id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// recordRevision appends a revision for a change that turned before into
+// after. before is nil when the FIR was created. It runs in the
+// transaction that writes the change, which also writes the FIR, so two
+// changes to the same FIR conflict there and are retried in turn rather
+// than racing for the next revision number.
+func (s *FIRService) recordRevision(ctx context.Context, before, after *models.FIR, action string, officerID primitive.ObjectID, reason string) error {
+	collection := database.GetCollection(s.revisionsCollection)
+
+	var fields []string
+	if before != nil {
+		fields = changedFields(before, after)
+	}
+	snapshot, err := s.snapshotFIR(after)
+	if err != nil {
+		return err
+	}
+
+	revision := models.FIRRevision{
+		ID:        primitive.NewObjectID(),
+		FIRID:     after.ID,
+		Number:    1,
+		Action:    action,
+		Fields:    fields,
+		Reason:    reason,
+		ChangedBy: officerID,
+		CreatedAt: time.Now(),
+		Snapshot:  snapshot,
+	}
+
+	var last models.FIRRevision
+	err = collection.FindOne(ctx,
+		bson.M{"fir_id": after.ID},
+		options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}}).SetProjection(bson.M{"number": 1, "hash": 1}),
+	).Decode(&last)
+	switch {
+	case err == nil:
+		revision.Number = last.Number + 1
+		revision.PrevHash = last.Hash
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return err
+	}
+
+	raw, err := bson.Marshal(revision)
+	if err != nil {
+		return err
+	}
+	revision.Hash = revisionHash(raw)
+
+	_, err = collection.InsertOne(ctx, revision)
+	return err
+}
+
+// ListRevisions returns every revision of an FIR, without snapshots, and
+// checks the hash chain. Revisions of deleted FIRs remain visible.
+func (s *FIRService) ListRevisions(ctx context.Context, firID, userID string) (*RevisionHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, filter, err := s.revisionFilter(ctx, firID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := database.GetCollection(s.revisionsCollection)
+	cursor, err := collection.Find(ctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "number", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := &RevisionHistory{Data: []models.FIRRevision{}, ChainValid: true}
+	prevHash := ""
+	for cursor.Next(ctx) {
+		var revision models.FIRRevision
+		if err := cursor.Decode(&revision); err != nil {
+			return nil, err
+		}
+
+		number := len(history.Data) + 1
+		if history.ChainValid && (revisionHash(cursor.Current) != revision.Hash || revision.PrevHash != prevHash || revision.Number != number) {
+			history.ChainValid = false
+			history.BrokenAt = &number
+		}
+		prevHash = revision.Hash
+
+		revision.Snapshot = nil
+		history.Data = append(history.Data, revision)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if len(history.Data) == 0 {
+		return nil, ErrFIRNotFound
+	}
+
+	history.Total = len(history.Data)
+	return history, nil
+}
+
+// DiffRevisions compares the snapshots of two revisions of an FIR.
+func (s *FIRService) DiffRevisions(ctx context.Context, firID, userID string, from, to int) (*RevisionDiff, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	firObjectID, filter, err := s.revisionFilter(ctx, firID, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter["number"] = bson.M{"$in": []int{from, to}}
+
+	collection := database.GetCollection(s.revisionsCollection)
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []models.FIRRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[int]*models.FIR, len(revisions))
+	for _, revision := range revisions {
+		snapshots[revision.Number] = revision.Snapshot
+	}
+	if snapshots[from] == nil || snapshots[to] == nil {
+		return nil, ErrRevisionNotFound
+	}
+	if err := s.openSnapshots(ctx, firObjectID, snapshots[from], snapshots[to]); err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		FIRID:   firID,
+		From:    from,
+		To:      to,
+		Changes: diffFIRs(snapshots[from], snapshots[to]),
+	}, nil
+}
+
+// FIRAsOf returns the latest revision of an FIR made at or before at. Its
+// snapshot is the FIR as it stood at that time.
+func (s *FIRService) FIRAsOf(ctx context.Context, firID, userID string, at time.Time) (*models.FIRRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	firObjectID, filter, err := s.revisionFilter(ctx, firID, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter["created_at"] = bson.M{"$lte": at}
+
+	var revision models.FIRRevision
+	err = database.GetCollection(s.revisionsCollection).FindOne(ctx,
+		filter,
+		options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}}),
+	).Decode(&revision)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.openSnapshots(ctx, firObjectID, revision.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// revisionFilter matches an FIR's revisions if the FIR, deleted or not, is
+// in the user's jurisdiction. The read is recorded in the audit log.
+func (s *FIRService) revisionFilter(ctx context.Context, firID, userID string) (primitive.ObjectID, bson.M, error) {
+	fir, err := s.scopedFIR(ctx, firID, userID, models.AuditFIRRevisions, true)
+	if err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+	return fir.ID, bson.M{"fir_id": fir.ID}, nil
+}
+
+// revisionHash hashes every element of a revision document except hash
+// itself, including prev_hash. It works on the stored bytes so revisions
+// written before a field was added to the model still verify.
+func revisionHash(raw bson.Raw) string {
+	h := sha256.New()
+	elements, _ := raw.Elements()
+	for _, element := range elements {
+		if element.Key() != "hash" {
+			h.Write(element)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// changedFields lists the top-level fields that differ between two FIRs,
+// ignoring updated_at.
+func changedFields(before, after *models.FIR) []string {
+	var fields []string
+	for _, change := range diffFIRs(before, after) {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+// diffFIRs compares two FIRs field by field using their JSON form, so
+// nested values are reported as the API shows them.
+func diffFIRs(before, after *models.FIR) []FieldChange {
+	from := firFields(before)
+	to := firFields(after)
+
+	names := make(map[string]bool, len(from)+len(to))
+	for name := range from {
+		names[name] = true
+	}
+	for name := range to {
+		names[name] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+		if name == "updated_at" || reflect.DeepEqual(from[name], to[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, From: from[name], To: to[name]})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func firFields(fir *models.FIR) map[string]interface{} {
+	fields := make(map[string]interface{})
+	raw, err := json.Marshal(fir)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(raw, &fields)
+	return fields
+}
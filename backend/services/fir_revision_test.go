@@ -0,0 +1,112 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRevisionHash(t *testing.T) {
+	firID := primitive.NewObjectID()
+	createdAt := time.Date(2024, time.August, 2, 10, 0, 0, 0, time.UTC)
+	base := bson.D{
+		{Key: "fir_id", Value: firID},
+		{Key: "number", Value: 2},
+		{Key: "action", Value: models.RevisionUpdated},
+		{Key: "created_at", Value: createdAt},
+		{Key: "prev_hash", Value: "abc"},
+	}
+	baseHash := revisionHash(mustMarshal(t, base))
+
+	with := func(key string, value interface{}) bson.D {
+		doc := append(bson.D{}, base...)
+		for i := range doc {
+			if doc[i].Key == key {
+				doc[i].Value = value
+				return doc
+			}
+		}
+		return append(doc, bson.E{Key: key, Value: value})
+	}
+
+	tests := []struct {
+		name     string
+		doc      bson.D
+		wantSame bool
+	}{
+		{"stored hash is ignored", with("hash", "anything"), true},
+		{"previous hash", with("prev_hash", "abd"), false},
+		{"number", with("number", 3), false},
+		{"action", with("action", models.RevisionDeleted), false},
+		{"field added later", with("reason", "typo"), false},
+		{"field order", bson.D{base[1], base[0], base[2], base[3], base[4]}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := revisionHash(mustMarshal(t, tt.doc))
+			if (got == baseHash) != tt.wantSame {
+				t.Errorf("revisionHash() = %s, base %s, want same = %v", got, baseHash, tt.wantSame)
+			}
+		})
+	}
+
+	if len(baseHash) != 64 {
+		t.Errorf("revisionHash() = %q, want 64 hex characters", baseHash)
+	}
+}
+
+func TestDiffFIRs(t *testing.T) {
+	before := &models.FIR{
+		ComplainantName:  "Ravi Kumar",
+		IncidentLocation: "MG Road",
+		Status:           models.FIRStatusDraft,
+		UpdatedAt:        time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		change func(fir *models.FIR)
+		want   []string
+	}{
+		{"no change", func(fir *models.FIR) {}, nil},
+		{"updated_at is ignored", func(fir *models.FIR) { fir.UpdatedAt = fir.UpdatedAt.Add(time.Hour) }, nil},
+		{"one field", func(fir *models.FIR) { fir.IncidentLocation = "Brigade Road" }, []string{"incident_location"}},
+		{
+			"sorted by name",
+			func(fir *models.FIR) {
+				fir.Status = models.FIRStatusSubmitted
+				fir.ComplainantName = "Ravi K."
+			},
+			[]string{"complainant_name", "status"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := *before
+			tt.change(&after)
+			if got := changedFields(before, &after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	changes := diffFIRs(before, &models.FIR{ComplainantName: "Ravi K.", IncidentLocation: "MG Road", Status: models.FIRStatusDraft, UpdatedAt: before.UpdatedAt})
+	want := []FieldChange{{Field: "complainant_name", From: "Ravi Kumar", To: "Ravi K."}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("diffFIRs() = %+v, want %+v", changes, want)
+	}
+}
+
+func mustMarshal(t *testing.T, doc interface{}) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	return data
+}
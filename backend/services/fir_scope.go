@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FIRList is a page of FIRs and the scope they were read under.
+type FIRList struct {
+	Data  []models.FIR
+	Total int64
+	Scope models.AccessScope
+}
+
+// readScope loads a user and the jurisdiction they may read FIRs in, with
+// the filter matching the undeleted FIRs in it.
+func (s *FIRService) readScope(ctx context.Context, userID string) (*models.User, models.AccessScope, bson.M, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, models.AccessScope{}, nil, err
+	}
+	scope := policy.ReadScope(user)
+	filter, err := scopeFilter(user, scope, func(scope models.AccessScope) ([]primitive.ObjectID, error) {
+		return s.authService.UserIDsPostedIn(ctx, scope)
+	})
+	if err != nil {
+		return nil, models.AccessScope{}, nil, err
+	}
+	filter["deleted_at"] = nil
+	return user, scope, filter, nil
+}
+
+// scopeFilter returns the filter matching the FIRs in scope. FIRs record
+// the station, district and state of the officer who registered them;
+// older FIRs that do not are matched by their officer's current posting,
+// with postedIn listing the users posted in scope.
+func scopeFilter(user *models.User, scope models.AccessScope, postedIn func(models.AccessScope) ([]primitive.ObjectID, error)) (bson.M, error) {
+	var area bson.M
+	var recorded string // the field older FIRs leave empty
+	switch scope.Level {
+	case models.ScopeStation:
+		area, recorded = bson.M{"station": scope.Station}, "station"
+	case models.ScopeDistrict:
+		area, recorded = bson.M{"state": scope.State, "district": scope.District}, "state"
+	case models.ScopeState:
+		area, recorded = bson.M{"state": scope.State}, "state"
+	default:
+		return bson.M{"officer_id": user.ID}, nil
+	}
+
+	officerIDs, err := postedIn(scope)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{"$or": []bson.M{
+		area,
+		{recorded: bson.M{"$in": []interface{}{nil, ""}}, "officer_id": bson.M{"$in": officerIDs}},
+	}}, nil
+}
+
+// scopedFIR loads an FIR in the user's jurisdiction and records the read
+// as action, whether or not the FIR is found. Deleted FIRs are only found
+// with withDeleted, for the revision history that outlives them.
+func (s *FIRService) scopedFIR(ctx context.Context, firID, userID, action string, withDeleted bool) (*models.FIR, error) {
+	firObjectID, _, err := parseFIRIDs(firID, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, scope, filter, err := s.readScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter["_id"] = firObjectID
+	if withDeleted {
+		delete(filter, "deleted_at")
+	}
+
+	fir, err := s.loadFIR(ctx, filter)
+	found := int64(1)
+	if errors.Is(err, ErrFIRNotFound) {
+		found = 0
+	}
+	if auditErr := s.auditService.Record(ctx, user, action, scope, &firObjectID, found); auditErr != nil {
+		return nil, auditErr
+	}
+	return fir, err
+}
+
+// listScoped returns a page of the FIRs matching filter and records the
+// query in the audit log.
+func (s *FIRService) listScoped(ctx context.Context, user *models.User, scope models.AccessScope, action string, filter bson.M, page, limit int) (*FIRList, error) {
+	firs, total, err := s.listFIRs(ctx, filter, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditService.Record(ctx, user, action, scope, nil, total); err != nil {
+		return nil, err
+	}
+	return &FIRList{Data: firs, Total: total, Scope: scope}, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestScopeFilter(t *testing.T) {
+	user := &models.User{ID: primitive.NewObjectID(), Station: "Connaught Place", District: "New Delhi", State: "Delhi"}
+	colleagues := []primitive.ObjectID{user.ID, primitive.NewObjectID()}
+	fallback := func(recorded string) bson.M {
+		return bson.M{recorded: bson.M{"$in": []interface{}{nil, ""}}, "officer_id": bson.M{"$in": colleagues}}
+	}
+
+	tests := []struct {
+		name      string
+		scope     models.AccessScope
+		want      bson.M
+		wantQuery bool
+	}{
+		{
+			"own",
+			models.AccessScope{Level: models.ScopeOwn},
+			bson.M{"officer_id": user.ID},
+			false,
+		},
+		{
+			"unknown level",
+			models.AccessScope{Level: "country"},
+			bson.M{"officer_id": user.ID},
+			false,
+		},
+		{
+			"station",
+			models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"},
+			bson.M{"$or": []bson.M{{"station": "Connaught Place"}, fallback("station")}},
+			true,
+		},
+		{
+			"district",
+			models.AccessScope{Level: models.ScopeDistrict, District: "New Delhi", State: "Delhi"},
+			bson.M{"$or": []bson.M{{"state": "Delhi", "district": "New Delhi"}, fallback("state")}},
+			true,
+		},
+		{
+			"state",
+			models.AccessScope{Level: models.ScopeState, State: "Delhi"},
+			bson.M{"$or": []bson.M{{"state": "Delhi"}, fallback("state")}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queried := false
+			got, err := scopeFilter(user, tt.scope, func(scope models.AccessScope) ([]primitive.ObjectID, error) {
+				queried = true
+				if scope != tt.scope {
+					t.Errorf("postedIn(%+v), want %+v", scope, tt.scope)
+				}
+				return colleagues, nil
+			})
+			if err != nil {
+				t.Fatalf("scopeFilter() error = %v", err)
+			}
+			if queried != tt.wantQuery {
+				t.Errorf("postedIn called = %v, want %v", queried, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scopeFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	lookupErr := errors.New("connection refused")
+	_, err := scopeFilter(user, models.AccessScope{Level: models.ScopeStation, Station: "Connaught Place"}, func(models.AccessScope) ([]primitive.ObjectID, error) {
+		return nil, lookupErr
+	})
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("scopeFilter() error = %v, want the lookup error", err)
+	}
+}
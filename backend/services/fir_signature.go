@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/policy"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrAlreadyCountersigned is returned when a supervisor countersigns an
+	// FIR a second time.
+	ErrAlreadyCountersigned = errors.New("FIR has already been countersigned by this supervisor")
+	// ErrOwnFIRCountersign is returned when a supervisor tries to
+	// countersign an FIR they registered.
+	ErrOwnFIRCountersign = errors.New("the registering officer cannot countersign their own FIR")
+	// ErrSignatureInvalid is returned when an FIR's registering signature
+	// does not verify, so it must not be countersigned.
+	ErrSignatureInvalid = errors.New("FIR's registering signature is not valid")
+	// ErrNotCountersigner is returned when a user without the countersign
+	// permission at the FIR's station tries to countersign it.
+	ErrNotCountersigner = errors.New("only countersigning officers at the FIR's station can countersign it")
+)
+
+// SignatureCheck is the result of verifying one signature.
+type SignatureCheck struct {
+	models.FIRSignature
+	Valid   bool   `json:"valid"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// SignatureVerification reports whether an FIR's signatures verify against
+// its current content. Payload is the canonical JSON that was signed,
+// base64 encoded, so the signatures can also be checked independently with
+// any Ed25519 implementation. Valid is true when the FIR has a registering
+// signature and every signature verifies.
+type SignatureVerification struct {
+	FIRID       string           `json:"fir_id"`
+	FIRNumber   string           `json:"fir_number"`
+	Payload     string           `json:"payload"`
+	ContentHash string           `json:"content_hash"`
+	Valid       bool             `json:"valid"`
+	Signatures  []SignatureCheck `json:"signatures"`
+}
+
+// signFIR signs an FIR's canonical JSON with the signer's key.
+func (s *FIRService) signFIR(ctx context.Context, fir *models.FIR, signer *models.User, role string) (*models.FIRSignature, error) {
+	private, key, err := s.keystore.Signer(ctx, signer.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newSignature(fir, signer, role, private, key), nil
+}
+
+// newSignature signs an FIR's canonical JSON with private, the key whose
+// public record is key.
+func newSignature(fir *models.FIR, signer *models.User, role string, private ed25519.PrivateKey, key *models.OfficerKey) *models.FIRSignature {
+	payload := canonicalFIRJSON(fir)
+	sum := sha256.Sum256(payload)
+	return &models.FIRSignature{
+		Role:        role,
+		OfficerID:   signer.ID,
+		SignerName:  signer.Name,
+		SignerRank:  signer.Rank,
+		SignerBadge: signer.Badge,
+		Algorithm:   models.SignatureAlgorithm,
+		KeyID:       key.KeyID,
+		PublicKey:   base64.StdEncoding.EncodeToString(key.PublicKey),
+		ContentHash: hex.EncodeToString(sum[:]),
+		Signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(private, payload)),
+		SignedAt:    time.Now().UTC(),
+	}
+}
+
+// checkSignature verifies a signature against an FIR's current content and
+// checks that its key is the one the keystore holds for the signer.
+func (s *FIRService) checkSignature(ctx context.Context, fir *models.FIR, signature models.FIRSignature) (SignatureCheck, error) {
+	check := SignatureCheck{FIRSignature: signature}
+
+	public, problem := signatureProblem(fir, signature)
+	if problem != "" {
+		check.Problem = problem
+		return check, nil
+	}
+
+	key, err := s.keystore.PublicKey(ctx, signature.OfficerID, signature.KeyID)
+	if errors.Is(err, ErrOfficerKeyNotFound) || (err == nil && !ed25519.PublicKey(key.PublicKey).Equal(public)) {
+		check.Problem = "key is not registered to the signer"
+		return check, nil
+	}
+	if err != nil {
+		return check, err
+	}
+
+	check.Valid = true
+	return check, nil
+}
+
+// signatureProblem verifies a signature against an FIR's current content
+// with the public key it carries. It returns the key, and what is wrong
+// with the signature or "" if it verifies.
+func signatureProblem(fir *models.FIR, signature models.FIRSignature) (ed25519.PublicKey, string) {
+	payload := canonicalFIRJSON(fir)
+	sum := sha256.Sum256(payload)
+	public, keyErr := base64.StdEncoding.DecodeString(signature.PublicKey)
+	signed, sigErr := base64.StdEncoding.DecodeString(signature.Signature)
+	switch {
+	case signature.Algorithm != models.SignatureAlgorithm:
+		return nil, fmt.Sprintf("unsupported algorithm %q", signature.Algorithm)
+	case keyErr != nil || len(public) != ed25519.PublicKeySize || sigErr != nil:
+		return nil, "malformed public key or signature"
+	case signingKeyID(public) != signature.KeyID:
+		return nil, "public key does not match the key ID"
+	case hex.EncodeToString(sum[:]) != signature.ContentHash:
+		return nil, "the FIR has changed since it was signed"
+	case !ed25519.Verify(public, payload, signed):
+		return nil, "signature does not verify"
+	}
+	return public, ""
+}
+
+// verifySignatures checks every signature on an FIR.
+func (s *FIRService) verifySignatures(ctx context.Context, fir *models.FIR) (*SignatureVerification, error) {
+	verification := &SignatureVerification{
+		FIRID:       fir.ID.Hex(),
+		FIRNumber:   fir.FIRNumber,
+		Payload:     base64.StdEncoding.EncodeToString(canonicalFIRJSON(fir)),
+		ContentHash: firContentHash(fir),
+		Valid:       fir.Signature != nil,
+		Signatures:  []SignatureCheck{},
+	}
+
+	signatures := fir.Countersignatures
+	if fir.Signature != nil {
+		signatures = append([]models.FIRSignature{*fir.Signature}, signatures...)
+	}
+	for _, signature := range signatures {
+		check, err := s.checkSignature(ctx, fir, signature)
+		if err != nil {
+			return nil, err
+		}
+		verification.Valid = verification.Valid && check.Valid
+		verification.Signatures = append(verification.Signatures, check)
+	}
+	return verification, nil
+}
+
+// VerifySignatures verifies the signatures on a submitted FIR in the
+// user's jurisdiction.
+func (s *FIRService) VerifySignatures(ctx context.Context, firID, userID string) (*SignatureVerification, error) {
+	fir, err := s.scopedFIR(ctx, firID, userID, models.AuditFIRSignatures, false)
+	if err != nil {
+		return nil, err
+	}
+	if fir.SubmittedAt == nil {
+		return nil, ErrFIRNotSubmitted
+	}
+	return s.verifySignatures(ctx, fir)
+}
+
+// Countersign adds a supervisor's signature to a submitted FIR at their
+// station. The supervisor needs the countersign permission there, the
+// registering officer's signature must still verify, and each supervisor
+// can countersign an FIR once.
+func (s *FIRService) Countersign(ctx context.Context, firID, supervisorID string) (*models.FIRSignature, error) {
+	fir, supervisor, err := s.signableFIR(ctx, firID, supervisorID)
+	if err != nil {
+		return nil, err
+	}
+	if fir.OfficerID == supervisor.ID {
+		return nil, ErrOwnFIRCountersign
+	}
+	if !policy.StationAllows(supervisor, policy.FIRCountersign, fir.Station) {
+		return nil, ErrNotCountersigner
+	}
+	if fir.Signature != nil {
+		check, err := s.checkSignature(ctx, fir, *fir.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !check.Valid {
+			return nil, fmt.Errorf("%w: %s", ErrSignatureInvalid, check.Problem)
+		}
+	}
+
+	signature, err := s.signFIR(ctx, fir, supervisor, models.SignatureRoleCountersign)
+	if err != nil {
+		return nil, err
+	}
+
+	// The filter makes a second countersignature by the same supervisor
+	// fail even when two requests race.
+	err = database.WithTransaction(ctx, func(ctx mongo.SessionContext) error {
+		result, err := database.GetCollection(s.collection).UpdateOne(ctx, bson.M{
+			"_id":                          fir.ID,
+			"deleted_at":                   nil,
+			"countersignatures.officer_id": bson.M{"$ne": supervisor.ID},
+		}, bson.M{
+			"$push": bson.M{"countersignatures": signature},
+			"$set":  bson.M{"updated_at": time.Now()},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrAlreadyCountersigned
+		}
+
+		_, err = s.recordChange(ctx, fir, models.RevisionCountersigned, supervisor.ID, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// signableFIR loads a submitted FIR for its registering officer or for a
+// user who can countersign FIRs at its station, along with the user.
+func (s *FIRService) signableFIR(ctx context.Context, firID, userID string) (*models.FIR, *models.User, error) {
+	firObjectID, userObjectID, err := parseFIRIDs(firID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := bson.M{"_id": firObjectID, "deleted_at": nil, "officer_id": userObjectID}
+	if policy.Allows(user, policy.FIRCountersign) && user.Station != "" {
+		delete(filter, "officer_id")
+		filter["$or"] = []bson.M{
+			{"officer_id": userObjectID},
+			{"station": user.Station},
+		}
+	}
+	firs, err := s.firsByID(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	fir, ok := firs[firObjectID]
+	if !ok {
+		return nil, nil, ErrFIRNotFound
+	}
+	if fir.SubmittedAt == nil {
+		return nil, nil, ErrFIRNotSubmitted
+	}
+	return fir, user, nil
+}
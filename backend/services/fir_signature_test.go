@@ -0,0 +1,138 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testSigningKey(t *testing.T) (ed25519.PrivateKey, *models.OfficerKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	return private, &models.OfficerKey{
+		Algorithm: models.SignatureAlgorithm,
+		KeyID:     signingKeyID(public),
+		PublicKey: public,
+	}
+}
+
+func TestSignatureProblem(t *testing.T) {
+	signer := &models.User{ID: primitive.NewObjectID(), Name: "Asha Rao", Rank: "SI"}
+	private, key := testSigningKey(t)
+	_, otherKey := testSigningKey(t)
+	fir := testSubmittedFIR("KOR/2024/0017")
+	signature := *newSignature(fir, signer, models.SignatureRoleRegistering, private, key)
+
+	tests := []struct {
+		name   string
+		change func(fir *models.FIR, signature *models.FIRSignature)
+		want   string
+	}{
+		{"valid", func(fir *models.FIR, signature *models.FIRSignature) {}, ""},
+		{"status changed after signing", func(fir *models.FIR, signature *models.FIRSignature) {
+			fir.Status = models.FIRStatusClosed
+		}, ""},
+		{"content changed", func(fir *models.FIR, signature *models.FIRSignature) {
+			fir.IncidentLocation = "Elsewhere"
+		}, "the FIR has changed since it was signed"},
+		{"content and hash changed", func(fir *models.FIR, signature *models.FIRSignature) {
+			fir.IncidentLocation = "Elsewhere"
+			signature.ContentHash = firContentHash(fir)
+		}, "signature does not verify"},
+		{"algorithm", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.Algorithm = "RSA"
+		}, `unsupported algorithm "RSA"`},
+		{"malformed key", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.PublicKey = "not base64!"
+		}, "malformed public key or signature"},
+		{"short key", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.PublicKey = base64.StdEncoding.EncodeToString([]byte("short"))
+		}, "malformed public key or signature"},
+		{"malformed signature", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.Signature = "%%%"
+		}, "malformed public key or signature"},
+		{"key swapped", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.PublicKey = base64.StdEncoding.EncodeToString(otherKey.PublicKey)
+		}, "public key does not match the key ID"},
+		{"key and ID swapped", func(fir *models.FIR, signature *models.FIRSignature) {
+			signature.PublicKey = base64.StdEncoding.EncodeToString(otherKey.PublicKey)
+			signature.KeyID = otherKey.KeyID
+		}, "signature does not verify"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fir := *fir
+			signature := signature
+			tt.change(&fir, &signature)
+
+			public, problem := signatureProblem(&fir, signature)
+			if problem != tt.want {
+				t.Fatalf("signatureProblem() = %q, want %q", problem, tt.want)
+			}
+			if problem == "" && !public.Equal(ed25519.PublicKey(key.PublicKey)) {
+				t.Errorf("signatureProblem() returned a different public key")
+			}
+		})
+	}
+}
+
+func TestKeystoreSealOpen(t *testing.T) {
+	officerID := primitive.NewObjectID()
+	seed := make([]byte, ed25519.SeedSize)
+	rand.Read(seed)
+
+	old := &Keystore{keyring: NewKeyring(&config.Config{EncryptionKey: "old secret", EncryptionKeyID: "k1"})}
+	sealed, err := old.seal(seed, officerID)
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		keystore *Keystore
+		key      models.OfficerKey
+		wantErr  bool
+	}{
+		{"same key", old, models.OfficerKey{OfficerID: officerID, EncryptedPrivateKey: sealed, SealedWith: "k1"}, false},
+		{"recorded before key IDs", old, models.OfficerKey{OfficerID: officerID, EncryptedPrivateKey: sealed}, false},
+		{
+			"after rotation",
+			&Keystore{keyring: NewKeyring(&config.Config{EncryptionKey: "new secret", EncryptionKeyID: "k2", EncryptionOldKeys: "k1:old secret"})},
+			models.OfficerKey{OfficerID: officerID, EncryptedPrivateKey: sealed, SealedWith: "k1"},
+			false,
+		},
+		{"copied to another officer", old, models.OfficerKey{OfficerID: primitive.NewObjectID(), EncryptedPrivateKey: sealed, SealedWith: "k1"}, true},
+		{
+			"old key removed",
+			&Keystore{keyring: NewKeyring(&config.Config{EncryptionKey: "new secret", EncryptionKeyID: "k2"})},
+			models.OfficerKey{OfficerID: officerID, EncryptedPrivateKey: sealed, SealedWith: "k1"},
+			true,
+		},
+		{
+			"secret changed under the same ID",
+			&Keystore{keyring: NewKeyring(&config.Config{EncryptionKey: "other secret", EncryptionKeyID: "k1"})},
+			models.OfficerKey{OfficerID: officerID, EncryptedPrivateKey: sealed, SealedWith: "k1"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keystore.open(&tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != string(seed) {
+				t.Errorf("open() returned a different seed")
+			}
+		})
+	}
+}
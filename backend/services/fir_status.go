@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"legalassist-ai-backend/models"
+)
+
+var (
+	// ErrFIRNotFound is returned when an FIR does not exist, has been
+	// deleted or is not one the user may act on.
+	ErrFIRNotFound = errors.New("FIR not found")
+	// ErrFIRNotDraft is returned when an edit or delete targets an FIR that
+	// has already been submitted.
+	ErrFIRNotDraft = errors.New("only draft FIRs can be edited or deleted")
+	// ErrFIRNotAmendable is returned when an amendment targets a draft,
+	// which should be edited instead, or a closed FIR.
+	ErrFIRNotAmendable = errors.New("amendments can only be added to submitted or under-investigation FIRs")
+	// ErrNotRegisteringOfficer is returned when someone other than the
+	// officer who registered an FIR tries to submit it.
+	ErrNotRegisteringOfficer = errors.New("only the registering officer can submit an FIR")
+	// ErrAnalysisPending is returned when a draft is submitted before its
+	// AI analysis has filled in the applicable sections.
+	ErrAnalysisPending = errors.New("the FIR's AI analysis has not finished; submit it once the applicable sections are filled in")
+)
+
+// firTransitions lists the statuses each status may move to.
+var firTransitions = map[string][]string{
+	models.FIRStatusDraft:              {models.FIRStatusSubmitted},
+	models.FIRStatusSubmitted:          {models.FIRStatusUnderInvestigation},
+	models.FIRStatusUnderInvestigation: {models.FIRStatusClosed},
+	models.FIRStatusClosed:             {},
+}
+
+// InvalidTransitionError is returned when a status change is not allowed
+// from the FIR's current status.
+type InvalidTransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	if _, known := firTransitions[e.To]; !known {
+		return fmt.Sprintf("unknown FIR status %q", e.To)
+	}
+	return fmt.Sprintf("cannot change FIR status from %s to %s", e.From, e.To)
+}
+
+// checkTransition returns an *InvalidTransitionError unless an FIR may
+// move from one status to the other.
+func checkTransition(from, to string) error {
+	allowed := firTransitions[from]
+	for _, status := range allowed {
+		if status == to {
+			return nil
+		}
+	}
+	return &InvalidTransitionError{From: from, To: to, Allowed: allowed}
+}
+
+// checkSubmittable returns ErrAnalysisPending while a draft's analysis is
+// pending. The applicable sections are sealed on submission and never
+// filled in afterwards, so submitting earlier would register the FIR
+// without them. A failed analysis does not block submission.
+func checkSubmittable(fir *models.FIR) error {
+	if fir.AIAnalysis.Status == models.AnalysisStatusPending {
+		return ErrAnalysisPending
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from, to    string
+		wantErr     bool
+		wantAllowed []string
+		wantMessage string
+	}{
+		{from: models.FIRStatusDraft, to: models.FIRStatusSubmitted},
+		{from: models.FIRStatusSubmitted, to: models.FIRStatusUnderInvestigation},
+		{from: models.FIRStatusUnderInvestigation, to: models.FIRStatusClosed},
+		{
+			from: models.FIRStatusDraft, to: models.FIRStatusClosed, wantErr: true,
+			wantAllowed: []string{models.FIRStatusSubmitted},
+			wantMessage: "cannot change FIR status from draft to closed",
+		},
+		{
+			from: models.FIRStatusSubmitted, to: models.FIRStatusDraft, wantErr: true,
+			wantAllowed: []string{models.FIRStatusUnderInvestigation},
+			wantMessage: "cannot change FIR status from submitted to draft",
+		},
+		{
+			from: models.FIRStatusClosed, to: models.FIRStatusUnderInvestigation, wantErr: true,
+			wantAllowed: []string{},
+			wantMessage: "cannot change FIR status from closed to under_investigation",
+		},
+		{
+			from: models.FIRStatusDraft, to: models.FIRStatusDraft, wantErr: true,
+			wantAllowed: []string{models.FIRStatusSubmitted},
+			wantMessage: "cannot change FIR status from draft to draft",
+		},
+		{
+			from: models.FIRStatusDraft, to: "archived", wantErr: true,
+			wantAllowed: []string{models.FIRStatusSubmitted},
+			wantMessage: `unknown FIR status "archived"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("checkTransition() error = %v", err)
+				}
+				return
+			}
+
+			var transitionErr *InvalidTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("checkTransition() error = %v, want *InvalidTransitionError", err)
+			}
+			if !reflect.DeepEqual(transitionErr.Allowed, tt.wantAllowed) {
+				t.Errorf("Allowed = %q, want %q", transitionErr.Allowed, tt.wantAllowed)
+			}
+			if err.Error() != tt.wantMessage {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCheckSubmittable(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr error
+	}{
+		{models.AnalysisStatusPending, ErrAnalysisPending},
+		{models.AnalysisStatusCompleted, nil},
+		{models.AnalysisStatusFailed, nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		fir := &models.FIR{Status: models.FIRStatusDraft, AIAnalysis: models.AIAnalysis{Status: tt.status}}
+		if err := checkSubmittable(fir); !errors.Is(err, tt.wantErr) {
+			t.Errorf("checkSubmittable() with analysis %q error = %v, want %v", tt.status, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrKeystoreNotConfigured is returned when signing is attempted
+	// without an encryption key for the keystore.
+	ErrKeystoreNotConfigured = errors.New("signing keystore is not configured; set ENCRYPTION_KEY")
+	// ErrOfficerKeyNotFound is returned when an officer has no key with
+	// the requested ID.
+	ErrOfficerKeyNotFound = errors.New("officer signing key not found")
+)
+
+// Keystore holds one Ed25519 signing key per officer. Private keys are
+// stored as seeds encrypted with AES-256-GCM under a key derived from
+// ENCRYPTION_KEY, bound to the officer they belong to, and are only
+// decrypted to sign.
+type Keystore struct {
+	collection string
+	keyring    *Keyring
+}
+
+// officerKeysPurpose derives the key officers' private keys are encrypted
+// under.
+const officerKeysPurpose = "officer-signing-keys"
+
+func NewKeystore(cfg *config.Config) *Keystore {
+	return &Keystore{collection: "officer_keys", keyring: NewKeyring(cfg)}
+}
+
+// EnsureIndexes creates the unique index that gives each officer a single
+// key.
+func (k *Keystore) EnsureIndexes(ctx context.Context) error {
+	_, err := database.GetCollection(k.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "officer_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Signer returns an officer's private key and its public record, creating
+// the keypair on first use.
+func (k *Keystore) Signer(ctx context.Context, officerID primitive.ObjectID) (ed25519.PrivateKey, *models.OfficerKey, error) {
+	if !k.keyring.configured() {
+		return nil, nil, ErrKeystoreNotConfigured
+	}
+
+	key, err := k.find(ctx, bson.M{"officer_id": officerID})
+	if errors.Is(err, ErrOfficerKeyNotFound) {
+		key, err = k.create(ctx, officerID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seed, err := k.open(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	if !private.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(key.PublicKey)) {
+		return nil, nil, errors.New("officer signing key does not match its public key")
+	}
+	return private, key, nil
+}
+
+// PublicKey returns an officer's key with the given ID.
+func (k *Keystore) PublicKey(ctx context.Context, officerID primitive.ObjectID, keyID string) (*models.OfficerKey, error) {
+	return k.find(ctx, bson.M{"officer_id": officerID, "key_id": keyID})
+}
+
+func (k *Keystore) find(ctx context.Context, filter bson.M) (*models.OfficerKey, error) {
+	var key models.OfficerKey
+	err := database.GetCollection(k.collection).FindOne(ctx, filter).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrOfficerKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (k *Keystore) create(ctx context.Context, officerID primitive.ObjectID) (*models.OfficerKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := k.seal(private.Seed(), officerID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.OfficerKey{
+		ID:                  primitive.NewObjectID(),
+		OfficerID:           officerID,
+		Algorithm:           models.SignatureAlgorithm,
+		KeyID:               signingKeyID(public),
+		PublicKey:           public,
+		EncryptedPrivateKey: encrypted,
+		SealedWith:          k.keyring.currentID,
+		CreatedAt:           time.Now(),
+	}
+	_, err = database.GetCollection(k.collection).InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the officer's key first.
+		return k.find(ctx, bson.M{"officer_id": officerID})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate re-encrypts every private key that is not encrypted under the
+// current encryption key and returns how many were re-encrypted.
+func (k *Keystore) Rotate(ctx context.Context) (int, error) {
+	if !k.keyring.configured() {
+		return 0, ErrKeystoreNotConfigured
+	}
+
+	collection := database.GetCollection(k.collection)
+	cursor, err := collection.Find(ctx, bson.M{"sealed_with": bson.M{"$ne": k.keyring.currentID}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	rotated := 0
+	for cursor.Next(ctx) {
+		var key models.OfficerKey
+		if err := cursor.Decode(&key); err != nil {
+			return rotated, err
+		}
+		seed, err := k.open(&key)
+		if err != nil {
+			return rotated, err
+		}
+		encrypted, err := k.seal(seed, key.OfficerID)
+		if err != nil {
+			return rotated, err
+		}
+
+		// Matching on the old ciphertext leaves a key alone if another
+		// run re-encrypted it first.
+		_, err = collection.UpdateOne(ctx, bson.M{
+			"_id":                   key.ID,
+			"encrypted_private_key": key.EncryptedPrivateKey,
+		}, bson.M{"$set": bson.M{
+			"encrypted_private_key": encrypted,
+			"sealed_with":           k.keyring.currentID,
+		}})
+		if err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+	return rotated, cursor.Err()
+}
+
+// seal encrypts an officer's private key under the current encryption
+// key. The officer ID is authenticated with it, so a key copied to another
+// officer's record fails to decrypt.
+func (k *Keystore) seal(seed []byte, officerID primitive.ObjectID) ([]byte, error) {
+	key, err := k.keyring.key(k.keyring.currentID, officerKeysPurpose)
+	if err != nil {
+		return nil, err
+	}
+	return sealGCM(key, seed, officerID[:])
+}
+
+// open decrypts an officer's private key. Keys from before key IDs were
+// recorded are tried against every configured encryption key.
+func (k *Keystore) open(officerKey *models.OfficerKey) ([]byte, error) {
+	ids := []string{officerKey.SealedWith}
+	if officerKey.SealedWith == "" {
+		ids = k.keyring.ids()
+	}
+	for _, id := range ids {
+		key, err := k.keyring.key(id, officerKeysPurpose)
+		if err != nil {
+			return nil, err
+		}
+		if seed, err := openGCM(key, officerKey.EncryptedPrivateKey, officerKey.OfficerID[:]); err == nil {
+			return seed, nil
+		}
+	}
+	return nil, errors.New("officer signing key cannot be decrypted; has ENCRYPTION_KEY changed?")
+}
+
+// signingKeyID identifies a public key by the first 8 bytes of its
+// SHA-256, in hex.
+func signingKeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
@@ -2,21 +2,23 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"legalassist-ai-backend/database"
 	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type LegalService struct {
-	sectionsCollection   string
-	caseLawsCollection   string
-	judgmentsCollection  string
+	sectionsCollection  string
+	caseLawsCollection  string
+	judgmentsCollection string
 }
 
 func NewLegalService() *LegalService {
@@ -27,268 +29,120 @@ func NewLegalService() *LegalService {
 	}
 }
 
-func (s *LegalService) SearchLaws(query string, category string, page, limit int) (map[string]interface{}, error) {
-	// Create search filter
-	filter := bson.M{}
-	if query != "" {
-		filter["$or"] = []bson.M{
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"description": bson.M{"$regex": query, "$options": "i"}},
-			{"keywords": bson.M{"$in": []string{query}}},
-		}
-	}
-	if category != "" && category != "all" {
-		filter["category"] = category
-	}
+// ErrInvalidSort is returned when a list is asked to sort on a field it
+// does not support.
+var ErrInvalidSort = errors.New("invalid sort field")
 
-	// Search legal sections
-	sections, sectionsTotal, _ := s.searchSections(filter, page, limit)
-	
-	// Search case laws
-	caseLaws, caseLawsTotal, _ := s.searchCaseLaws(filter, page, limit)
-	
-	// Search judgments
-	judgments, judgmentsTotal, _ := s.searchJudgments(filter, page, limit)
-
-	return map[string]interface{}{
-		"sections": map[string]interface{}{
-			"data":  sections,
-			"total": sectionsTotal,
-		},
-		"case_laws": map[string]interface{}{
-			"data":  caseLaws,
-			"total": caseLawsTotal,
-		},
-		"judgments": map[string]interface{}{
-			"data":  judgments,
-			"total": judgmentsTotal,
-		},
-	}, nil
-}
+// Sortable fields and defaults for the legal list endpoints. A leading "-"
+// in a sort parameter sorts descending.
+var (
+	sectionSortFields  = map[string]bool{"section": true, "title": true, "category": true, "updated_at": true}
+	caseLawSortFields  = map[string]bool{"year": true, "title": true, "court": true, "importance": true}
+	judgmentSortFields = map[string]bool{"year": true, "case": true, "court": true}
+)
 
-func (s *LegalService) searchSections(filter bson.M, page, limit int) ([]models.LegalSection, int64, error) {
-	collection := database.GetCollection(s.sectionsCollection)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// numericCollation sorts "64" before "354" and "498A" after "498".
+var numericCollation = &options.Collation{Locale: "en", NumericOrdering: true}
 
-	// Get mock data if collection is empty
-	count, _ := collection.CountDocuments(ctx, bson.M{})
-	if count == 0 {
-		return s.getMockSections(), 3, nil
+// ListSections returns the sections of an act, paginated and sorted.
+func (s *LegalService) ListSections(act, sort string, page, limit int) ([]models.LegalSection, int64, error) {
+	if canonical, err := NormalizeAct(act); err == nil {
+		act = canonical
+	}
+	sortSpec, err := parseSort(sort, "section", sectionSortFields)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	total, err := collection.CountDocuments(ctx, filter)
+	sections := []models.LegalSection{}
+	total, err := s.list(s.sectionsCollection, bson.M{"act": act}, sortSpec, page, limit, &sections)
+	return sections, total, err
+}
+
+// ListCaseLaws returns case laws that cite a section, paginated and sorted.
+func (s *LegalService) ListCaseLaws(section, sort string, page, limit int) ([]models.CaseLawRecord, int64, error) {
+	sortSpec, err := parseSort(sort, "-year", caseLawSortFields)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	opts := options.Find()
-	opts.SetSkip(int64((page - 1) * limit))
-	opts.SetLimit(int64(limit))
-	opts.SetSort(bson.D{{"section", 1}})
+	caseLaws := []models.CaseLawRecord{}
+	total, err := s.list(s.caseLawsCollection, bson.M{"sections": normalizeSection(section)}, sortSpec, page, limit, &caseLaws)
+	return caseLaws, total, err
+}
 
-	cursor, err := collection.Find(ctx, filter, opts)
+// ListLandmarkJudgments returns landmark judgments, paginated and sorted.
+func (s *LegalService) ListLandmarkJudgments(sort string, page, limit int) ([]models.LandmarkJudgment, int64, error) {
+	sortSpec, err := parseSort(sort, "-year", judgmentSortFields)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(ctx)
 
-	var sections []models.LegalSection
-	err = cursor.All(ctx, &sections)
-	return sections, total, err
+	judgments := []models.LandmarkJudgment{}
+	total, err := s.list(s.judgmentsCollection, bson.M{}, sortSpec, page, limit, &judgments)
+	return judgments, total, err
 }
 
-func (s *LegalService) searchCaseLaws(filter bson.M, page, limit int) ([]models.CaseLawRecord, int64, error) {
-	collection := database.GetCollection(s.caseLawsCollection)
+// list runs a paginated, sorted find and decodes the page into results.
+func (s *LegalService) list(collectionName string, filter bson.M, sortSpec bson.D, page, limit int, results interface{}) (int64, error) {
+	collection := database.GetCollection(collectionName)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Get mock data if collection is empty
-	count, _ := collection.CountDocuments(ctx, bson.M{})
-	if count == 0 {
-		return s.getMockCaseLaws(), 2, nil
-	}
-
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
 
+	offset, limit := utils.Paginate(page, limit)
 	opts := options.Find()
-	opts.SetSkip(int64((page - 1) * limit))
+	opts.SetSort(sortSpec)
+	opts.SetSkip(int64(offset))
 	opts.SetLimit(int64(limit))
-	opts.SetSort(bson.D{{"year", -1}})
+	opts.SetCollation(numericCollation)
+	opts.SetProjection(bson.M{"embedding": 0, "full_text": 0})
 
 	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
 	defer cursor.Close(ctx)
 
-	var caseLaws []models.CaseLawRecord
-	err = cursor.All(ctx, &caseLaws)
-	return caseLaws, total, err
+	return total, cursor.All(ctx, results)
 }
 
-func (s *LegalService) searchJudgments(filter bson.M, page, limit int) ([]models.LandmarkJudgment, int64, error) {
-	collection := database.GetCollection(s.judgmentsCollection)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Get mock data if collection is empty
-	count, _ := collection.CountDocuments(ctx, bson.M{})
-	if count == 0 {
-		return s.getMockJudgments(), 2, nil
+// parseSort turns "field" or "-field" into a sort document, falling back to
+// fallback when sort is empty. Ties are broken by _id for stable paging.
+func parseSort(sort, fallback string, allowed map[string]bool) (bson.D, error) {
+	if sort == "" {
+		sort = fallback
 	}
 
-	// Modify filter for judgments
-	judgmentFilter := bson.M{}
-	if filter["$or"] != nil {
-		judgmentFilter["$or"] = []bson.M{
-			{"case": bson.M{"$regex": filter["$or"].([]bson.M)[0]["title"].(bson.M)["$regex"], "$options": "i"}},
-			{"significance": bson.M{"$regex": filter["$or"].([]bson.M)[0]["title"].(bson.M)["$regex"], "$options": "i"}},
-		}
+	field, direction := sort, 1
+	if strings.HasPrefix(sort, "-") {
+		field, direction = sort[1:], -1
 	}
-
-	total, err := collection.CountDocuments(ctx, judgmentFilter)
-	if err != nil {
-		return nil, 0, err
+	if !allowed[field] {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSort, field)
 	}
 
-	opts := options.Find()
-	opts.SetSkip(int64((page - 1) * limit))
-	opts.SetLimit(int64(limit))
-	opts.SetSort(bson.D{{"year", -1}})
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}, nil
+}
 
-	cursor, err := collection.Find(ctx, judgmentFilter, opts)
+// FindSections loads the given section numbers of an act.
+func (s *LegalService) FindSections(ctx context.Context, act string, numbers []string) ([]models.LegalSection, error) {
+	collection := database.GetCollection(s.sectionsCollection)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"embedding": 0})
+	cursor, err := collection.Find(ctx, bson.M{"act": act, "section": bson.M{"$in": numbers}}, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var judgments []models.LandmarkJudgment
-	err = cursor.All(ctx, &judgments)
-	return judgments, total, err
-}
-
-// Mock data functions
-func (s *LegalService) getMockSections() []models.LegalSection {
-	return []models.LegalSection{
-		{
-			ID:              primitive.NewObjectID(),
-			Section:         "354",
-			Act:             "Indian Penal Code",
-			Title:           "Assault or criminal force to woman with intent to outrage her modesty",
-			Description:     "Whoever assaults or uses criminal force to any woman, intending to outrage or knowing it to be likely that he will thereby outrage her modesty, shall be punished with imprisonment of either description for a term which may extend to two years, or with fine, or with both.",
-			Category:        "crimes_against_women",
-			Amendments:      []string{"Criminal Law Amendment Act, 2013"},
-			RelatedSections: []string{"354A", "354B", "354C", "354D"},
-			Keywords:        []string{"assault", "woman", "modesty", "criminal force"},
-			Punishment:      "Imprisonment up to 2 years or fine or both",
-			IsBailable:      true,
-			IsCognizable:    true,
-			CreatedAt:       time.Now(),
-			UpdatedAt:       time.Now(),
-		},
-		{
-			ID:              primitive.NewObjectID(),
-			Section:         "420",
-			Act:             "Indian Penal Code",
-			Title:           "Cheating and dishonestly inducing delivery of property",
-			Description:     "Whoever cheats and thereby dishonestly induces the person deceived to deliver any property to any person, or to make, alter or destroy the whole or any part of a valuable security, shall be punished with imprisonment of either description for a term which may extend to seven years, and shall also be liable to fine.",
-			Category:        "property_crimes",
-			Amendments:      []string{},
-			RelatedSections: []string{"415", "417", "418", "419"},
-			Keywords:        []string{"cheating", "fraud", "property", "deception"},
-			Punishment:      "Imprisonment up to 7 years and fine",
-			IsBailable:      false,
-			IsCognizable:    true,
-			CreatedAt:       time.Now(),
-			UpdatedAt:       time.Now(),
-		},
-		{
-			ID:              primitive.NewObjectID(),
-			Section:         "498A",
-			Act:             "Indian Penal Code",
-			Title:           "Husband or relative of husband of a woman subjecting her to cruelty",
-			Description:     "Whoever, being the husband or the relative of the husband of a woman, subjects such woman to cruelty shall be punished with imprisonment for a term which may extend to three years and shall also be liable to fine.",
-			Category:        "crimes_against_women",
-			Amendments:      []string{"Criminal Law Amendment Act, 1983"},
-			RelatedSections: []string{"304B", "406", "34"},
-			Keywords:        []string{"dowry", "cruelty", "husband", "domestic violence"},
-			Punishment:      "Imprisonment up to 3 years and fine",
-			IsBailable:      false,
-			IsCognizable:    true,
-			CreatedAt:       time.Now(),
-			UpdatedAt:       time.Now(),
-		},
-	}
-}
-
-func (s *LegalService) getMockCaseLaws() []models.CaseLawRecord {
-	return []models.CaseLawRecord{
-		{
-			ID:          primitive.NewObjectID(),
-			Title:       "Vishaka & Ors vs State Of Rajasthan & Ors",
-			Court:       "Supreme Court of India",
-			Year:        "1997",
-			Judges:      []string{"Justice J.S. Verma", "Justice Sujata V. Manohar", "Justice B.N. Kirpal"},
-			Summary:     "This landmark judgment established guidelines for prevention of sexual harassment of women at workplace and the obligations of employers.",
-			KeyPoints:   []string{"Established Vishaka Guidelines", "Sexual harassment as violation of fundamental rights", "Employer obligations", "Complaint procedures"},
-			Citations:   []string{"AIR 1997 SC 3011", "1997 (6) SCC 241"},
-			Category:    "women_rights",
-			Importance:  "landmark",
-			LegalIssues: []string{"Sexual harassment", "Workplace safety", "Fundamental rights"},
-			Sections:    []string{"354", "509"},
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          primitive.NewObjectID(),
-			Title:       "State of Punjab vs Gurmit Singh & Ors",
-			Court:       "Supreme Court of India",
-			Year:        "1996",
-			Judges:      []string{"Justice K. Ramaswamy", "Justice S. Saghir Ahmad"},
-			Summary:     "This case clarified the scope and meaning of outraging modesty under Section 354 IPC and established important precedents.",
-			KeyPoints:   []string{"Definition of outraging modesty", "Physical contact not always necessary", "Mens rea requirement", "Victim testimony importance"},
-			Citations:   []string{"AIR 1996 SC 1393", "1996 (2) SCC 384"},
-			Category:    "criminal_law",
-			Importance:  "significant",
-			LegalIssues: []string{"Outraging modesty", "Section 354", "Criminal law"},
-			Sections:    []string{"354"},
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-	}
+	var sections []models.LegalSection
+	err = cursor.All(ctx, &sections)
+	return sections, err
 }
-
-func (s *LegalService) getMockJudgments() []models.LandmarkJudgment {
-	return []models.LandmarkJudgment{
-		{
-			ID:            primitive.NewObjectID(),
-			Case:          "Kesavananda Bharati vs State of Kerala",
-			Court:         "Supreme Court of India",
-			Year:          "1973",
-			Significance:  "Established the Basic Structure Doctrine of the Constitution",
-			Impact:        "Fundamental principle that limits Parliament's power to amend the Constitution",
-			KeyPrinciples: []string{"Basic Structure Doctrine", "Parliamentary limitations", "Constitutional supremacy", "Judicial review"},
-			LegalDoctrine: "Basic Structure Doctrine",
-			Precedent:     "Constitutional amendments cannot alter the basic structure of the Constitution",
-			CreatedAt:     time.Now(),
-			UpdatedAt:     time.Now(),
-		},
-		{
-			ID:            primitive.NewObjectID(),
-			Case:          "Maneka Gandhi vs Union of India",
-			Court:         "Supreme Court of India",
-			Year:          "1978",
-			Significance:  "Expanded the scope of Article 21 (Right to Life and Personal Liberty)",
-			Impact:        "Established that right to life includes right to live with dignity",
-			KeyPrinciples: []string{"Expanded Article 21", "Right to dignity", "Procedural due process", "Interconnected rights"},
-			LegalDoctrine: "Expanded interpretation of fundamental rights",
-			Precedent:     "Right to life and personal liberty includes various facets of human dignity",
-			CreatedAt:     time.Now(),
-			UpdatedAt:     time.Now(),
-		},
-	}
-}
\ No newline at end of file
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLegalRecordNotFound is returned when an admin operation targets a
+// record that does not exist.
+var ErrLegalRecordNotFound = errors.New("legal record not found")
+
+// ValidationError lists every problem found in a legal record.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + strings.Join(e.Problems, "; ")
+}
+
+var yearPattern = regexp.MustCompile(`^\d{4}$`)
+
+// EnsureIndexes creates the unique indexes that back import upserts.
+func (s *LegalService) EnsureIndexes(ctx context.Context) error {
+	indexes := map[string]mongo.IndexModel{
+		s.sectionsCollection: {
+			Keys:    bson.D{{Key: "act", Value: 1}, {Key: "section", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		s.caseLawsCollection: {
+			Keys:    bson.D{{Key: "citations", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		s.judgmentsCollection: {
+			Keys:    bson.D{{Key: "case", Value: 1}, {Key: "year", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	for name, index := range indexes {
+		if _, err := database.GetCollection(name).Indexes().CreateOne(ctx, index); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CreateSection validates and inserts a new legal section.
+func (s *LegalService) CreateSection(section models.LegalSection) (*models.LegalSection, error) {
+	if problems := validateSection(&section); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	section.ID = primitive.NewObjectID()
+	section.Embedding = nil
+	section.CreatedAt = time.Now()
+	section.UpdatedAt = section.CreatedAt
+
+	if err := s.insertRecord(s.sectionsCollection, section); err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// UpdateSection replaces the editable fields of a section. Its embedding is
+// cleared so it is recomputed from the new text.
+func (s *LegalService) UpdateSection(id string, section models.LegalSection) (*models.LegalSection, error) {
+	if problems := validateSection(&section); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	section.Embedding = nil
+
+	var updated models.LegalSection
+	if err := s.replaceRecord(s.sectionsCollection, id, section, bson.M{"embedding": ""}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteSection removes a legal section.
+func (s *LegalService) DeleteSection(id string) error {
+	return s.deleteRecord(s.sectionsCollection, id)
+}
+
+// CreateCaseLaw validates and inserts a new case law.
+func (s *LegalService) CreateCaseLaw(caseLaw models.CaseLawRecord) (*models.CaseLawRecord, error) {
+	if problems := validateCaseLaw(&caseLaw); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	caseLaw.ID = primitive.NewObjectID()
+	caseLaw.CreatedAt = time.Now()
+	caseLaw.UpdatedAt = caseLaw.CreatedAt
+
+	if err := s.insertRecord(s.caseLawsCollection, caseLaw); err != nil {
+		return nil, err
+	}
+	return &caseLaw, nil
+}
+
+// UpdateCaseLaw replaces the editable fields of a case law.
+func (s *LegalService) UpdateCaseLaw(id string, caseLaw models.CaseLawRecord) (*models.CaseLawRecord, error) {
+	if problems := validateCaseLaw(&caseLaw); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var updated models.CaseLawRecord
+	if err := s.replaceRecord(s.caseLawsCollection, id, caseLaw, nil, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCaseLaw removes a case law.
+func (s *LegalService) DeleteCaseLaw(id string) error {
+	return s.deleteRecord(s.caseLawsCollection, id)
+}
+
+// CreateJudgment validates and inserts a new landmark judgment.
+func (s *LegalService) CreateJudgment(judgment models.LandmarkJudgment) (*models.LandmarkJudgment, error) {
+	if problems := validateJudgment(&judgment); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	judgment.ID = primitive.NewObjectID()
+	judgment.CreatedAt = time.Now()
+	judgment.UpdatedAt = judgment.CreatedAt
+
+	if err := s.insertRecord(s.judgmentsCollection, judgment); err != nil {
+		return nil, err
+	}
+	return &judgment, nil
+}
+
+// UpdateJudgment replaces the editable fields of a landmark judgment.
+func (s *LegalService) UpdateJudgment(id string, judgment models.LandmarkJudgment) (*models.LandmarkJudgment, error) {
+	if problems := validateJudgment(&judgment); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var updated models.LandmarkJudgment
+	if err := s.replaceRecord(s.judgmentsCollection, id, judgment, nil, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteJudgment removes a landmark judgment.
+func (s *LegalService) DeleteJudgment(id string) error {
+	return s.deleteRecord(s.judgmentsCollection, id)
+}
+
+func (s *LegalService) insertRecord(collectionName string, record interface{}) error {
+	collection := database.GetCollection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, record); err != nil {
+		return err
+	}
+	invalidateSearchIndex()
+	return nil
+}
+
+// replaceRecord overwrites every field of record except _id and created_at
+// and decodes the updated document into result.
+func (s *LegalService) replaceRecord(collectionName, id string, record interface{}, unset bson.M, result interface{}) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrLegalRecordNotFound
+	}
+
+	fields, err := recordFields(record)
+	if err != nil {
+		return err
+	}
+	fields["updated_at"] = time.Now()
+
+	update := bson.M{"$set": fields}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	collection := database.GetCollection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"embedding": 0})
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrLegalRecordNotFound
+	}
+	if err != nil {
+		return err
+	}
+	invalidateSearchIndex()
+	return nil
+}
+
+func (s *LegalService) deleteRecord(collectionName, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrLegalRecordNotFound
+	}
+
+	collection := database.GetCollection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrLegalRecordNotFound
+	}
+	invalidateSearchIndex()
+	return nil
+}
+
+// recordFields converts a record to a $set document, leaving out the fields
+// that are fixed when the record is created.
+func recordFields(record interface{}) (bson.M, error) {
+	data, err := bson.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "_id")
+	delete(fields, "created_at")
+	return fields, nil
+}
+
+// validateSection normalises a section in place and returns its problems.
+func validateSection(section *models.LegalSection) []string {
+	var problems []string
+
+	section.Act = strings.TrimSpace(section.Act)
+	if canonical, err := NormalizeAct(section.Act); err == nil {
+		section.Act = canonical
+	}
+	section.Section = normalizeSection(section.Section)
+	section.Title = strings.TrimSpace(section.Title)
+	section.Category = strings.TrimSpace(section.Category)
+
+	if section.Act == "" {
+		problems = append(problems, "act is required")
+	}
+	if section.Section == "" {
+		problems = append(problems, "section is required")
+	}
+	if section.Title == "" {
+		problems = append(problems, "title is required")
+	}
+	if strings.TrimSpace(section.Description) == "" {
+		problems = append(problems, "description is required")
+	}
+
+	section.Amendments = compactStrings(section.Amendments)
+	section.Keywords = compactStrings(section.Keywords)
+	section.RelatedSections = compactStrings(section.RelatedSections)
+	for i, related := range section.RelatedSections {
+		section.RelatedSections[i] = normalizeSection(related)
+	}
+
+	return problems
+}
+
+// validateCaseLaw normalises a case law in place and returns its problems.
+func validateCaseLaw(caseLaw *models.CaseLawRecord) []string {
+	var problems []string
+
+	caseLaw.Title = strings.TrimSpace(caseLaw.Title)
+	caseLaw.Court = strings.TrimSpace(caseLaw.Court)
+	caseLaw.Year = strings.TrimSpace(caseLaw.Year)
+	caseLaw.Importance = strings.ToLower(strings.TrimSpace(caseLaw.Importance))
+	caseLaw.Citations = compactStrings(caseLaw.Citations)
+
+	if caseLaw.Title == "" {
+		problems = append(problems, "title is required")
+	}
+	if caseLaw.Court == "" {
+		problems = append(problems, "court is required")
+	}
+	if !yearPattern.MatchString(caseLaw.Year) {
+		problems = append(problems, "year must be a four-digit year")
+	}
+	if len(caseLaw.Citations) == 0 {
+		problems = append(problems, "at least one citation is required")
+	}
+	switch caseLaw.Importance {
+	case "landmark", "significant", "reference":
+	case "":
+		caseLaw.Importance = "reference"
+	default:
+		problems = append(problems, "importance must be landmark, significant or reference")
+	}
+
+	caseLaw.Judges = compactStrings(caseLaw.Judges)
+	caseLaw.KeyPoints = compactStrings(caseLaw.KeyPoints)
+	caseLaw.LegalIssues = compactStrings(caseLaw.LegalIssues)
+	caseLaw.Sections = compactStrings(caseLaw.Sections)
+	for i, section := range caseLaw.Sections {
+		caseLaw.Sections[i] = normalizeSection(section)
+	}
+
+	return problems
+}
+
+// validateJudgment normalises a judgment in place and returns its problems.
+func validateJudgment(judgment *models.LandmarkJudgment) []string {
+	var problems []string
+
+	judgment.Case = strings.TrimSpace(judgment.Case)
+	judgment.Court = strings.TrimSpace(judgment.Court)
+	judgment.Year = strings.TrimSpace(judgment.Year)
+
+	if judgment.Case == "" {
+		problems = append(problems, "case is required")
+	}
+	if judgment.Court == "" {
+		problems = append(problems, "court is required")
+	}
+	if !yearPattern.MatchString(judgment.Year) {
+		problems = append(problems, "year must be a four-digit year")
+	}
+	if strings.TrimSpace(judgment.Significance) == "" {
+		problems = append(problems, "significance is required")
+	}
+
+	judgment.KeyPrinciples = compactStrings(judgment.KeyPrinciples)
+
+	return problems
+}
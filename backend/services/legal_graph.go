@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// citationActOrder resolves the unqualified section numbers cited by case
+// laws. Most case laws in the corpus interpret the IPC, so a number is read
+// as an IPC section when one exists and otherwise as the first act in this
+// list that has it.
+var citationActOrder = []string{
+	models.ActIPC,
+	models.ActBNS,
+	models.ActCrPC,
+	models.ActBNSS,
+	models.ActIEA,
+	models.ActBSA,
+}
+
+// SectionNode is a legal section in the citation graph. CitedBy counts the
+// case laws that cite it.
+type SectionNode struct {
+	ID      string `json:"id"`
+	Act     string `json:"act"`
+	Section string `json:"section"`
+	Title   string `json:"title"`
+	CitedBy int    `json:"cited_by"`
+}
+
+// SectionReference is a section string from a record and the section it
+// resolves to. Section is nil when the reference is dangling.
+type SectionReference struct {
+	Ref     string       `json:"ref"`
+	Section *SectionNode `json:"section,omitempty"`
+}
+
+// CaseLawNode is a case law in the citation graph. Via names the equivalent
+// section a case law was reached through when it does not cite the section
+// directly. JudgmentID links to the landmark judgment for the same case.
+type CaseLawNode struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Court      string `json:"court"`
+	Year       string `json:"year"`
+	Importance string `json:"importance"`
+	JudgmentID string `json:"judgment_id,omitempty"`
+	Via        string `json:"via,omitempty"`
+}
+
+// SectionGraph is the neighbourhood of a section: the sections it lists as
+// related, the sections that list it, its counterparts across the 2024
+// reform and the case laws interpreting it or its counterparts.
+type SectionGraph struct {
+	Section         SectionNode        `json:"section"`
+	RelatedSections []SectionReference `json:"related_sections"`
+	ReferencedBy    []SectionNode      `json:"referenced_by"`
+	Equivalents     []SectionReference `json:"equivalents"`
+	CaseLaws        []CaseLawNode      `json:"case_laws"`
+}
+
+// DanglingReference is a section string that does not resolve to any
+// section in the legal database.
+type DanglingReference struct {
+	Collection string `json:"collection"`
+	ID         string `json:"id"`
+	Record     string `json:"record"`
+	Field      string `json:"field"`
+	Ref        string `json:"ref"`
+}
+
+type graphSection struct {
+	record  models.LegalSection
+	related []SectionReference
+	citedBy []*graphCaseLaw
+}
+
+type graphCaseLaw struct {
+	record     models.CaseLawRecord
+	judgmentID string
+}
+
+// citationGraph links every section and case law in the database. The
+// corpus is small enough to resolve in memory on each request.
+type citationGraph struct {
+	sections     map[string]*graphSection
+	byKey        map[sectionKey]*graphSection
+	referencedBy map[string][]*graphSection
+	dangling     []DanglingReference
+}
+
+// SectionGraph returns the citation graph around the section with the
+// given ID.
+func (s *LegalService) SectionGraph(ctx context.Context, id string) (*SectionGraph, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, ErrLegalRecordNotFound
+	}
+
+	graph, err := s.loadCitationGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := graph.sections[id]
+	if !ok {
+		return nil, ErrLegalRecordNotFound
+	}
+
+	result := &SectionGraph{
+		Section:         node.node(),
+		RelatedSections: node.related,
+		ReferencedBy:    []SectionNode{},
+		Equivalents:     []SectionReference{},
+		CaseLaws:        []CaseLawNode{},
+	}
+	if result.RelatedSections == nil {
+		result.RelatedSections = []SectionReference{}
+	}
+	for _, referrer := range graph.referencedBy[id] {
+		result.ReferencedBy = append(result.ReferencedBy, referrer.node())
+	}
+
+	seen := make(map[string]bool)
+	for _, caseLaw := range node.citedBy {
+		seen[caseLaw.record.ID.Hex()] = true
+		result.CaseLaws = append(result.CaseLaws, caseLaw.node(""))
+	}
+
+	for _, ref := range s.EquivalentSections(node.record.Act, node.record.Section) {
+		label := actAbbreviations[ref.Act] + " " + ref.Section
+		equivalent := SectionReference{Ref: label}
+		target := graph.resolve(ref.Act, ref.Section)
+		if target != nil {
+			sectionNode := target.node()
+			equivalent.Section = &sectionNode
+			for _, caseLaw := range target.citedBy {
+				if id := caseLaw.record.ID.Hex(); !seen[id] {
+					seen[id] = true
+					result.CaseLaws = append(result.CaseLaws, caseLaw.node(label))
+				}
+			}
+		}
+		result.Equivalents = append(result.Equivalents, equivalent)
+	}
+
+	return result, nil
+}
+
+// DanglingReferences lists related-section and case-law section strings
+// that do not resolve to a section in the legal database.
+func (s *LegalService) DanglingReferences(ctx context.Context) ([]DanglingReference, error) {
+	graph, err := s.loadCitationGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if graph.dangling == nil {
+		return []DanglingReference{}, nil
+	}
+	return graph.dangling, nil
+}
+
+func (s *LegalService) loadCitationGraph(ctx context.Context) (*citationGraph, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var sections []models.LegalSection
+	if err := s.loadAll(ctx, s.sectionsCollection, bson.D{{Key: "act", Value: 1}, {Key: "section", Value: 1}}, &sections); err != nil {
+		return nil, err
+	}
+	var caseLaws []models.CaseLawRecord
+	if err := s.loadAll(ctx, s.caseLawsCollection, bson.D{{Key: "year", Value: -1}}, &caseLaws); err != nil {
+		return nil, err
+	}
+	var judgments []models.LandmarkJudgment
+	if err := s.loadAll(ctx, s.judgmentsCollection, bson.D{{Key: "year", Value: -1}}, &judgments); err != nil {
+		return nil, err
+	}
+
+	return buildCitationGraph(sections, caseLaws, judgments), nil
+}
+
+func buildCitationGraph(sections []models.LegalSection, caseLaws []models.CaseLawRecord, judgments []models.LandmarkJudgment) *citationGraph {
+	graph := &citationGraph{
+		sections:     make(map[string]*graphSection, len(sections)),
+		byKey:        make(map[sectionKey]*graphSection, len(sections)),
+		referencedBy: make(map[string][]*graphSection),
+	}
+	for _, section := range sections {
+		node := &graphSection{record: section}
+		graph.sections[section.ID.Hex()] = node
+		graph.byKey[sectionKey{section.Act, normalizeSection(section.Section)}] = node
+	}
+
+	judgmentIDs := make(map[string]string, len(judgments))
+	for _, judgment := range judgments {
+		judgmentIDs[caseKey(judgment.Case, judgment.Year)] = judgment.ID.Hex()
+	}
+
+	// Resolve related sections within the section's own act.
+	for _, section := range sections {
+		node := graph.sections[section.ID.Hex()]
+		for _, ref := range section.RelatedSections {
+			target := graph.resolve(section.Act, ref)
+			if target == node {
+				continue
+			}
+			reference := SectionReference{Ref: ref}
+			if target == nil {
+				graph.dangling = append(graph.dangling, DanglingReference{
+					Collection: "legal_sections",
+					ID:         section.ID.Hex(),
+					Record:     actAbbreviations[section.Act] + " " + section.Section,
+					Field:      "related_sections",
+					Ref:        ref,
+				})
+			} else {
+				targetID := target.record.ID.Hex()
+				graph.referencedBy[targetID] = append(graph.referencedBy[targetID], node)
+			}
+			node.related = append(node.related, reference)
+		}
+	}
+
+	for _, caseLaw := range caseLaws {
+		node := &graphCaseLaw{record: caseLaw, judgmentID: judgmentIDs[caseKey(caseLaw.Title, caseLaw.Year)]}
+
+		cited := make(map[*graphSection]bool)
+		for _, ref := range caseLaw.Sections {
+			target := graph.resolveCitation(ref)
+			if target == nil {
+				graph.dangling = append(graph.dangling, DanglingReference{
+					Collection: "case_laws",
+					ID:         caseLaw.ID.Hex(),
+					Record:     caseLaw.Title,
+					Field:      "sections",
+					Ref:        ref,
+				})
+				continue
+			}
+			if !cited[target] {
+				cited[target] = true
+				target.citedBy = append(target.citedBy, node)
+			}
+		}
+	}
+
+	// Related references were recorded before citations were counted, so
+	// fill in the targets now that cited_by is final.
+	for _, node := range graph.sections {
+		for i, reference := range node.related {
+			if target := graph.resolve(node.record.Act, reference.Ref); target != nil {
+				sectionNode := target.node()
+				node.related[i].Section = &sectionNode
+			}
+		}
+	}
+	for id := range graph.referencedBy {
+		referrers := graph.referencedBy[id]
+		sort.Slice(referrers, func(i, j int) bool {
+			if referrers[i].record.Act != referrers[j].record.Act {
+				return referrers[i].record.Act < referrers[j].record.Act
+			}
+			return referrers[i].record.Section < referrers[j].record.Section
+		})
+	}
+
+	return graph
+}
+
+// resolve finds a section of act, falling back to the base section so
+// "303(2)" resolves to section 303.
+func (g *citationGraph) resolve(act, ref string) *graphSection {
+	section := normalizeSection(ref)
+	if node, ok := g.byKey[sectionKey{act, section}]; ok {
+		return node
+	}
+	return g.byKey[sectionKey{act, baseSection(section)}]
+}
+
+// resolveCitation resolves an unqualified section number cited by a case
+// law using citationActOrder.
+func (g *citationGraph) resolveCitation(ref string) *graphSection {
+	for _, act := range citationActOrder {
+		if node := g.resolve(act, ref); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+func (n *graphSection) node() SectionNode {
+	return SectionNode{
+		ID:      n.record.ID.Hex(),
+		Act:     n.record.Act,
+		Section: n.record.Section,
+		Title:   n.record.Title,
+		CitedBy: len(n.citedBy),
+	}
+}
+
+func (n *graphCaseLaw) node(via string) CaseLawNode {
+	return CaseLawNode{
+		ID:         n.record.ID.Hex(),
+		Title:      n.record.Title,
+		Court:      n.record.Court,
+		Year:       n.record.Year,
+		Importance: n.record.Importance,
+		JudgmentID: n.judgmentID,
+		Via:        via,
+	}
+}
+
+var (
+	versusPattern      = regexp.MustCompile(`\b(?:vs?|versus)\b\.?`)
+	nonWordsPattern    = regexp.MustCompile(`[^a-z0-9]+`)
+	partySuffixPattern = regexp.MustCompile(`\b(?:ors|anr)\b`)
+)
+
+// caseKey identifies a case across collections regardless of how "vs"
+// and co-party suffixes are written.
+func caseKey(title, year string) string {
+	title = strings.ToLower(title)
+	title = versusPattern.ReplaceAllString(title, " v ")
+	title = strings.ReplaceAll(title, "&", " ")
+	title = partySuffixPattern.ReplaceAllString(title, " ")
+	title = nonWordsPattern.ReplaceAllString(title, " ")
+	return strings.Join(strings.Fields(title), " ") + "|" + strings.TrimSpace(year)
+}
@@ -0,0 +1,434 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Record kinds accepted by Import.
+const (
+	ImportKindSections  = "sections"
+	ImportKindCaseLaws  = "case-laws"
+	ImportKindJudgments = "judgments"
+)
+
+// File formats accepted by Import.
+const (
+	ImportFormatJSONL = "jsonl"
+	ImportFormatCSV   = "csv"
+)
+
+var (
+	ErrUnknownImportKind   = errors.New("unknown import kind")
+	ErrUnknownImportFormat = errors.New("unknown import format")
+)
+
+// csvListSeparator separates list values inside a single CSV cell.
+const csvListSeparator = ";"
+
+// ImportResult summarises a bulk import. In a dry run Inserted and Updated
+// are what the import would have done. Reembed counts the sections that
+// were added or whose text changed and so need a new embedding.
+type ImportResult struct {
+	Kind     string           `json:"kind"`
+	Format   string           `json:"format"`
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Reembed  int              `json:"reembed"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportRowError reports why a row of an import file was rejected. Row is
+// the line number in the file.
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+// importRow is a parsed, validated row ready to be upserted. Rows for
+// records with an embedding carry the text it is computed from. keys
+// describe what identifies the record; a case law has one per citation.
+type importRow struct {
+	line          int
+	key           bson.M
+	keys          []string
+	record        interface{}
+	embeddingText string
+	problems      []string
+}
+
+// Import upserts legal records from a JSON Lines or CSV file. Sections are
+// keyed on (act, section), case laws on any of their citations and
+// judgments on (case, year). Rows that fail validation are reported and
+// skipped; the rest are written unless dryRun is set.
+func (s *LegalService) Import(ctx context.Context, kind, format string, r io.Reader, dryRun bool) (*ImportResult, error) {
+	collectionName, err := s.importCollection(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	switch format {
+	case ImportFormatJSONL:
+		rows, err = parseJSONLines(kind, r)
+	case ImportFormatCSV:
+		rows, err = parseCSV(kind, r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownImportFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Kind: kind, Format: format, DryRun: dryRun, Total: len(rows), Errors: []ImportRowError{}}
+	collection := database.GetCollection(collectionName)
+	markDuplicates(rows)
+
+	for _, row := range rows {
+		if len(row.problems) > 0 {
+			result.Failed++
+			result.Errors = append(result.Errors, ImportRowError{Row: row.line, Errors: row.problems})
+			continue
+		}
+
+		if dryRun {
+			count, err := collection.CountDocuments(ctx, row.key, options.Count().SetLimit(1))
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				result.Updated++
+			} else {
+				result.Inserted++
+			}
+			continue
+		}
+
+		fields, err := recordFields(row.record)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		fields["updated_at"] = now
+
+		update := bson.M{
+			"$set":         fields,
+			"$setOnInsert": bson.M{"created_at": now},
+		}
+		if row.embeddingText != "" {
+			stale, err := embeddingStale(ctx, collection, row)
+			if err != nil {
+				return nil, err
+			}
+			if stale {
+				update["$unset"] = bson.M{"embedding": ""}
+				result.Reembed++
+			}
+		}
+
+		res, err := collection.UpdateOne(ctx, row.key, update, options.Update().SetUpsert(true))
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, ImportRowError{Row: row.line, Errors: []string{err.Error()}})
+			continue
+		}
+		if res.UpsertedCount > 0 {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if result.Inserted+result.Updated > 0 && !dryRun {
+		invalidateSearchIndex()
+	}
+	return result, nil
+}
+
+// markDuplicates rejects valid rows that share any key with an earlier
+// valid row, since both would upsert the same record. Two case laws that
+// share one citation are duplicates even if their other citations differ.
+func markDuplicates(rows []importRow) {
+	firstRowForKey := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		if len(row.problems) > 0 {
+			continue
+		}
+		for _, key := range row.keys {
+			if first, ok := firstRowForKey[key]; ok {
+				row.problems = append(row.problems, fmt.Sprintf("duplicate of row %d (%s)", first, key))
+			}
+		}
+		if len(row.problems) > 0 {
+			continue
+		}
+		for _, key := range row.keys {
+			firstRowForKey[key] = row.line
+		}
+	}
+}
+
+// embeddingStale reports whether the record a row upserts is new or has
+// different embedding text from the stored one. Unchanged records keep
+// their embedding rather than being queued to embed again.
+func embeddingStale(ctx context.Context, collection *mongo.Collection, row importRow) (bool, error) {
+	var existing models.LegalSection
+	opts := options.FindOne().SetProjection(bson.M{"embedding": 0})
+	err := collection.FindOne(ctx, row.key, opts).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sectionEmbeddingText(existing) != row.embeddingText, nil
+}
+
+func (s *LegalService) importCollection(kind string) (string, error) {
+	switch kind {
+	case ImportKindSections:
+		return s.sectionsCollection, nil
+	case ImportKindCaseLaws:
+		return s.caseLawsCollection, nil
+	case ImportKindJudgments:
+		return s.judgmentsCollection, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownImportKind, kind)
+	}
+}
+
+// ImportFormatFromFilename infers the import format from a file extension.
+func ImportFormatFromFilename(filename string) string {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return ImportFormatCSV
+	case strings.HasSuffix(lower, ".jsonl"), strings.HasSuffix(lower, ".ndjson"):
+		return ImportFormatJSONL
+	default:
+		return ""
+	}
+}
+
+func parseJSONLines(kind string, r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var rows []importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+
+		var row importRow
+		switch kind {
+		case ImportKindSections:
+			var section models.LegalSection
+			if err := decoder.Decode(&section); err != nil {
+				rows = append(rows, importRow{line: line, problems: []string{"invalid JSON: " + err.Error()}})
+				continue
+			}
+			row = sectionRow(section)
+		case ImportKindCaseLaws:
+			var caseLaw models.CaseLawRecord
+			if err := decoder.Decode(&caseLaw); err != nil {
+				rows = append(rows, importRow{line: line, problems: []string{"invalid JSON: " + err.Error()}})
+				continue
+			}
+			row = caseLawRow(caseLaw)
+		case ImportKindJudgments:
+			var judgment models.LandmarkJudgment
+			if err := decoder.Decode(&judgment); err != nil {
+				rows = append(rows, importRow{line: line, problems: []string{"invalid JSON: " + err.Error()}})
+				continue
+			}
+			row = judgmentRow(judgment)
+		}
+		row.line = line
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func parseCSV(kind string, r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, importRow{line: parseErr.StartLine, problems: []string{parseErr.Err.Error()}})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		cells := csvCells{columns: columns, record: record}
+		var row importRow
+		switch kind {
+		case ImportKindSections:
+			row = sectionRow(models.LegalSection{
+				Act:             cells.get("act"),
+				Section:         cells.get("section"),
+				Title:           cells.get("title"),
+				Description:     cells.get("description"),
+				Category:        cells.get("category"),
+				Amendments:      cells.list("amendments"),
+				RelatedSections: cells.list("related_sections"),
+				Keywords:        cells.list("keywords"),
+				Punishment:      cells.get("punishment"),
+				IsBailable:      cells.bool("is_bailable"),
+				IsCognizable:    cells.bool("is_cognizable"),
+			})
+		case ImportKindCaseLaws:
+			row = caseLawRow(models.CaseLawRecord{
+				Title:       cells.get("title"),
+				Court:       cells.get("court"),
+				Year:        cells.get("year"),
+				Judges:      cells.list("judges"),
+				Summary:     cells.get("summary"),
+				KeyPoints:   cells.list("key_points"),
+				Citations:   cells.list("citations"),
+				Category:    cells.get("category"),
+				Importance:  cells.get("importance"),
+				LegalIssues: cells.list("legal_issues"),
+				Sections:    cells.list("sections"),
+				FullText:    cells.get("full_text"),
+			})
+		case ImportKindJudgments:
+			row = judgmentRow(models.LandmarkJudgment{
+				Case:          cells.get("case"),
+				Court:         cells.get("court"),
+				Year:          cells.get("year"),
+				Significance:  cells.get("significance"),
+				Impact:        cells.get("impact"),
+				KeyPrinciples: cells.list("key_principles"),
+				LegalDoctrine: cells.get("legal_doctrine"),
+				Precedent:     cells.get("precedent"),
+			})
+		}
+		row.line = line
+		row.problems = append(row.problems, cells.problems...)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func sectionRow(section models.LegalSection) importRow {
+	problems := validateSection(&section)
+	section.Embedding = nil
+	return importRow{
+		key:           bson.M{"act": section.Act, "section": section.Section},
+		keys:          []string{section.Act + " " + section.Section},
+		record:        section,
+		embeddingText: sectionEmbeddingText(section),
+		problems:      problems,
+	}
+}
+
+func caseLawRow(caseLaw models.CaseLawRecord) importRow {
+	problems := validateCaseLaw(&caseLaw)
+	return importRow{
+		key:      bson.M{"citations": bson.M{"$in": caseLaw.Citations}},
+		keys:     caseLaw.Citations,
+		record:   caseLaw,
+		problems: problems,
+	}
+}
+
+func judgmentRow(judgment models.LandmarkJudgment) importRow {
+	problems := validateJudgment(&judgment)
+	return importRow{
+		key:      bson.M{"case": judgment.Case, "year": judgment.Year},
+		keys:     []string{judgment.Case + " (" + judgment.Year + ")"},
+		record:   judgment,
+		problems: problems,
+	}
+}
+
+// csvCells reads named columns from a CSV record and collects conversion
+// problems so they are reported with the row.
+type csvCells struct {
+	columns  map[string]int
+	record   []string
+	problems []string
+}
+
+func (c *csvCells) get(column string) string {
+	i, ok := c.columns[column]
+	if !ok || i >= len(c.record) {
+		return ""
+	}
+	return strings.TrimSpace(c.record[i])
+}
+
+func (c *csvCells) list(column string) []string {
+	value := c.get(column)
+	if value == "" {
+		return nil
+	}
+	return compactStrings(strings.Split(value, csvListSeparator))
+}
+
+func (c *csvCells) bool(column string) bool {
+	value := strings.ToLower(c.get(column))
+	switch value {
+	case "", "no", "n":
+		return false
+	case "yes", "y":
+		return true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		c.problems = append(c.problems, fmt.Sprintf("%s: %q is not a boolean", column, value))
+	}
+	return parsed
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarkDuplicates(t *testing.T) {
+	caseLaw := func(title string, citations ...string) string {
+		return `{"title": "` + title + `", "court": "Supreme Court of India", "year": "1994", "citations": ["` + strings.Join(citations, `", "`) + `"]}`
+	}
+
+	tests := []struct {
+		name string
+		kind string
+		file []string
+		want map[int][]string
+	}{
+		{
+			"case laws sharing one citation",
+			ImportKindCaseLaws,
+			[]string{
+				caseLaw("Joginder Kumar v. State of UP", "(1994) 4 SCC 260", "AIR 1994 SC 1349"),
+				caseLaw("Joginder Kumar v. State of U.P.", "AIR 1994 SC 1349", "1994 SCR (3) 661"),
+			},
+			map[int][]string{2: {"duplicate of row 1 (AIR 1994 SC 1349)"}},
+		},
+		{
+			"case laws with the same citations in another order",
+			ImportKindCaseLaws,
+			[]string{
+				caseLaw("D.K. Basu v. State of West Bengal", "(1997) 1 SCC 416", "AIR 1997 SC 610"),
+				caseLaw("D.K. Basu v. State of West Bengal", "AIR 1997 SC 610", "(1997) 1 SCC 416"),
+			},
+			map[int][]string{2: {"duplicate of row 1 (AIR 1997 SC 610)", "duplicate of row 1 ((1997) 1 SCC 416)"}},
+		},
+		{
+			"distinct case laws",
+			ImportKindCaseLaws,
+			[]string{
+				caseLaw("Joginder Kumar v. State of UP", "(1994) 4 SCC 260"),
+				caseLaw("Arnesh Kumar v. State of Bihar", "(2014) 8 SCC 273"),
+				caseLaw("Lalita Kumari v. Govt. of U.P.", "(2014) 2 SCC 1"),
+			},
+			map[int][]string{},
+		},
+		{
+			"invalid row does not claim its citations",
+			ImportKindCaseLaws,
+			[]string{
+				`{"title": "", "court": "Supreme Court of India", "year": "2014", "citations": ["(2014) 8 SCC 273"]}`,
+				caseLaw("Arnesh Kumar v. State of Bihar", "(2014) 8 SCC 273"),
+			},
+			map[int][]string{1: {"title is required"}},
+		},
+		{
+			"sections",
+			ImportKindSections,
+			[]string{
+				`{"act": "Bharatiya Nyaya Sanhita", "section": "303", "title": "Theft", "description": "Theft.", "punishment": "Imprisonment", "category": "property"}`,
+				`{"act": "Bharatiya Nyaya Sanhita", "section": "303", "title": "Theft again", "description": "Theft.", "punishment": "Imprisonment", "category": "property"}`,
+				`{"act": "Indian Penal Code", "section": "303", "title": "Murder by life-convict", "description": "Murder.", "punishment": "Death", "category": "violent"}`,
+			},
+			map[int][]string{2: {"duplicate of row 1 (Bharatiya Nyaya Sanhita 303)"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseJSONLines(tt.kind, strings.NewReader(strings.Join(tt.file, "\n")))
+			if err != nil {
+				t.Fatalf("parseJSONLines() error = %v", err)
+			}
+			markDuplicates(rows)
+
+			got := map[int][]string{}
+			for _, row := range rows {
+				if len(row.problems) > 0 {
+					got[row.line] = row.problems
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/search"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SectionCandidate is a legal section retrieved for an incident together
+// with the score it was ranked by.
+type SectionCandidate struct {
+	Section models.LegalSection
+	Score   float64 // 0-1
+}
+
+// CaseLawCandidate is a case law retrieved for an incident.
+type CaseLawCandidate struct {
+	CaseLaw models.CaseLawRecord
+	Score   float64 // 0-1
+}
+
+// Weights for the hybrid retrieval score. When either the query or a section
+// has no embedding only the keyword score is used.
+const (
+	keywordWeight   = 0.5
+	embeddingWeight = 0.5
+)
+
+// RetrieveSections ranks the legal_sections collection against an incident
+// description by keyword overlap and, when an embedder is given, cosine
+// similarity. Sections are scored in process, which is fine for the size of
+// a statute corpus. Only the fields scoring reads are loaded for the whole
+// collection; the full documents are fetched for the sections returned.
+func (s *LegalService) RetrieveSections(ctx context.Context, text string, embedder Embedder, limit int) ([]SectionCandidate, error) {
+	collection := database.GetCollection(s.sectionsCollection)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	queryTerms := termSet(text)
+	var queryEmbedding []float32
+	if embedder != nil {
+		if vectors, err := embedder.Embed(ctx, []string{text}); err == nil && len(vectors) == 1 {
+			queryEmbedding = vectors[0]
+		}
+	}
+
+	projection := bson.M{"title": 1, "description": 1, "keywords": 1}
+	if len(queryEmbedding) > 0 {
+		projection["embedding"] = 1
+	}
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sections []models.LegalSection
+	if err := cursor.All(ctx, &sections); err != nil {
+		return nil, err
+	}
+
+	ranked := rankSections(queryTerms, queryEmbedding, sections, limit)
+	if len(ranked) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(ranked))
+	for i, candidate := range ranked {
+		ids[i] = candidate.Section.ID
+	}
+	cursor, err = collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var full []models.LegalSection
+	if err := cursor.All(ctx, &full); err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]models.LegalSection, len(full))
+	for _, section := range full {
+		byID[section.ID] = section
+	}
+
+	candidates := make([]SectionCandidate, 0, len(ranked))
+	for _, candidate := range ranked {
+		// A section deleted between the two reads is dropped.
+		if section, ok := byID[candidate.Section.ID]; ok {
+			candidates = append(candidates, SectionCandidate{Section: section, Score: candidate.Score})
+		}
+	}
+	return candidates, nil
+}
+
+// rankSections scores sections against the query and returns the best
+// limit of them, highest score first. Sections that score nothing are
+// left out. The returned sections carry no embedding.
+func rankSections(queryTerms map[string]bool, queryEmbedding []float32, sections []models.LegalSection, limit int) []SectionCandidate {
+	var candidates []SectionCandidate
+	for _, section := range sections {
+		score := keywordScore(queryTerms, section)
+		if len(queryEmbedding) > 0 && len(section.Embedding) > 0 {
+			similarity := cosineSimilarity(queryEmbedding, section.Embedding)
+			score = keywordWeight*score + embeddingWeight*math.Max(similarity, 0)
+		}
+		if score <= 0 {
+			continue
+		}
+		section.Embedding = nil
+		candidates = append(candidates, SectionCandidate{Section: section, Score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// RetrieveCaseLaws finds case laws that interpret the candidate sections,
+// ranked by the scores of the sections they cite plus keyword overlap with
+// the incident.
+func (s *LegalService) RetrieveCaseLaws(ctx context.Context, text string, sections []SectionCandidate, limit int) ([]CaseLawCandidate, error) {
+	if len(sections) == 0 {
+		return nil, nil
+	}
+
+	sectionScores := make(map[string]float64)
+	var numbers []string
+	for _, candidate := range sections {
+		for _, number := range s.citedNumbers(candidate.Section) {
+			if _, seen := sectionScores[number]; !seen {
+				numbers = append(numbers, number)
+			}
+			sectionScores[number] = math.Max(sectionScores[number], candidate.Score)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+
+	collection := database.GetCollection(s.caseLawsCollection)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"full_text": 0})
+	cursor, err := collection.Find(ctx, bson.M{"sections": bson.M{"$in": numbers}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var caseLaws []models.CaseLawRecord
+	if err := cursor.All(ctx, &caseLaws); err != nil {
+		return nil, err
+	}
+
+	queryTerms := termSet(text)
+	var candidates []CaseLawCandidate
+	for _, caseLaw := range caseLaws {
+		var sectionScore float64
+		for _, number := range caseLaw.Sections {
+			sectionScore = math.Max(sectionScore, sectionScores[number])
+		}
+		documentTerms := termSet(strings.Join(append([]string{caseLaw.Title, caseLaw.Summary}, caseLaw.LegalIssues...), " "))
+		score := 0.7*sectionScore + 0.3*overlap(queryTerms, documentTerms)
+		candidates = append(candidates, CaseLawCandidate{CaseLaw: caseLaw, Score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// citedNumbers returns the bare section numbers case laws use to cite
+// section. Case laws cite the codes the 2024 reform repealed (see
+// citationActOrder), so a BNS, BNSS or BSA section is looked up by its
+// counterparts in the act it replaced; BNS 318(4) is cited as 420.
+func (s *LegalService) citedNumbers(section models.LegalSection) []string {
+	if _, repealed := successorActs[section.Act]; repealed || counterpartAct(section.Act) == "" {
+		return []string{section.Section}
+	}
+
+	var numbers []string
+	for _, ref := range s.EquivalentSections(section.Act, section.Section) {
+		numbers = append(numbers, ref.Section)
+		if base := baseSection(ref.Section); base != ref.Section {
+			numbers = append(numbers, base)
+		}
+	}
+	return numbers
+}
+
+// IndexSectionEmbeddings computes embeddings for sections that do not have
+// one yet and stores them on the section documents.
+func (s *LegalService) IndexSectionEmbeddings(ctx context.Context, embedder Embedder) (int, error) {
+	collection := database.GetCollection(s.sectionsCollection)
+
+	opts := options.Find().SetProjection(bson.M{"act": 1, "section": 1, "title": 1, "description": 1, "keywords": 1})
+	cursor, err := collection.Find(ctx, bson.M{"embedding": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	var sections []models.LegalSection
+	if err := cursor.All(ctx, &sections); err != nil {
+		return 0, err
+	}
+
+	const batchSize = 32
+	indexed := 0
+	for start := 0; start < len(sections); start += batchSize {
+		end := start + batchSize
+		if end > len(sections) {
+			end = len(sections)
+		}
+		batch := sections[start:end]
+
+		texts := make([]string, len(batch))
+		for i, section := range batch {
+			texts[i] = sectionEmbeddingText(section)
+		}
+
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return indexed, err
+		}
+		if len(vectors) != len(batch) {
+			return indexed, fmt.Errorf("embedder returned %d vectors for %d sections", len(vectors), len(batch))
+		}
+
+		for i, section := range batch {
+			_, err := collection.UpdateOne(ctx,
+				bson.M{"_id": section.ID},
+				bson.M{"$set": bson.M{"embedding": vectors[i]}},
+			)
+			if err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+	}
+	return indexed, nil
+}
+
+func sectionEmbeddingText(section models.LegalSection) string {
+	return strings.Join([]string{
+		section.Act + " Section " + section.Section,
+		section.Title,
+		section.Description,
+		strings.Join(section.Keywords, ", "),
+	}, "\n")
+}
+
+// keywordScore weights matches on a section's curated keywords above
+// matches in its title and description.
+func keywordScore(queryTerms map[string]bool, section models.LegalSection) float64 {
+	if len(queryTerms) == 0 {
+		return 0
+	}
+	keywordTerms := termSet(strings.Join(section.Keywords, " "))
+	titleTerms := termSet(section.Title)
+	descriptionTerms := termSet(section.Description)
+
+	score := 0.5*overlap(queryTerms, keywordTerms) +
+		0.3*overlap(queryTerms, titleTerms) +
+		0.2*overlap(queryTerms, descriptionTerms)
+	return math.Min(score, 1)
+}
+
+// overlap returns the fraction of document terms present in the query,
+// dampened so long documents are not penalised too heavily.
+func overlap(queryTerms, documentTerms map[string]bool) float64 {
+	if len(queryTerms) == 0 || len(documentTerms) == 0 {
+		return 0
+	}
+	matches := 0
+	for term := range documentTerms {
+		if queryTerms[term] {
+			matches++
+		}
+	}
+	return math.Min(float64(matches)/math.Sqrt(float64(len(documentTerms))), 1)
+}
+
+// termSet returns the distinct terms of text, analysed the same way as the
+// legal search index.
+func termSet(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, term := range search.Analyze(text) {
+		terms[term] = true
+	}
+	return terms
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
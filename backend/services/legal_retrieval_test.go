@@ -0,0 +1,54 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+)
+
+func TestRankSections(t *testing.T) {
+	sections := []models.LegalSection{
+		{Section: "303", Title: "Theft", Description: "Whoever dishonestly takes movable property.", Keywords: []string{"theft", "stolen", "property"}, Embedding: []float32{1, 0}},
+		{Section: "115", Title: "Voluntarily causing hurt", Description: "Whoever causes bodily pain.", Keywords: []string{"hurt", "injury", "assault"}, Embedding: []float32{0, 1}},
+		{Section: "318", Title: "Cheating", Description: "Whoever deceives any person.", Keywords: []string{"cheating", "fraud"}},
+	}
+	numbers := func(candidates []SectionCandidate) []string {
+		var got []string
+		for _, candidate := range candidates {
+			got = append(got, candidate.Section.Section)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		embedding []float32
+		limit     int
+		want      []string
+	}{
+		{"keyword match", "my phone was stolen, theft of property", nil, 0, []string{"303"}},
+		{"no match", "the weather was pleasant", nil, 0, nil},
+		{"best first", "fraud and theft, property stolen", nil, 0, []string{"303", "318"}},
+		{"limit", "fraud and theft, property stolen", nil, 1, []string{"303"}},
+		{"embedding ranks without keywords", "the weather was pleasant", []float32{0.1, 1}, 0, []string{"115", "303"}},
+		{"opposite embedding adds nothing", "the weather was pleasant", []float32{-1, 0.5}, 0, []string{"115"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankSections(termSet(tt.query), tt.embedding, sections, tt.limit)
+			if numbers := numbers(got); !reflect.DeepEqual(numbers, tt.want) {
+				t.Errorf("rankSections() = %q, want %q", numbers, tt.want)
+			}
+			for _, candidate := range got {
+				if candidate.Section.Embedding != nil {
+					t.Errorf("section %s kept its embedding", candidate.Section.Section)
+				}
+				if candidate.Score <= 0 || candidate.Score > 1 {
+					t.Errorf("section %s score = %v, want in (0, 1]", candidate.Section.Section, candidate.Score)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"legalassist-ai-backend/database"
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/search"
+	"legalassist-ai-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrInvalidCursor is returned when a search cursor is malformed or was
+	// issued for a different query.
+	ErrInvalidCursor = errors.New("invalid search cursor")
+	// ErrInvalidSearchType is returned for a result type filter that is not
+	// section, case_law or judgment.
+	ErrInvalidSearchType = errors.New("invalid search result type")
+)
+
+// searchIndexTTL bounds how stale a search index can get when the legal
+// collections are changed by another process, such as the seed command.
+// Changes made through this service rebuild it on the next search.
+const searchIndexTTL = 5 * time.Minute
+
+// Search result types, in the order ties between them are broken.
+const (
+	SearchTypeSection  = "section"
+	SearchTypeCaseLaw  = "case_law"
+	SearchTypeJudgment = "judgment"
+)
+
+var searchTypeRank = map[string]int{
+	SearchTypeSection:  0,
+	SearchTypeCaseLaw:  1,
+	SearchTypeJudgment: 2,
+}
+
+// actAbbreviations are indexed with each section so "IPC 420" finds it.
+var actAbbreviations = map[string]string{
+	models.ActIPC:  "IPC",
+	models.ActBNS:  "BNS",
+	models.ActCrPC: "CrPC",
+	models.ActBNSS: "BNSS",
+	models.ActIEA:  "IEA Evidence Act",
+	models.ActBSA:  "BSA",
+}
+
+// SearchQuery describes a federated search. Filters that do not apply to a
+// record type exclude it: an act filter only matches sections and a court
+// filter never matches sections.
+type SearchQuery struct {
+	Query    string
+	Types    []string
+	Category string
+	Act      string
+	Court    string
+	Cursor   string
+	Limit    int
+}
+
+// SearchResponse is one page of the federated result stream. Next is the
+// cursor for the following page and is empty on the last page.
+type SearchResponse struct {
+	Query  string                  `json:"query"`
+	Data   []SearchResult          `json:"data"`
+	Total  int                     `json:"total"`
+	Limit  int                     `json:"limit"`
+	Next   string                  `json:"next"`
+	Facets map[string][]FacetCount `json:"facets"`
+	Errors []SearchSourceError     `json:"errors"`
+}
+
+// SearchResult is a section, case law or judgment matched by a search.
+// Highlights maps field names to snippets with the matched words wrapped in
+// <mark> tags.
+type SearchResult struct {
+	Type       string                   `json:"type"`
+	ID         string                   `json:"id"`
+	Score      float64                  `json:"score"`
+	Highlights map[string]string        `json:"highlights,omitempty"`
+	Section    *models.LegalSection     `json:"section,omitempty"`
+	CaseLaw    *models.CaseLawRecord    `json:"case_law,omitempty"`
+	Judgment   *models.LandmarkJudgment `json:"judgment,omitempty"`
+}
+
+// FacetCount is how many results share a facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchSourceError reports a collection that could not be searched. The
+// other sources are still returned.
+type SearchSourceError struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// searchEntry is an indexed record and the values it is filtered and
+// faceted on.
+type searchEntry struct {
+	result   SearchResult
+	category string
+	act      string
+	court    string
+}
+
+type sourceIndex struct {
+	index   *search.Index
+	entries map[string]searchEntry
+	builtAt time.Time
+}
+
+// searchSource loads one collection into a search index.
+type searchSource struct {
+	name     string
+	resultOf string
+	build    func(s *LegalService, ctx context.Context) (*sourceIndex, error)
+}
+
+var searchSources = []searchSource{
+	{name: "sections", resultOf: SearchTypeSection, build: (*LegalService).buildSectionIndex},
+	{name: "case_laws", resultOf: SearchTypeCaseLaw, build: (*LegalService).buildCaseLawIndex},
+	{name: "judgments", resultOf: SearchTypeJudgment, build: (*LegalService).buildJudgmentIndex},
+}
+
+// The indexes are shared by every LegalService so they are built once per
+// process.
+var searchCache struct {
+	sync.Mutex
+	sources map[string]*sourceIndex
+}
+
+// invalidateSearchIndex makes the next search rebuild the indexes.
+func invalidateSearchIndex() {
+	searchCache.Lock()
+	searchCache.sources = nil
+	searchCache.Unlock()
+}
+
+// searchCursor is the position after the last result of a page, by its
+// rounded score, type and ID. Hash ties the cursor to the query and filters
+// it was issued for.
+type searchCursor struct {
+	Score float64 `json:"s"`
+	Type  string  `json:"t"`
+	ID    string  `json:"i"`
+	Hash  string  `json:"h"`
+}
+
+// Search runs a ranked full-text search across sections, case laws and
+// judgments and returns one page of a single stream ordered by score. An
+// empty query lists every record. A source that fails is reported in Errors;
+// Search only fails when every source does.
+func (s *LegalService) Search(ctx context.Context, q SearchQuery) (*SearchResponse, error) {
+	_, limit := utils.Paginate(1, q.Limit)
+	if canonical, err := NormalizeAct(q.Act); err == nil {
+		q.Act = canonical
+	}
+	for _, resultType := range q.Types {
+		if _, ok := searchTypeRank[resultType]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSearchType, resultType)
+		}
+	}
+	hash := q.hash()
+
+	var after *searchCursor
+	if q.Cursor != "" {
+		cursor, err := decodeSearchCursor(q.Cursor)
+		if err != nil || cursor.Hash != hash {
+			return nil, ErrInvalidCursor
+		}
+		after = cursor
+	}
+
+	response := &SearchResponse{
+		Query:  q.Query,
+		Data:   []SearchResult{},
+		Limit:  limit,
+		Errors: []SearchSourceError{},
+	}
+
+	var matches []searchEntry
+	var lastErr error
+	for _, source := range searchSources {
+		if len(q.Types) > 0 && !containsString(q.Types, source.resultOf) {
+			continue
+		}
+		index, err := s.sourceIndex(ctx, source)
+		if err != nil {
+			lastErr = err
+			response.Errors = append(response.Errors, SearchSourceError{Source: source.name, Error: err.Error()})
+			continue
+		}
+		for _, hit := range searchHits(index.index, q.Query) {
+			entry := index.entries[hit.ID]
+			if !q.matches(entry) {
+				continue
+			}
+			entry.result.Score = roundScore(hit.Score)
+			entry.result.Highlights = hit.Highlights
+			matches = append(matches, entry)
+		}
+	}
+	if lastErr != nil && len(response.Errors) == countSearchedSources(q.Types) {
+		return nil, lastErr
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return resultBefore(matches[i].result, matches[j].result)
+	})
+	response.Total = len(matches)
+	response.Facets = facetCounts(matches)
+
+	start := 0
+	if after != nil {
+		position := SearchResult{Score: roundScore(after.Score), Type: after.Type, ID: after.ID}
+		start = sort.Search(len(matches), func(i int) bool {
+			return resultBefore(position, matches[i].result)
+		})
+	}
+	end := start + limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+	for _, entry := range matches[start:end] {
+		response.Data = append(response.Data, entry.result)
+	}
+
+	if end < len(matches) {
+		last := matches[end-1].result
+		response.Next = encodeSearchCursor(searchCursor{Score: last.Score, Type: last.Type, ID: last.ID, Hash: hash})
+	}
+	return response, nil
+}
+
+// roundScore rounds a search score to six decimal places. A score is a sum
+// of floating-point terms, so the same record can score differently in the
+// last bit once the index is rebuilt; results are ordered and paged on the
+// rounded score so a cursor still finds its place.
+func roundScore(score float64) float64 {
+	return math.Round(score*1e6) / 1e6
+}
+
+// resultBefore orders results by score, then type, then ID so the stream
+// has a total order a cursor can resume from.
+func resultBefore(a, b SearchResult) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Type != b.Type {
+		return searchTypeRank[a.Type] < searchTypeRank[b.Type]
+	}
+	return a.ID < b.ID
+}
+
+func (q SearchQuery) matches(entry searchEntry) bool {
+	if q.Category != "" && q.Category != "all" && entry.category != q.Category {
+		return false
+	}
+	if q.Act != "" && entry.act != q.Act {
+		return false
+	}
+	if q.Court != "" && !strings.EqualFold(entry.court, q.Court) {
+		return false
+	}
+	return true
+}
+
+func (q SearchQuery) hash() string {
+	types := append([]string(nil), q.Types...)
+	sort.Strings(types)
+	key := strings.Join([]string{q.Query, strings.Join(types, ","), q.Category, q.Act, strings.ToLower(q.Court)}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+func countSearchedSources(types []string) int {
+	if len(types) == 0 {
+		return len(searchSources)
+	}
+	count := 0
+	for _, source := range searchSources {
+		if containsString(types, source.resultOf) {
+			count++
+		}
+	}
+	return count
+}
+
+func encodeSearchCursor(cursor searchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSearchCursor(token string) (*searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// facetCounts counts the matching results by type, category, act and
+// court, most common value first.
+func facetCounts(matches []searchEntry) map[string][]FacetCount {
+	counts := map[string]map[string]int{
+		"type":     {},
+		"category": {},
+		"act":      {},
+		"court":    {},
+	}
+	for _, entry := range matches {
+		counts["type"][entry.result.Type]++
+		if entry.category != "" {
+			counts["category"][entry.category]++
+		}
+		if entry.act != "" {
+			counts["act"][entry.act]++
+		}
+		if entry.court != "" {
+			counts["court"][entry.court]++
+		}
+	}
+
+	facets := make(map[string][]FacetCount, len(counts))
+	for name, values := range counts {
+		facet := []FacetCount{}
+		for value, count := range values {
+			facet = append(facet, FacetCount{Value: value, Count: count})
+		}
+		sort.Slice(facet, func(i, j int) bool {
+			if facet[i].Count != facet[j].Count {
+				return facet[i].Count > facet[j].Count
+			}
+			return facet[i].Value < facet[j].Value
+		})
+		facets[name] = facet
+	}
+	return facets
+}
+
+func searchHits(index *search.Index, query string) []search.Hit {
+	if strings.TrimSpace(query) == "" {
+		return index.All()
+	}
+	return index.Search(query)
+}
+
+// sourceIndex returns the cached index for a source, rebuilding it when the
+// cache has been invalidated or is older than searchIndexTTL. Failed builds
+// are not cached.
+func (s *LegalService) sourceIndex(ctx context.Context, source searchSource) (*sourceIndex, error) {
+	searchCache.Lock()
+	defer searchCache.Unlock()
+
+	if index := searchCache.sources[source.name]; index != nil && time.Since(index.builtAt) < searchIndexTTL {
+		return index, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	index, err := source.build(s, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if searchCache.sources == nil {
+		searchCache.sources = make(map[string]*sourceIndex)
+	}
+	searchCache.sources[source.name] = index
+	return index, nil
+}
+
+func (s *LegalService) buildSectionIndex(ctx context.Context) (*sourceIndex, error) {
+	var sections []models.LegalSection
+	sortSpec := bson.D{{Key: "act", Value: 1}, {Key: "section", Value: 1}}
+	if err := s.loadAll(ctx, s.sectionsCollection, sortSpec, &sections); err != nil {
+		return nil, err
+	}
+
+	index := newSourceIndex(
+		search.Field{Name: "section", Weight: 3},
+		search.Field{Name: "title", Weight: 2},
+		search.Field{Name: "keywords", Weight: 2},
+		search.Field{Name: "description", Weight: 1},
+		search.Field{Name: "act", Weight: 0.5},
+	)
+	for i := range sections {
+		section := &sections[i]
+		id := section.ID.Hex()
+		index.entries[id] = searchEntry{
+			result:   SearchResult{Type: SearchTypeSection, ID: id, Section: section},
+			category: section.Category,
+			act:      section.Act,
+		}
+		index.index.Add(search.Document{ID: id, Fields: map[string]string{
+			"section":     section.Section,
+			"title":       section.Title,
+			"keywords":    strings.Join(section.Keywords, ", "),
+			"description": section.Description,
+			"act":         section.Act + " " + actAbbreviations[section.Act],
+		}})
+	}
+	return index, nil
+}
+
+func (s *LegalService) buildCaseLawIndex(ctx context.Context) (*sourceIndex, error) {
+	var caseLaws []models.CaseLawRecord
+	if err := s.loadAll(ctx, s.caseLawsCollection, bson.D{{Key: "year", Value: -1}}, &caseLaws); err != nil {
+		return nil, err
+	}
+
+	index := newSourceIndex(
+		search.Field{Name: "title", Weight: 2},
+		search.Field{Name: "sections", Weight: 2},
+		search.Field{Name: "legal_issues", Weight: 1.5},
+		search.Field{Name: "summary", Weight: 1},
+		search.Field{Name: "key_points", Weight: 1},
+	)
+	for i := range caseLaws {
+		caseLaw := &caseLaws[i]
+		id := caseLaw.ID.Hex()
+		index.entries[id] = searchEntry{
+			result:   SearchResult{Type: SearchTypeCaseLaw, ID: id, CaseLaw: caseLaw},
+			category: caseLaw.Category,
+			court:    caseLaw.Court,
+		}
+		index.index.Add(search.Document{ID: id, Fields: map[string]string{
+			"title":        caseLaw.Title,
+			"sections":     strings.Join(caseLaw.Sections, ", "),
+			"legal_issues": strings.Join(caseLaw.LegalIssues, "; "),
+			"summary":      caseLaw.Summary,
+			"key_points":   strings.Join(caseLaw.KeyPoints, "; "),
+		}})
+	}
+	return index, nil
+}
+
+func (s *LegalService) buildJudgmentIndex(ctx context.Context) (*sourceIndex, error) {
+	var judgments []models.LandmarkJudgment
+	if err := s.loadAll(ctx, s.judgmentsCollection, bson.D{{Key: "year", Value: -1}}, &judgments); err != nil {
+		return nil, err
+	}
+
+	index := newSourceIndex(
+		search.Field{Name: "case", Weight: 2},
+		search.Field{Name: "legal_doctrine", Weight: 1.5},
+		search.Field{Name: "significance", Weight: 1},
+		search.Field{Name: "impact", Weight: 1},
+		search.Field{Name: "key_principles", Weight: 1},
+	)
+	for i := range judgments {
+		judgment := &judgments[i]
+		id := judgment.ID.Hex()
+		index.entries[id] = searchEntry{
+			result: SearchResult{Type: SearchTypeJudgment, ID: id, Judgment: judgment},
+			court:  judgment.Court,
+		}
+		index.index.Add(search.Document{ID: id, Fields: map[string]string{
+			"case":           judgment.Case,
+			"legal_doctrine": judgment.LegalDoctrine,
+			"significance":   judgment.Significance,
+			"impact":         judgment.Impact,
+			"key_principles": strings.Join(judgment.KeyPrinciples, "; "),
+		}})
+	}
+	return index, nil
+}
+
+func newSourceIndex(fields ...search.Field) *sourceIndex {
+	return &sourceIndex{
+		index:   search.NewIndex(fields...),
+		entries: make(map[string]searchEntry),
+		builtAt: time.Now(),
+	}
+}
+
+// loadAll decodes a whole collection, without embeddings or full texts.
+func (s *LegalService) loadAll(ctx context.Context, collectionName string, sortSpec bson.D, results interface{}) error {
+	opts := options.Find().
+		SetSort(sortSpec).
+		SetCollation(numericCollation).
+		SetProjection(bson.M{"embedding": 0, "full_text": 0})
+
+	cursor, err := database.GetCollection(collectionName).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
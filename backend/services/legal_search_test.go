@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+	"legalassist-ai-backend/search"
+)
+
+// testSearchIndexes replaces the shared search indexes with small ones for
+// the length of a test. Many records share text so their scores tie.
+func testSearchIndexes(t *testing.T) {
+	t.Helper()
+	sections := newSourceIndex(search.Field{Name: "title", Weight: 2}, search.Field{Name: "description", Weight: 1})
+	for i, title := range []string{"Theft", "Theft in a dwelling house", "Theft by a servant", "Robbery", "Dacoity", "Extortion", "Snatching", "Cheating", "Criminal breach of trust", "Receiving stolen property"} {
+		id := fmt.Sprintf("s%02d", i)
+		sections.entries[id] = searchEntry{result: SearchResult{Type: SearchTypeSection, ID: id}, category: "property", act: models.ActBNS}
+		sections.index.Add(search.Document{ID: id, Fields: map[string]string{"title": title, "description": "Whoever commits theft of movable property shall be punished"}})
+	}
+	caseLaws := newSourceIndex(search.Field{Name: "title", Weight: 2}, search.Field{Name: "summary", Weight: 1})
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("c%02d", i)
+		caseLaws.entries[id] = searchEntry{result: SearchResult{Type: SearchTypeCaseLaw, ID: id}, category: "property", court: "Supreme Court"}
+		caseLaws.index.Add(search.Document{ID: id, Fields: map[string]string{"title": fmt.Sprintf("State v. Accused %d", i), "summary": "Conviction for theft of stolen property upheld"}})
+	}
+	judgments := newSourceIndex(search.Field{Name: "case", Weight: 2}, search.Field{Name: "significance", Weight: 1})
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("j%02d", i)
+		judgments.entries[id] = searchEntry{result: SearchResult{Type: SearchTypeJudgment, ID: id}, court: "Supreme Court"}
+		judgments.index.Add(search.Document{ID: id, Fields: map[string]string{"case": fmt.Sprintf("Landmark %d", i), "significance": "Defined dishonest intention in theft"}})
+	}
+
+	searchCache.Lock()
+	searchCache.sources = map[string]*sourceIndex{"sections": sections, "case_laws": caseLaws, "judgments": judgments}
+	searchCache.Unlock()
+	t.Cleanup(invalidateSearchIndex)
+}
+
+func TestSearchPages(t *testing.T) {
+	testSearchIndexes(t)
+	service := NewLegalService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+	}{
+		{"ranked", SearchQuery{Query: "theft stolen property"}},
+		{"tied scores", SearchQuery{Query: "theft", Types: []string{SearchTypeCaseLaw, SearchTypeJudgment}}},
+		{"listing", SearchQuery{}},
+		{"filtered", SearchQuery{Query: "property", Court: "supreme court"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := tt.query
+			all.Limit = 100
+			full, err := service.Search(ctx, all)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			var want []string
+			for _, result := range full.Data {
+				want = append(want, result.ID)
+			}
+
+			var got []string
+			page := tt.query
+			page.Limit = 3
+			for pages := 0; ; pages++ {
+				if pages > len(want) {
+					t.Fatalf("paging did not end after %d pages", pages)
+				}
+				response, err := service.Search(ctx, page)
+				if err != nil {
+					t.Fatalf("Search() page %d error = %v", pages+1, err)
+				}
+				for _, result := range response.Data {
+					got = append(got, result.ID)
+				}
+				if response.Next == "" {
+					break
+				}
+				page.Cursor = response.Next
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("paged results = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	testSearchIndexes(t)
+	service := NewLegalService()
+	ctx := context.Background()
+	query := SearchQuery{Query: "theft stolen property", Limit: 4}
+
+	first, err := service.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	cursor, err := decodeSearchCursor(first.Next)
+	if err != nil {
+		t.Fatalf("decodeSearchCursor() error = %v", err)
+	}
+	// A score that drifted in the last bit still resumes in the same place.
+	drifted := *cursor
+	drifted.Score = math.Nextafter(cursor.Score, math.Inf(1))
+
+	tests := []struct {
+		name    string
+		query   SearchQuery
+		cursor  string
+		wantErr error
+	}{
+		{"issued cursor", query, first.Next, nil},
+		{"score drifted", query, encodeSearchCursor(drifted), nil},
+		{"other query", SearchQuery{Query: "robbery", Limit: 4}, first.Next, ErrInvalidCursor},
+		{"malformed", query, "not a cursor", ErrInvalidCursor},
+	}
+	var want []SearchResult
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Cursor = tt.cursor
+			response, err := service.Search(ctx, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Search() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if want == nil {
+				want = response.Data
+			}
+			if !reflect.DeepEqual(response.Data, want) || response.Data[0].ID == first.Data[0].ID {
+				t.Errorf("second page = %+v, want %+v", response.Data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/config"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrLLMDisabled is returned by providers that do not talk to a model.
+// Callers treat it as a signal to use their deterministic fallback.
+var ErrLLMDisabled = errors.New("llm provider is disabled")
+
+const (
+	defaultOpenAIModel          = openai.GPT3Dot5Turbo
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultLocalBaseURL         = "http://localhost:11434/v1"
+	defaultLocalModel           = "llama3"
+	defaultLocalEmbeddingModel  = "nomic-embed-text"
+	defaultLLMTimeout           = 30 * time.Second
+)
+
+// CompletionRequest is a single prompt sent to an LLMProvider.
+type CompletionRequest struct {
+	System      string
+	Prompt      string
+	Model       string // overrides the provider's default model when set
+	Temperature float32
+	MaxTokens   int
+	JSON        bool // ask the backend to return a JSON object
+}
+
+// LLMProvider is the interface AIService uses to reach a language model.
+type LLMProvider interface {
+	Name() string
+	Model() string
+	Complete(ctx context.Context, req CompletionRequest) (string, error)
+}
+
+// Embedder is implemented by providers that can turn text into embedding
+// vectors for similarity search.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewLLMProvider builds the provider selected by cfg.LLMProvider. When no
+// provider is configured, an OpenAI API key selects the hosted backend and
+// its absence selects the mock.
+func NewLLMProvider(cfg *config.Config) LLMProvider {
+	name := strings.ToLower(cfg.LLMProvider)
+	if name == "" {
+		name = "mock"
+		if cfg.OpenAIAPIKey != "" {
+			name = "openai"
+		}
+	}
+
+	switch name {
+	case "openai":
+		return NewOpenAIProvider(cfg.OpenAIAPIKey, cfg.LLMBaseURL, cfg.LLMModel, cfg.LLMEmbeddingModel, cfg.LLMTimeout)
+	case "local":
+		return NewLocalLLMProvider(cfg.LLMBaseURL, cfg.LLMModel, cfg.LLMEmbeddingModel, cfg.LLMTimeout)
+	case "mock":
+		return NewMockLLMProvider()
+	default:
+		log.Printf("Unknown LLM provider %q, falling back to mock", cfg.LLMProvider)
+		return NewMockLLMProvider()
+	}
+}
+
+// OpenAIProvider talks to any endpoint implementing the OpenAI chat
+// completions API: api.openai.com, Azure-style gateways, vLLM, llama.cpp,
+// Ollama and similar self-hosted servers.
+type OpenAIProvider struct {
+	name           string
+	client         *openai.Client
+	httpClient     *http.Client
+	apiKey         string
+	baseURL        string
+	model          string
+	embeddingModel string
+	timeout        time.Duration
+}
+
+// NewOpenAIProvider creates a provider for an OpenAI-compatible endpoint.
+// An empty baseURL targets api.openai.com.
+func NewOpenAIProvider(apiKey, baseURL, model, embeddingModel string, timeout time.Duration) *OpenAIProvider {
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	if embeddingModel == "" {
+		embeddingModel = defaultOpenAIEmbeddingModel
+	}
+	return newOpenAICompatibleProvider("openai", apiKey, baseURL, model, embeddingModel, timeout)
+}
+
+// NewLocalLLMProvider creates a provider for a self-hosted OpenAI-compatible
+// server, such as a model running in the station data centre. No API key is
+// sent and the base URL defaults to a local Ollama instance.
+func NewLocalLLMProvider(baseURL, model, embeddingModel string, timeout time.Duration) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = defaultLocalBaseURL
+	}
+	if model == "" {
+		model = defaultLocalModel
+	}
+	if embeddingModel == "" {
+		embeddingModel = defaultLocalEmbeddingModel
+	}
+	return newOpenAICompatibleProvider("local", "", baseURL, model, embeddingModel, timeout)
+}
+
+func newOpenAICompatibleProvider(name, apiKey, baseURL, model, embeddingModel string, timeout time.Duration) *OpenAIProvider {
+	if timeout <= 0 {
+		timeout = defaultLLMTimeout
+	}
+
+	clientConfig := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		clientConfig.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+	httpClient := &http.Client{Timeout: timeout}
+	clientConfig.HTTPClient = httpClient
+
+	return &OpenAIProvider{
+		name:           name,
+		client:         openai.NewClientWithConfig(clientConfig),
+		httpClient:     httpClient,
+		apiKey:         apiKey,
+		baseURL:        clientConfig.BaseURL,
+		model:          model,
+		embeddingModel: embeddingModel,
+		timeout:        timeout,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return p.name
+}
+
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	model := req.Model
+	if model == "" {
+		model = p.model
+	}
+
+	var messages []openai.ChatCompletionMessage
+	if req.System != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: req.System,
+		})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: req.Prompt,
+	})
+
+	chatReq := openai.ChatCompletionRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+	if req.JSON {
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		return "", fmt.Errorf("%s completion failed: %w", p.name, err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no choices", p.name)
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Embed calls the /embeddings endpoint directly so the request shape does
+// not depend on the client library's model enumeration.
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model": p.embeddingModel,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s embeddings failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s embeddings returned status %d", p.name, resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s embeddings: %w", p.name, err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("%s returned %d embeddings for %d inputs", p.name, len(result.Data), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("%s returned embedding index %d out of range", p.name, item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+	return embeddings, nil
+}
+
+// MockLLMProvider never reaches a model. It lets the service run without any
+// LLM configured, in which case AIService uses its keyword analyzer. Tests
+// and demos can set Respond to return canned, deterministic output.
+type MockLLMProvider struct {
+	Respond func(req CompletionRequest) (string, error)
+}
+
+func NewMockLLMProvider() *MockLLMProvider {
+	return &MockLLMProvider{}
+}
+
+func (p *MockLLMProvider) Name() string {
+	return "mock"
+}
+
+func (p *MockLLMProvider) Model() string {
+	return "mock"
+}
+
+func (p *MockLLMProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if p.Respond == nil {
+		return "", ErrLLMDisabled
+	}
+	return p.Respond(req)
+}
@@ -0,0 +1,673 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"legalassist-ai-backend/models"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/math/fixed"
+)
+
+// PDF page layout, in millimetres and points.
+const (
+	pdfMargin       = 20.0
+	pdfTitleSize    = 14.0
+	pdfTextSize     = 10.5
+	pdfLineHeight   = 5.5
+	pdfQRSize       = 32.0
+	pdfColumnGap    = 6.0
+	pdfFooterSize   = 8.0
+	pdfLatinFamily  = "latin"
+	pdfFallbackFont = "helvetica"
+)
+
+// ErrPDFScriptUnsupported is returned when a document contains text in a
+// script for which no font is installed.
+var ErrPDFScriptUnsupported = errors.New("no font is installed to print this document")
+
+// pdfFonts lists the font used for each script, by file name, in order of
+// preference. Digits, spaces and punctuation are set in the font of the
+// text around them when it has them. Shaped scripts are laid out with
+// HarfBuzz, which forms Indic conjuncts, reorders vowel signs and joins
+// Arabic letters; the rest are embedded as PDF text.
+var pdfFonts = []struct {
+	family string
+	script *unicode.RangeTable
+	files  []string
+	shaped bool
+}{
+	{pdfLatinFamily, unicode.Latin, []string{"NotoSans-Regular.ttf", "DejaVuSans.ttf"}, false},
+	{"Devanagari", unicode.Devanagari, []string{"NotoSansDevanagari-Regular.ttf"}, true},
+	{"Bengali", unicode.Bengali, []string{"NotoSansBengali-Regular.ttf"}, true},
+	{"Tamil", unicode.Tamil, []string{"NotoSansTamil-Regular.ttf"}, true},
+	{"Telugu", unicode.Telugu, []string{"NotoSansTelugu-Regular.ttf"}, true},
+	{"Gujarati", unicode.Gujarati, []string{"NotoSansGujarati-Regular.ttf"}, true},
+	{"Kannada", unicode.Kannada, []string{"NotoSansKannada-Regular.ttf"}, true},
+	{"Malayalam", unicode.Malayalam, []string{"NotoSansMalayalam-Regular.ttf"}, true},
+	{"Gurmukhi", unicode.Gurmukhi, []string{"NotoSansGurmukhi-Regular.ttf"}, true},
+	{"Oriya", unicode.Oriya, []string{"NotoSansOriya-Regular.ttf"}, true},
+	{"Arabic", unicode.Arabic, []string{"NotoNaskhArabic-Regular.ttf", "NotoSansArabic-Regular.ttf"}, true},
+}
+
+// PDFDocument is a document to print. When Sections is set, the document
+// is bilingual and printed as a two-column table of its sections, each
+// English paragraph beside its translation, instead of Text. Signatures is
+// printed in small type after the text, then QRContent is encoded in a QR
+// code with QRCaption beneath it. Footer is printed on every page with the
+// page number.
+type PDFDocument struct {
+	Title      string
+	Text       string
+	Sections   []models.GeneratedFIRSection
+	Signatures string
+	Footer     string
+	QRContent  string
+	QRCaption  string
+}
+
+// pdfFont is a loaded font. Fonts for shaped scripts are parsed for the
+// shaper; the others are kept as files for gofpdf to embed.
+type pdfFont struct {
+	data   []byte
+	parsed *font.Font
+}
+
+// PDFRenderer prints documents as A4 PDFs. Fonts are TrueType files found
+// under fontDir. Latin text is embedded as text, subset to the characters
+// used, and falls back to the PDF core font Helvetica, which only covers
+// Western European text, when no Latin font is installed. Indian scripts
+// and Urdu are shaped and drawn as glyph outlines, so they print correctly
+// but cannot be selected or searched in the PDF. A document in a script
+// whose font is missing is refused with ErrPDFScriptUnsupported.
+type PDFRenderer struct {
+	fontDir string
+
+	loadFonts sync.Once
+	fonts     map[string]pdfFont
+}
+
+func NewPDFRenderer(fontDir string) *PDFRenderer {
+	return &PDFRenderer{fontDir: fontDir}
+}
+
+// load reads the preferred font for each script from fontDir and its
+// subdirectories.
+func (r *PDFRenderer) load() {
+	r.fonts = map[string]pdfFont{}
+
+	paths := map[string]string{}
+	if r.fontDir != "" {
+		err := filepath.WalkDir(r.fontDir, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() {
+				if _, seen := paths[entry.Name()]; !seen {
+					paths[entry.Name()] = path
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Reading PDF fonts from %s failed: %v", r.fontDir, err)
+		}
+	}
+
+	var missing []string
+	for _, entry := range pdfFonts {
+		for _, file := range entry.files {
+			path, ok := paths[file]
+			if !ok {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				log.Printf("Reading PDF font %s failed: %v", path, err)
+				continue
+			}
+			if !entry.shaped {
+				r.fonts[entry.family] = pdfFont{data: data}
+				break
+			}
+			face, err := font.ParseTTF(bytes.NewReader(data))
+			if err != nil {
+				log.Printf("Parsing PDF font %s failed: %v", path, err)
+				continue
+			}
+			r.fonts[entry.family] = pdfFont{parsed: face.Font}
+			break
+		}
+		if _, ok := r.fonts[entry.family]; !ok {
+			missing = append(missing, entry.family)
+		}
+	}
+	if len(missing) > 0 {
+		log.Printf("No PDF fonts in %q for %s; Latin text falls back to Helvetica and other scripts cannot be printed", r.fontDir, strings.Join(missing, ", "))
+	}
+}
+
+// missingScripts returns the scripts in text that have no font, in the
+// order of pdfFonts.
+func (r *PDFRenderer) missingScripts(text string) []string {
+	found := make([]bool, len(pdfFonts))
+	for _, ch := range text {
+		if ch < unicode.MaxLatin1 || !unicode.IsLetter(ch) && !unicode.IsMark(ch) {
+			continue
+		}
+		for i, entry := range pdfFonts {
+			if unicode.Is(entry.script, ch) {
+				found[i] = true
+				break
+			}
+		}
+	}
+	var names []string
+	for i, entry := range pdfFonts {
+		if _, ok := r.fonts[entry.family]; found[i] && !ok && entry.shaped {
+			names = append(names, entry.family)
+		}
+	}
+	return names
+}
+
+// textRun is a stretch of text set in one font. Right-to-left runs are
+// shaped right to left.
+type textRun struct {
+	family string
+	text   string
+	rtl    bool
+}
+
+// runs splits a line into runs by script, using the fonts that are
+// available.
+func (r *PDFRenderer) runs(line string) []textRun {
+	var runs []textRun
+	family := ""
+	for _, ch := range line {
+		if next := r.familyFor(ch); next != "" {
+			family = next
+		} else if family == "" || !r.covers(family, ch) {
+			family = r.latinFamily()
+		}
+		if n := len(runs); n > 0 && runs[n-1].family == family {
+			runs[n-1].text += string(ch)
+			continue
+		}
+		runs = append(runs, textRun{family: family, text: string(ch)})
+	}
+	return runs
+}
+
+// familyFor returns the font family for a letter or mark, or "" for
+// characters that take the font of the text around them.
+func (r *PDFRenderer) familyFor(ch rune) string {
+	if !unicode.IsLetter(ch) && !unicode.IsMark(ch) {
+		return ""
+	}
+	for _, entry := range pdfFonts {
+		if unicode.Is(entry.script, ch) {
+			if _, ok := r.fonts[entry.family]; ok {
+				return entry.family
+			}
+			break
+		}
+	}
+	return r.latinFamily()
+}
+
+// covers reports whether family's font has a glyph for ch. Embedded fonts
+// are assumed to cover the digits and punctuation they are used for.
+func (r *PDFRenderer) covers(family string, ch rune) bool {
+	parsed := r.fonts[family].parsed
+	if parsed == nil {
+		return true
+	}
+	_, ok := parsed.NominalGlyph(ch)
+	return ok
+}
+
+func (r *PDFRenderer) latinFamily() string {
+	if _, ok := r.fonts[pdfLatinFamily]; ok {
+		return pdfLatinFamily
+	}
+	return pdfFallbackFont
+}
+
+// visualRuns splits a line into font runs in the order they are drawn,
+// left to right, and reports whether the line reads right to left.
+func (r *PDFRenderer) visualRuns(line string) ([]textRun, bool) {
+	directional, rtl := bidiRuns(line)
+	var runs []textRun
+	for _, run := range directional {
+		fontRuns := r.runs(run.text)
+		if run.rtl {
+			for i, j := 0, len(fontRuns)-1; i < j; i, j = i+1, j-1 {
+				fontRuns[i], fontRuns[j] = fontRuns[j], fontRuns[i]
+			}
+			for i := range fontRuns {
+				fontRuns[i].rtl = true
+			}
+		}
+		runs = append(runs, fontRuns...)
+	}
+	return runs, rtl
+}
+
+// bidiRun is a stretch of a line in one direction.
+type bidiRun struct {
+	text string
+	rtl  bool
+}
+
+// bidiRuns splits a line into runs of one direction in visual order, left
+// to right, and reports whether the line reads right to left, which it
+// does when its first letter is Arabic script. This is the two-level case
+// of the Unicode bidirectional algorithm: Arabic-script letters read right
+// to left, other letters and digits left to right, and spaces and
+// punctuation take the direction of the text on both sides of them, or
+// the line's when those differ. Right-to-left runs are left in logical
+// order for the shaper to reverse.
+func bidiRuns(line string) ([]bidiRun, bool) {
+	chars := []rune(line)
+	dirs := make([]int, len(chars)) // 1 right to left, -1 left to right, 0 neutral
+	rtl, found := false, false
+	for i, ch := range chars {
+		switch {
+		case unicode.IsMark(ch) && i > 0:
+			dirs[i] = dirs[i-1]
+		case unicode.IsLetter(ch) && unicode.Is(unicode.Arabic, ch):
+			dirs[i] = 1
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch):
+			dirs[i] = -1
+		}
+		if dirs[i] != 0 && !found {
+			rtl, found = dirs[i] == 1, true
+		}
+	}
+
+	base := -1
+	if rtl {
+		base = 1
+	}
+	for i := 0; i < len(dirs); {
+		if dirs[i] != 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(dirs) && dirs[j] == 0 {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = dirs[i-1]
+		}
+		if j < len(dirs) {
+			after = dirs[j]
+		}
+		dir := base
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			dirs[k] = dir
+		}
+		i = j
+	}
+
+	var runs []bidiRun
+	for i, ch := range chars {
+		if n := len(runs); n > 0 && runs[n-1].rtl == (dirs[i] == 1) {
+			runs[n-1].text += string(ch)
+			continue
+		}
+		runs = append(runs, bidiRun{text: string(ch), rtl: dirs[i] == 1})
+	}
+	if rtl {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+	return runs, rtl
+}
+
+// Render lays out doc and returns the PDF.
+func (r *PDFRenderer) Render(doc PDFDocument) ([]byte, error) {
+	r.loadFonts.Do(r.load)
+
+	parts := []string{doc.Title, doc.Text, doc.Signatures, doc.Footer, doc.QRCaption}
+	for _, section := range doc.Sections {
+		parts = append(parts, section.English, section.Translated)
+	}
+	all := strings.Join(parts, "\n")
+	if missing := r.missingScripts(all); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: no font for %s text", ErrPDFScriptUnsupported, strings.Join(missing, ", "))
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	// Lines are placed one at a time, so pages are broken by hand.
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetCreator("LegalAssist-AI", false)
+	pdf.AliasNbPages("")
+
+	// Only fonts the document uses are embedded.
+	used := map[string]bool{r.latinFamily(): true}
+	for _, line := range strings.Split(all, "\n") {
+		for _, run := range r.runs(line) {
+			used[run.family] = true
+		}
+	}
+	for family := range used {
+		if f, ok := r.fonts[family]; ok && f.parsed == nil {
+			pdf.AddUTF8FontFromBytes(family, "", f.data)
+		}
+	}
+
+	layout := &pdfLayout{
+		PDFRenderer: r,
+		pdf:         pdf,
+		// Core fonts only have Windows-1252 glyphs.
+		encodeCore: pdf.UnicodeTranslatorFromDescriptor(""),
+		faces:      map[string]*font.Face{},
+	}
+	pageWidth, pageHeight := pdf.GetPageSize()
+	bodyWidth := pageWidth - 2*pdfMargin
+
+	// nextLine returns the top of a line of the given height, starting a
+	// new page when it would run into the bottom margin.
+	nextLine := func(height float64) float64 {
+		if pdf.GetY()+height > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		return pdf.GetY()
+	}
+	write := func(text string, size float64, center bool) {
+		height := lineHeight(size)
+		for _, line := range layout.wrap(text, bodyWidth, size) {
+			y := nextLine(height)
+			layout.writeLine(line, pdfMargin, y, bodyWidth, size, center)
+			pdf.SetXY(pdfMargin, y+height)
+		}
+	}
+	writeDocument := func(text string) {
+		title, body, _ := strings.Cut(strings.TrimSpace(text), "\n")
+		write(title, pdfTitleSize, true)
+		write(body, pdfTextSize, false)
+	}
+	// writeColumns prints each section as a table row, a line of each
+	// column at a time so the row can break across pages.
+	writeColumns := func(sections []models.GeneratedFIRSection) {
+		width := (bodyWidth - pdfColumnGap) / 2
+		height := lineHeight(pdfTextSize)
+		for _, section := range sections {
+			left := layout.wrap(section.English, width, pdfTextSize)
+			right := layout.wrap(section.Translated, width, pdfTextSize)
+			for i := 0; i < len(left) || i < len(right); i++ {
+				y := nextLine(height)
+				if i < len(left) {
+					layout.writeLine(left[i], pdfMargin, y, width, pdfTextSize, false)
+				}
+				if i < len(right) {
+					layout.writeLine(right[i], pdfMargin+width+pdfColumnGap, y, width, pdfTextSize, false)
+				}
+				pdf.SetXY(pdfMargin, y+height)
+			}
+			pdf.Ln(height / 2)
+		}
+	}
+
+	pdf.SetFooterFunc(func() {
+		footer := fmt.Sprintf("%s    Page %d of {nb}", doc.Footer, pdf.PageNo())
+		layout.writeLine(footer, pdfMargin, pageHeight-pdfMargin+5, bodyWidth, pdfFooterSize, true)
+	})
+
+	pdf.AddPage()
+	if len(doc.Sections) > 0 {
+		writeColumns(doc.Sections)
+	} else {
+		writeDocument(doc.Text)
+	}
+	if doc.Signatures != "" {
+		pdf.Ln(pdfLineHeight)
+		write(doc.Signatures, pdfFooterSize, false)
+	}
+
+	if doc.QRContent != "" {
+		code, err := qrcode.New(doc.QRContent, qrcode.Medium)
+		if err != nil {
+			return nil, fmt.Errorf("pdf: QR code: %w", err)
+		}
+		if pdf.GetY()+pdfQRSize+4*pdfLineHeight > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		pdf.Ln(pdfLineHeight)
+		drawQRCode(pdf, code.Bitmap(), pdfMargin, pdf.GetY(), pdfQRSize)
+		pdf.SetY(pdf.GetY() + pdfQRSize + 2)
+		write(doc.QRCaption, pdfFooterSize, false)
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// lineHeight is the height of a line of text at size points.
+func lineHeight(size float64) float64 {
+	return size * pdfLineHeight / pdfTextSize
+}
+
+// pdfLayout sets text in one PDF. Font faces are made for each document
+// because they cache glyphs and are not safe for concurrent use.
+type pdfLayout struct {
+	*PDFRenderer
+	pdf        *gofpdf.Fpdf
+	encodeCore func(string) string
+	faces      map[string]*font.Face
+	shaper     shaping.HarfbuzzShaper
+}
+
+// wrap breaks text into lines no wider than width at size, breaking at
+// spaces. A paragraph that fits is kept as it is, indentation included. A
+// word wider than width gets a line of its own and overflows it.
+func (l *pdfLayout) wrap(text string, width, size float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n") {
+		paragraph = strings.TrimRightFunc(paragraph, unicode.IsSpace)
+		if l.width(paragraph, size) <= width {
+			lines = append(lines, paragraph)
+			continue
+		}
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && l.width(line+" "+word, size) > width {
+				lines = append(lines, line)
+				line = word
+			} else if line != "" {
+				line += " " + word
+			} else {
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// width measures a line at size points.
+func (l *pdfLayout) width(line string, size float64) float64 {
+	runs, _ := l.visualRuns(line)
+	total := 0.0
+	for _, run := range runs {
+		total += l.runWidth(run, size)
+	}
+	return total
+}
+
+func (l *pdfLayout) runWidth(run textRun, size float64) float64 {
+	if l.fonts[run.family].parsed != nil {
+		return l.fromFixed(l.shape(run, size).Advance)
+	}
+	l.pdf.SetFont(run.family, "", size)
+	return l.pdf.GetStringWidth(l.encode(run))
+}
+
+// writeLine prints one line of text in the width from x, with the top of
+// the line at y, without wrapping it. A centred line is centred in the
+// width, and a line that reads right to left is aligned to its right.
+func (l *pdfLayout) writeLine(line string, x, y, width, size float64, center bool) {
+	runs, rtl := l.visualRuns(line)
+	if center || rtl {
+		lineWidth := 0.0
+		for _, run := range runs {
+			lineWidth += l.runWidth(run, size)
+		}
+		if center {
+			x += (width - lineWidth) / 2
+		} else {
+			x += width - lineWidth
+		}
+	}
+
+	// gofpdf places the baseline of a cell's text this far below its top.
+	baseline := y + lineHeight(size)/2 + 0.3*l.pdf.PointConvert(size)
+	for _, run := range runs {
+		if l.fonts[run.family].parsed != nil {
+			out := l.shape(run, size)
+			l.drawGlyphs(out, x, baseline)
+			x += l.fromFixed(out.Advance)
+			continue
+		}
+		l.pdf.SetFont(run.family, "", size)
+		text := l.encode(run)
+		l.pdf.Text(x, baseline, text)
+		x += l.pdf.GetStringWidth(text)
+	}
+}
+
+func (l *pdfLayout) encode(run textRun) string {
+	if run.family == pdfFallbackFont {
+		return l.encodeCore(run.text)
+	}
+	return run.text
+}
+
+// shape lays out a run in a shaped script at size points. The glyphs of a
+// right-to-left run come out in visual order.
+func (l *pdfLayout) shape(run textRun, size float64) shaping.Output {
+	face := l.faces[run.family]
+	if face == nil {
+		face = font.NewFace(l.fonts[run.family].parsed)
+		l.faces[run.family] = face
+	}
+	text := []rune(run.text)
+	input := shaping.Input{
+		Text:      text,
+		RunEnd:    len(text),
+		Direction: di.DirectionLTR,
+		Face:      face,
+		Size:      fixed.Int26_6(size * 64),
+		Script:    language.Common,
+	}
+	if run.rtl {
+		input.Direction = di.DirectionRTL
+	}
+	for _, ch := range text {
+		if script := language.LookupScript(ch); script != language.Common && script != language.Inherited {
+			input.Script = script
+			break
+		}
+	}
+	return l.shaper.Shape(input)
+}
+
+// drawGlyphs draws shaped glyphs as filled outlines from x along the
+// baseline. They are drawn rather than written as PDF text because a PDF
+// string holds characters, and shaping produces ligatures, reordered vowel
+// signs and positioned marks that have none.
+func (l *pdfLayout) drawGlyphs(out shaping.Output, x, baseline float64) {
+	scale := l.fromFixed(out.Size) / float64(out.Face.Upem())
+	l.pdf.SetFillColor(0, 0, 0)
+	for _, glyph := range out.Glyphs {
+		outline, ok := out.Face.GlyphData(glyph.GlyphID).(font.GlyphOutline)
+		if ok && len(outline.Segments) > 0 {
+			originX := x + l.fromFixed(glyph.XOffset)
+			originY := baseline - l.fromFixed(glyph.YOffset)
+			at := func(p font.SegmentPoint) (float64, float64) {
+				return originX + float64(p.X)*scale, originY - float64(p.Y)*scale
+			}
+
+			var currentX, currentY float64
+			for _, segment := range outline.Segments {
+				endX, endY := 0.0, 0.0
+				switch segment.Op {
+				case ot.SegmentOpMoveTo:
+					endX, endY = at(segment.Args[0])
+					l.pdf.MoveTo(endX, endY)
+				case ot.SegmentOpLineTo:
+					endX, endY = at(segment.Args[0])
+					l.pdf.LineTo(endX, endY)
+				case ot.SegmentOpQuadTo:
+					// PDF has no quadratic curves; raise the degree.
+					controlX, controlY := at(segment.Args[0])
+					endX, endY = at(segment.Args[1])
+					l.pdf.CurveBezierCubicTo(
+						currentX+2*(controlX-currentX)/3, currentY+2*(controlY-currentY)/3,
+						endX+2*(controlX-endX)/3, endY+2*(controlY-endY)/3,
+						endX, endY)
+				case ot.SegmentOpCubeTo:
+					control1X, control1Y := at(segment.Args[0])
+					control2X, control2Y := at(segment.Args[1])
+					endX, endY = at(segment.Args[2])
+					l.pdf.CurveBezierCubicTo(control1X, control1Y, control2X, control2Y, endX, endY)
+				}
+				currentX, currentY = endX, endY
+			}
+			l.pdf.DrawPath("F")
+		}
+		x += l.fromFixed(glyph.XAdvance)
+	}
+}
+
+// fromFixed converts a length in points from the shaper to millimetres.
+func (l *pdfLayout) fromFixed(v fixed.Int26_6) float64 {
+	return l.pdf.PointConvert(float64(v) / 64)
+}
+
+// drawQRCode draws a QR code as filled rectangles, one per horizontal run
+// of dark modules, so it stays sharp at any print resolution.
+func drawQRCode(pdf *gofpdf.Fpdf, bitmap [][]bool, x, y, size float64) {
+	if len(bitmap) == 0 {
+		return
+	}
+	module := size / float64(len(bitmap))
+	pdf.SetFillColor(0, 0, 0)
+	for row, cells := range bitmap {
+		for col := 0; col < len(cells); col++ {
+			if !cells[col] {
+				continue
+			}
+			start := col
+			for col+1 < len(cells) && cells[col+1] {
+				col++
+			}
+			pdf.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start+1)*module, module, "F")
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"legalassist-ai-backend/models"
+
+	"github.com/go-text/typesetting/font"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// testFonts holds Noto Sans Devanagari and no other fonts.
+const testFonts = "testdata/fonts"
+
+func TestMissingScripts(t *testing.T) {
+	renderer := NewPDFRenderer(testFonts)
+	renderer.loadFonts.Do(renderer.load)
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"FIR No. KOR/2024/0017 — Café ₹500", nil},
+		{"मेरा फोन चोरी हो गया।", nil},
+		{"میرا فون and আমার ফোন", []string{"Bengali", "Arabic"}},
+		{"ਮੇਰਾ ਫ਼ੋਨ ମୋ ଫୋନ୍", []string{"Gurmukhi", "Oriya"}},
+	}
+	for _, tt := range tests {
+		if got := renderer.missingScripts(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("missingScripts(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBidiRuns(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []bidiRun
+		wantRTL bool
+	}{
+		{"FIR No. 12", []bidiRun{{"FIR No. 12", false}}, false},
+		{"ایف آئی آر", []bidiRun{{"ایف آئی آر", true}}, true},
+		{"ایف آئی آر 12", []bidiRun{{"12", false}, {"ایف آئی آر ", true}}, true},
+		{"FIR ایف آر No. 12", []bidiRun{{"FIR ", false}, {"ایف آر", true}, {" No. 12", false}}, false},
+		{"(ایف)", []bidiRun{{"(ایف)", true}}, true},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		got, rtl := bidiRuns(tt.line)
+		if !reflect.DeepEqual(got, tt.want) || rtl != tt.wantRTL {
+			t.Errorf("bidiRuns(%q) = %+v, %v, want %+v, %v", tt.line, got, rtl, tt.want, tt.wantRTL)
+		}
+	}
+}
+
+func TestShapeDevanagari(t *testing.T) {
+	renderer := NewPDFRenderer(testFonts)
+	renderer.loadFonts.Do(renderer.load)
+	layout := &pdfLayout{PDFRenderer: renderer, pdf: gofpdf.New("P", "mm", "A4", ""), faces: map[string]*font.Face{}}
+	nominal := func(ch rune) font.GID {
+		gid, _ := renderer.fonts["Devanagari"].parsed.NominalGlyph(ch)
+		return gid
+	}
+
+	tests := []struct {
+		name string
+		text string
+		// want is the glyphs expected in visual order, 0 standing for one
+		// that is not a letter's nominal glyph.
+		want []font.GID
+	}{
+		{"vowel sign i before its consonant", "कि", []font.GID{0, nominal('क')}},
+		{"conjunct", "क्ष", []font.GID{0}},
+		{"half form", "स्त", []font.GID{0, nominal('त')}},
+		{"plain letters", "कम", []font.GID{nominal('क'), nominal('म')}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := renderer.runs(tt.text)
+			if len(runs) != 1 || runs[0].family != "Devanagari" {
+				t.Fatalf("runs(%q) = %+v, want one Devanagari run", tt.text, runs)
+			}
+			out := layout.shape(runs[0], pdfTextSize)
+			var got []font.GID
+			for _, glyph := range out.Glyphs {
+				gid := glyph.GlyphID
+				if gid == 0 {
+					t.Fatalf("shape(%q) has a missing glyph", tt.text)
+				}
+				if gid != nominal('क') && gid != nominal('म') && gid != nominal('त') {
+					gid = 0
+				}
+				got = append(got, gid)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shape(%q) glyphs = %v, want %v", tt.text, got, tt.want)
+			}
+			if out.Advance <= 0 {
+				t.Errorf("shape(%q) advance = %v, want it positive", tt.text, out.Advance)
+			}
+		})
+	}
+
+	// Punctuation the Devanagari font lacks is set in the Latin font.
+	got := renderer.runs("फोन: 12 & 13")
+	want := []textRun{{family: "Devanagari", text: "फोन: 12 "}, {family: renderer.latinFamily(), text: "& 13"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("runs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPDFRender(t *testing.T) {
+	renderer := NewPDFRenderer(testFonts)
+	long := strings.Repeat("The complainant stated that the accused entered the house at night. ", 20)
+	hindi := strings.Repeat("शिकायतकर्ता ने बताया कि रात में अभियुक्त घर में घुसा और मोबाइल फ़ोन ले गया। ", 20)
+
+	tests := []struct {
+		name    string
+		doc     PDFDocument
+		wantErr error
+	}{
+		{
+			name: "English",
+			doc:  PDFDocument{Title: "FIR 1", Text: "FIRST INFORMATION REPORT\n" + long, Footer: "FIR No. 1", QRContent: "https://example.org/api/verify/x", QRCaption: "Scan to verify"},
+		},
+		{
+			name: "bilingual columns across pages",
+			doc: PDFDocument{Title: "FIR 1", Text: long, Footer: "FIR No. 1", Sections: []models.GeneratedFIRSection{
+				{English: "FIRST INFORMATION REPORT", Translated: "[en->fr] RAPPORT"},
+				{English: long, Translated: "[en->fr] " + long},
+				{English: long + long, Translated: "Résumé"},
+			}},
+		},
+		{
+			name: "Devanagari",
+			doc:  PDFDocument{Title: "FIR 1", Text: "प्रथम सूचना रिपोर्ट\nशिकायतकर्ता: रवि कुमार\n" + hindi, Footer: "FIR No. 1"},
+		},
+		{
+			name: "Hindi translation",
+			doc: PDFDocument{Title: "FIR 1", Text: "FIR", Footer: "FIR No. 1", Sections: []models.GeneratedFIRSection{
+				{English: "FIRST INFORMATION REPORT", Translated: "प्रथम सूचना रिपोर्ट"},
+				{English: long, Translated: hindi},
+			}},
+		},
+		{
+			name: "Urdu without an Arabic font",
+			doc: PDFDocument{Title: "FIR 1", Text: "FIR", Sections: []models.GeneratedFIRSection{
+				{English: "FIR", Translated: "ایف آئی آر"},
+			}},
+			wantErr: ErrPDFScriptUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := renderer.Render(tt.doc)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Render() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !bytes.HasPrefix(data, []byte("%PDF-")) {
+				t.Errorf("Render() output does not start with a PDF header")
+			}
+		})
+	}
+}
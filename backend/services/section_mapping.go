@@ -0,0 +1,222 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/models"
+)
+
+// ErrUnknownAct is returned when an act name or abbreviation is not one of
+// the mapped criminal codes.
+var ErrUnknownAct = errors.New("unknown act")
+
+//go:embed data/section_mappings.json
+var sectionMappingData []byte
+
+// newCodesEffective is when the BNS, BNSS and BSA came into force.
+var newCodesEffective = time.Date(2024, time.July, 1, 0, 0, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+
+// successorActs maps each repealed act to the act that replaced it.
+var successorActs = map[string]string{
+	models.ActIPC:  models.ActBNS,
+	models.ActCrPC: models.ActBNSS,
+	models.ActIEA:  models.ActBSA,
+}
+
+var actAliases = map[string]string{
+	"ipc":                                models.ActIPC,
+	"indian penal code":                  models.ActIPC,
+	"bns":                                models.ActBNS,
+	"bharatiya nyaya sanhita":            models.ActBNS,
+	"crpc":                               models.ActCrPC,
+	"cr.p.c.":                            models.ActCrPC,
+	"code of criminal procedure":         models.ActCrPC,
+	"bnss":                               models.ActBNSS,
+	"bharatiya nagarik suraksha sanhita": models.ActBNSS,
+	"iea":                                models.ActIEA,
+	"evidence act":                       models.ActIEA,
+	"indian evidence act":                models.ActIEA,
+	"bsa":                                models.ActBSA,
+	"bharatiya sakshya adhiniyam":        models.ActBSA,
+}
+
+type sectionKey struct {
+	act     string
+	section string
+}
+
+// sectionMappingTable indexes the embedded correspondence table in both
+// directions. Reverse entries are also indexed by their base section, so
+// "303" finds the rows for "303(1)" and "303(2)".
+type sectionMappingTable struct {
+	version string
+	forward map[sectionKey][]models.SectionMapping
+	reverse map[sectionKey][]models.SectionMapping
+}
+
+var sectionMappings = mustLoadSectionMappings(sectionMappingData)
+
+func mustLoadSectionMappings(data []byte) *sectionMappingTable {
+	var file struct {
+		Version  string                  `json:"version"`
+		Mappings []models.SectionMapping `json:"mappings"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		panic(fmt.Sprintf("invalid section mapping table: %v", err))
+	}
+
+	table := &sectionMappingTable{
+		version: file.Version,
+		forward: make(map[sectionKey][]models.SectionMapping),
+		reverse: make(map[sectionKey][]models.SectionMapping),
+	}
+
+	for _, mapping := range file.Mappings {
+		key := sectionKey{mapping.FromAct, normalizeSection(mapping.FromSection)}
+		table.forward[key] = append(table.forward[key], mapping)
+
+		for _, to := range mapping.ToSections {
+			reversed := models.SectionMapping{
+				FromAct:     mapping.ToAct,
+				FromSection: to,
+				ToAct:       mapping.FromAct,
+				ToSections:  []string{mapping.FromSection},
+				Type:        reverseMappingType(mapping.Type),
+				Notes:       mapping.Notes,
+			}
+
+			exact := sectionKey{mapping.ToAct, normalizeSection(to)}
+			table.reverse[exact] = append(table.reverse[exact], reversed)
+			if base := baseSection(to); base != exact.section {
+				key := sectionKey{mapping.ToAct, base}
+				table.reverse[key] = append(table.reverse[key], reversed)
+			}
+		}
+	}
+
+	return table
+}
+
+// reverseMappingType describes a mapping from the other side: several old
+// sections merged into one new section means the new section splits back
+// into several old ones.
+func reverseMappingType(mappingType string) string {
+	switch mappingType {
+	case "split":
+		return "merged"
+	case "merged":
+		return "split"
+	default:
+		return mappingType
+	}
+}
+
+// MapSection returns the sections corresponding to act/section in the
+// replacement act, or in the repealed act when given a new-code section.
+func (s *LegalService) MapSection(act, section string) ([]models.SectionMapping, error) {
+	return mapSection(act, section)
+}
+
+func mapSection(act, section string) ([]models.SectionMapping, error) {
+	canonical, err := NormalizeAct(act)
+	if err != nil {
+		return nil, err
+	}
+
+	key := sectionKey{canonical, normalizeSection(section)}
+	if _, repealed := successorActs[canonical]; repealed {
+		return sectionMappings.forward[key], nil
+	}
+	return sectionMappings.reverse[key], nil
+}
+
+// EquivalentSections flattens MapSection into section references. Unknown
+// acts and unmapped sections yield no equivalents.
+func (s *LegalService) EquivalentSections(act, section string) []models.SectionRef {
+	return equivalentSections(act, section)
+}
+
+func equivalentSections(act, section string) []models.SectionRef {
+	mappings, err := mapSection(act, section)
+	if err != nil {
+		return nil
+	}
+
+	var refs []models.SectionRef
+	for _, mapping := range mappings {
+		for _, to := range mapping.ToSections {
+			refs = append(refs, models.SectionRef{Act: mapping.ToAct, Section: to})
+		}
+	}
+	return refs
+}
+
+// SectionMappingVersion identifies the embedded correspondence table.
+func (s *LegalService) SectionMappingVersion() string {
+	return sectionMappings.version
+}
+
+// CodesForDate returns the criminal codes in force on date. Offences are
+// charged under the code in force when they were committed, so callers pass
+// the incident date for the penal code.
+func CodesForDate(date time.Time) models.CriminalCodes {
+	if date.Before(newCodesEffective) {
+		return models.CriminalCodes{
+			Penal:     models.ActIPC,
+			Procedure: models.ActCrPC,
+			Evidence:  models.ActIEA,
+		}
+	}
+	return models.CriminalCodes{
+		Penal:     models.ActBNS,
+		Procedure: models.ActBNSS,
+		Evidence:  models.ActBSA,
+	}
+}
+
+// counterpartAct returns the act paired with act across the 2024 reform,
+// or "" for acts that were not replaced.
+func counterpartAct(act string) string {
+	if successor, ok := successorActs[act]; ok {
+		return successor
+	}
+	for repealed, successor := range successorActs {
+		if successor == act {
+			return repealed
+		}
+	}
+	return ""
+}
+
+// NormalizeAct resolves an act name or common abbreviation to the name used
+// in the legal database.
+func NormalizeAct(act string) (string, error) {
+	if canonical, ok := actAliases[strings.ToLower(strings.TrimSpace(act))]; ok {
+		return canonical, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownAct, act)
+}
+
+var sectionPrefixPattern = regexp.MustCompile(`(?i)^(section|sec\.?|s\.)\s*`)
+
+// normalizeSection strips "Section"/"s." prefixes and whitespace and
+// upper-cases letter suffixes, so "s. 498a" becomes "498A".
+func normalizeSection(section string) string {
+	section = sectionPrefixPattern.ReplaceAllString(strings.TrimSpace(section), "")
+	return strings.ToUpper(strings.ReplaceAll(section, " ", ""))
+}
+
+// baseSection drops sub-section and clause references: "303(2)" -> "303".
+func baseSection(section string) string {
+	section = normalizeSection(section)
+	if i := strings.Index(section, "("); i > 0 {
+		return section[:i]
+	}
+	return section
+}
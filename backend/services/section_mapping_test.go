@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"legalassist-ai-backend/models"
+)
+
+func TestCodesForDate(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"long before", time.Date(2023, time.March, 10, 12, 0, 0, 0, ist), models.ActIPC},
+		{"last minute in IST", time.Date(2024, time.June, 30, 23, 59, 59, 0, ist), models.ActIPC},
+		{"midnight in IST", time.Date(2024, time.July, 1, 0, 0, 0, 0, ist), models.ActBNS},
+		{"UTC evening before is already July in IST", time.Date(2024, time.June, 30, 18, 30, 0, 0, time.UTC), models.ActBNS},
+		{"UTC just before the IST midnight", time.Date(2024, time.June, 30, 18, 29, 59, 0, time.UTC), models.ActIPC},
+		{"after", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), models.ActBNS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := CodesForDate(tt.date)
+			if codes.Penal != tt.want {
+				t.Fatalf("CodesForDate(%s).Penal = %q, want %q", tt.date, codes.Penal, tt.want)
+			}
+			wantProcedure := models.ActBNSS
+			if tt.want == models.ActIPC {
+				wantProcedure = models.ActCrPC
+			}
+			if codes.Procedure != wantProcedure {
+				t.Errorf("CodesForDate(%s).Procedure = %q, want %q", tt.date, codes.Procedure, wantProcedure)
+			}
+		})
+	}
+}
+
+func TestNormalizeAct(t *testing.T) {
+	tests := []struct {
+		act     string
+		want    string
+		wantErr bool
+	}{
+		{"IPC", models.ActIPC, false},
+		{"  Bharatiya Nyaya Sanhita ", models.ActBNS, false},
+		{"Cr.P.C.", models.ActCrPC, false},
+		{"evidence act", models.ActIEA, false},
+		{"Motor Vehicles Act", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeAct(tt.act)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnknownAct) {
+				t.Errorf("NormalizeAct(%q) error = %v, want ErrUnknownAct", tt.act, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeAct(%q) = %q, %v, want %q", tt.act, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSection(t *testing.T) {
+	tests := []struct {
+		section  string
+		want     string
+		wantBase string
+	}{
+		{"302", "302", "302"},
+		{"s. 498a", "498A", "498A"},
+		{"Section 303(2)", "303(2)", "303"},
+		{"sec.420", "420", "420"},
+		{" 376 D ", "376D", "376D"},
+	}
+	for _, tt := range tests {
+		if got := normalizeSection(tt.section); got != tt.want {
+			t.Errorf("normalizeSection(%q) = %q, want %q", tt.section, got, tt.want)
+		}
+		if got := baseSection(tt.section); got != tt.wantBase {
+			t.Errorf("baseSection(%q) = %q, want %q", tt.section, got, tt.wantBase)
+		}
+	}
+}
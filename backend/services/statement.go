@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"legalassist-ai-backend/models"
+)
+
+const (
+	// turnPause is the silence, in seconds, after which a speaker's next
+	// segment starts a new turn.
+	turnPause = 2.0
+	// officerQuestionRatio is the share of a speaker's turns that must be
+	// questions before the speaker is taken to be the officer.
+	officerQuestionRatio = 0.3
+)
+
+// buildTurns merges consecutive segments by the same speaker into turns.
+func buildTurns(segments []TranscriptSegment) []models.SpeakerTurn {
+	turns := []models.SpeakerTurn{}
+	for _, segment := range segments {
+		if segment.Text == "" {
+			continue
+		}
+		if n := len(turns); n > 0 && turns[n-1].Speaker == segment.Speaker && segment.Start-turns[n-1].End < turnPause {
+			turns[n-1].End = segment.End
+			turns[n-1].Text += " " + segment.Text
+			continue
+		}
+		turns = append(turns, models.SpeakerTurn{
+			Speaker: segment.Speaker,
+			Start:   segment.Start,
+			End:     segment.End,
+			Text:    segment.Text,
+		})
+	}
+	return turns
+}
+
+// summarizeSpeakers totals each speaker's turns, in order of first
+// appearance, and suggests a role for each. A lone speaker is the
+// complainant. Otherwise the speaker who mostly asks questions is taken to
+// be the officer, the one who talks longest of the rest the complainant,
+// and everyone else a witness.
+func summarizeSpeakers(turns []models.SpeakerTurn) []models.SpeakerSummary {
+	summaries := []models.SpeakerSummary{}
+	index := map[string]int{}
+	questions := map[string]int{}
+	for _, turn := range turns {
+		i, ok := index[turn.Speaker]
+		if !ok {
+			i = len(summaries)
+			index[turn.Speaker] = i
+			summaries = append(summaries, models.SpeakerSummary{Speaker: turn.Speaker})
+		}
+		summaries[i].Turns++
+		summaries[i].Duration += turn.End - turn.Start
+		if strings.Contains(turn.Text, "?") {
+			questions[turn.Speaker]++
+		}
+	}
+
+	if len(summaries) == 1 {
+		summaries[0].SuggestedRole = models.RoleComplainant
+		return summaries
+	}
+
+	officer, officerRatio := -1, officerQuestionRatio
+	for i, summary := range summaries {
+		if ratio := float64(questions[summary.Speaker]) / float64(summary.Turns); ratio >= officerRatio {
+			officer, officerRatio = i, ratio
+		}
+	}
+
+	byDuration := make([]int, 0, len(summaries))
+	for i := range summaries {
+		if i != officer {
+			byDuration = append(byDuration, i)
+		}
+	}
+	sort.SliceStable(byDuration, func(a, b int) bool {
+		return summaries[byDuration[a]].Duration > summaries[byDuration[b]].Duration
+	})
+
+	for rank, i := range byDuration {
+		summaries[i].SuggestedRole = models.RoleWitness
+		if rank == 0 {
+			summaries[i].SuggestedRole = models.RoleComplainant
+		}
+	}
+	if officer >= 0 {
+		summaries[officer].SuggestedRole = models.RoleOfficer
+	}
+	return summaries
+}
+
+// MapStatement maps a statement's turns into FIR text. A turn's own role
+// takes precedence over the role given for its speaker, which takes
+// precedence over the speaker's suggested role. Complainant turns form the
+// incident description, officer turns the officer's remarks, and witness
+// turns the witness details, grouped by witness.
+func MapStatement(req models.MapStatementRequest) (*models.StatementMapping, error) {
+	summaries := summarizeSpeakers(req.Turns)
+	suggested := map[string]string{}
+	for _, summary := range summaries {
+		suggested[summary.Speaker] = summary.SuggestedRole
+	}
+
+	var problems []string
+	for speaker, role := range req.Roles {
+		if !validRole(role) {
+			problems = append(problems, fmt.Sprintf("roles[%q]: invalid role %q", speaker, role))
+		}
+	}
+	for i, turn := range req.Turns {
+		if turn.Role != "" && !validRole(turn.Role) {
+			problems = append(problems, fmt.Sprintf("turns[%d]: invalid role %q", i, turn.Role))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var complainant, officer []string
+	var witnesses []string
+	witnessText := map[string][]string{}
+	for _, turn := range req.Turns {
+		role := turn.Role
+		if role == "" {
+			role = req.Roles[turn.Speaker]
+		}
+		if role == "" {
+			role = suggested[turn.Speaker]
+		}
+		text := strings.TrimSpace(turn.Text)
+
+		switch role {
+		case models.RoleComplainant:
+			complainant = append(complainant, text)
+		case models.RoleOfficer:
+			officer = append(officer, text)
+		case models.RoleWitness:
+			if _, ok := witnessText[turn.Speaker]; !ok {
+				witnesses = append(witnesses, turn.Speaker)
+			}
+			witnessText[turn.Speaker] = append(witnessText[turn.Speaker], text)
+		}
+	}
+
+	var witnessDetails []string
+	for i, speaker := range witnesses {
+		text := strings.Join(witnessText[speaker], " ")
+		if len(witnesses) > 1 {
+			text = fmt.Sprintf("Witness %d: %s", i+1, text)
+		}
+		witnessDetails = append(witnessDetails, text)
+	}
+
+	return &models.StatementMapping{
+		IncidentDescription: strings.Join(complainant, " "),
+		WitnessDetails:      strings.Join(witnessDetails, "\n\n"),
+		OfficerRemarks:      strings.Join(officer, " "),
+		Speakers:            summaries,
+	}, nil
+}
+
+func validRole(role string) bool {
+	switch role {
+	case models.RoleComplainant, models.RoleWitness, models.RoleOfficer, models.RoleIgnore:
+		return true
+	}
+	return false
+}
+
+// ApplyStatement maps a statement into a draft FIR's incident description,
+// witness details and officer remarks. Only fields the statement has text
+// for are changed, and with req.Append the text is added after what the
+// FIR already has.
+func (s *FIRService) ApplyStatement(ctx context.Context, firID, officerID string, req models.MapStatementRequest) (*models.FIR, error) {
+	mapping, err := MapStatement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fir, err := s.GetFIRByID(firID, officerID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := models.UpdateFIRRequest{Reason: strings.TrimSpace(req.Reason)}
+	if update.Reason == "" {
+		update.Reason = "Mapped from recorded statement"
+	}
+	fields := []struct {
+		mapped   string
+		existing string
+		target   **string
+	}{
+		{mapping.IncidentDescription, fir.IncidentDescription, &update.IncidentDescription},
+		{mapping.WitnessDetails, fir.WitnessDetails, &update.WitnessDetails},
+		{mapping.OfficerRemarks, fir.OfficerRemarks, &update.OfficerRemarks},
+	}
+	changed := false
+	for _, f := range fields {
+		if f.mapped == "" {
+			continue
+		}
+		value := f.mapped
+		if req.Append && strings.TrimSpace(f.existing) != "" {
+			value = strings.TrimSpace(f.existing) + "\n\n" + f.mapped
+		}
+		*f.target = &value
+		changed = true
+	}
+	if !changed {
+		return nil, &ValidationError{Problems: []string{"statement has no text to map into the FIR"}}
+	}
+
+	return s.UpdateFIR(ctx, firID, officerID, update)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"legalassist-ai-backend/models"
+)
+
+func TestBuildTurns(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []TranscriptSegment
+		want     []models.SpeakerTurn
+	}{
+		{"no segments", nil, []models.SpeakerTurn{}},
+		{
+			"same speaker within the pause",
+			[]TranscriptSegment{
+				{Speaker: "A", Start: 0, End: 1, Text: "My bag"},
+				{Speaker: "A", Start: 2.5, End: 3, Text: "was snatched."},
+			},
+			[]models.SpeakerTurn{{Speaker: "A", Start: 0, End: 3, Text: "My bag was snatched."}},
+		},
+		{
+			"same speaker after the pause",
+			[]TranscriptSegment{
+				{Speaker: "A", Start: 0, End: 1, Text: "My bag was snatched."},
+				{Speaker: "A", Start: 3, End: 4, Text: "Near the market."},
+			},
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 1, Text: "My bag was snatched."},
+				{Speaker: "A", Start: 3, End: 4, Text: "Near the market."},
+			},
+		},
+		{
+			"speaker changes",
+			[]TranscriptSegment{
+				{Speaker: "A", Start: 0, End: 1, Text: "What happened?"},
+				{Speaker: "B", Start: 1, End: 2, Text: "My bag was snatched."},
+				{Speaker: "A", Start: 2, End: 3, Text: "When?"},
+			},
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 1, Text: "What happened?"},
+				{Speaker: "B", Start: 1, End: 2, Text: "My bag was snatched."},
+				{Speaker: "A", Start: 2, End: 3, Text: "When?"},
+			},
+		},
+		{
+			"empty segments skipped",
+			[]TranscriptSegment{
+				{Speaker: "A", Start: 0, End: 1, Text: "Around"},
+				{Speaker: "A", Start: 1, End: 1.5, Text: ""},
+				{Speaker: "B", Start: 1.5, End: 1.75, Text: ""},
+				{Speaker: "A", Start: 2, End: 3, Text: "nine."},
+			},
+			[]models.SpeakerTurn{{Speaker: "A", Start: 0, End: 3, Text: "Around nine."}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildTurns(tt.segments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildTurns() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeSpeakers(t *testing.T) {
+	tests := []struct {
+		name  string
+		turns []models.SpeakerTurn
+		want  []models.SpeakerSummary
+	}{
+		{"no turns", nil, []models.SpeakerSummary{}},
+		{
+			"lone speaker",
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 2, Text: "Who do I report this to?"},
+				{Speaker: "A", Start: 5, End: 9, Text: "My bag was snatched."},
+			},
+			[]models.SpeakerSummary{{Speaker: "A", Duration: 6, Turns: 2, SuggestedRole: models.RoleComplainant}},
+		},
+		{
+			"interview with a witness",
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 2, Text: "What happened?"},
+				{Speaker: "B", Start: 2, End: 12, Text: "My bag was snatched near the market."},
+				{Speaker: "C", Start: 12, End: 15, Text: "I saw a man run."},
+				{Speaker: "A", Start: 15, End: 16, Text: "Thank you."},
+			},
+			[]models.SpeakerSummary{
+				{Speaker: "A", Duration: 3, Turns: 2, SuggestedRole: models.RoleOfficer},
+				{Speaker: "B", Duration: 10, Turns: 1, SuggestedRole: models.RoleComplainant},
+				{Speaker: "C", Duration: 3, Turns: 1, SuggestedRole: models.RoleWitness},
+			},
+		},
+		{
+			"nobody asks enough questions",
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 1, Text: "Yes."},
+				{Speaker: "A", Start: 3, End: 4, Text: "Why?"},
+				{Speaker: "A", Start: 6, End: 7, Text: "No."},
+				{Speaker: "A", Start: 9, End: 10, Text: "Fine."},
+				{Speaker: "B", Start: 10, End: 18, Text: "My bag was snatched."},
+			},
+			[]models.SpeakerSummary{
+				{Speaker: "A", Duration: 4, Turns: 4, SuggestedRole: models.RoleWitness},
+				{Speaker: "B", Duration: 8, Turns: 1, SuggestedRole: models.RoleComplainant},
+			},
+		},
+		{
+			"most questions is the officer",
+			[]models.SpeakerTurn{
+				{Speaker: "A", Start: 0, End: 1, Text: "Is this the station?"},
+				{Speaker: "B", Start: 1, End: 2, Text: "What happened?"},
+				{Speaker: "A", Start: 2, End: 8, Text: "My bag was snatched."},
+			},
+			[]models.SpeakerSummary{
+				{Speaker: "A", Duration: 7, Turns: 2, SuggestedRole: models.RoleComplainant},
+				{Speaker: "B", Duration: 1, Turns: 1, SuggestedRole: models.RoleOfficer},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeSpeakers(tt.turns); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("summarizeSpeakers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapStatement(t *testing.T) {
+	interview := []models.SpeakerTurn{
+		{Speaker: "A", Start: 0, End: 1, Text: "What happened?"},
+		{Speaker: "B", Start: 1, End: 11, Text: " My bag was snatched. "},
+		{Speaker: "C", Start: 11, End: 12, Text: "I saw him."},
+		{Speaker: "D", Start: 12, End: 13, Text: "I heard shouting."},
+		{Speaker: "C", Start: 13, End: 14, Text: "He ran north."},
+		{Speaker: "A", Start: 14, End: 15, Text: "Noted."},
+	}
+	withRole := func(turns []models.SpeakerTurn, i int, role string) []models.SpeakerTurn {
+		turns = append([]models.SpeakerTurn(nil), turns...)
+		turns[i].Role = role
+		return turns
+	}
+
+	tests := []struct {
+		name string
+		req  models.MapStatementRequest
+		want models.StatementMapping
+	}{
+		{
+			"suggested roles",
+			models.MapStatementRequest{Turns: interview},
+			models.StatementMapping{
+				IncidentDescription: "My bag was snatched.",
+				WitnessDetails:      "Witness 1: I saw him. He ran north.\n\nWitness 2: I heard shouting.",
+				OfficerRemarks:      "What happened? Noted.",
+			},
+		},
+		{
+			"speaker roles override suggestions",
+			models.MapStatementRequest{Turns: interview, Roles: map[string]string{
+				"C": models.RoleComplainant,
+				"D": models.RoleIgnore,
+			}},
+			models.StatementMapping{
+				IncidentDescription: "My bag was snatched. I saw him. He ran north.",
+				OfficerRemarks:      "What happened? Noted.",
+			},
+		},
+		{
+			"single witness is not numbered",
+			models.MapStatementRequest{Turns: interview, Roles: map[string]string{"D": models.RoleIgnore}},
+			models.StatementMapping{
+				IncidentDescription: "My bag was snatched.",
+				WitnessDetails:      "I saw him. He ran north.",
+				OfficerRemarks:      "What happened? Noted.",
+			},
+		},
+		{
+			"turn role overrides speaker role",
+			models.MapStatementRequest{
+				Turns: withRole(interview, 5, models.RoleIgnore),
+				Roles: map[string]string{"A": models.RoleOfficer, "D": models.RoleIgnore},
+			},
+			models.StatementMapping{
+				IncidentDescription: "My bag was snatched.",
+				WitnessDetails:      "I saw him. He ran north.",
+				OfficerRemarks:      "What happened?",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapStatement(tt.req)
+			if err != nil {
+				t.Fatalf("MapStatement() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Speakers, summarizeSpeakers(tt.req.Turns)) {
+				t.Errorf("Speakers = %+v, want the suggested roles", got.Speakers)
+			}
+			got.Speakers = nil
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("MapStatement() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	_, err := MapStatement(models.MapStatementRequest{
+		Turns: withRole(interview, 1, "victim"),
+		Roles: map[string]string{"A": "judge"},
+	})
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("MapStatement() with invalid roles error = %v, want a ValidationError", err)
+	}
+	want := []string{`roles["A"]: invalid role "judge"`, `turns[1]: invalid role "victim"`}
+	if !reflect.DeepEqual(validation.Problems, want) {
+		t.Errorf("Problems = %q, want %q", validation.Problems, want)
+	}
+}
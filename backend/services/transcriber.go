@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/models"
+)
+
+const (
+	defaultWhisperBaseURL      = "https://api.openai.com/v1"
+	defaultLocalWhisperBaseURL = "http://localhost:8000/v1"
+	defaultWhisperModel        = "whisper-1"
+	defaultTranscriberTimeout  = 2 * time.Minute
+)
+
+// TranscriptionRequest is an audio recording sent to a Transcriber.
+type TranscriptionRequest struct {
+	Audio    io.Reader
+	Format   string        // file extension of the detected format, e.g. "wav"
+	Language string        // ISO 639-1 hint; empty lets the backend detect it
+	Duration time.Duration // known length of the recording, or 0
+}
+
+// TranscriptSegment is a span of speech. Start and End are in seconds and
+// Confidence is between 0 and 1. Speaker is a label such as "SPEAKER_1"
+// when the recording has been diarized.
+type TranscriptSegment struct {
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Speaker    string  `json:"speaker,omitempty"`
+}
+
+// Transcript is the text of a recording with its timed segments, grouped
+// into speaker turns. Duration is in seconds.
+type Transcript struct {
+	Text     string                  `json:"text"`
+	Language string                  `json:"language"`
+	Duration float64                 `json:"duration"`
+	Segments []TranscriptSegment     `json:"segments"`
+	Turns    []models.SpeakerTurn    `json:"turns"`
+	Speakers []models.SpeakerSummary `json:"speakers"`
+	Provider string                  `json:"provider"`
+	Model    string                  `json:"model"`
+}
+
+// Transcriber turns recorded speech into text.
+type Transcriber interface {
+	Name() string
+	Transcribe(ctx context.Context, req TranscriptionRequest) (*Transcript, error)
+}
+
+// NewTranscriber builds the transcriber selected by
+// cfg.TranscriberProvider. When none is configured, a transcriber base URL or
+// an OpenAI API key selects Whisper and their absence selects the fake.
+func NewTranscriber(cfg *config.Config) Transcriber {
+	switch transcriberName(cfg) {
+	case "whisper":
+		baseURL, apiKey := cfg.TranscriberBaseURL, ""
+		if baseURL == "" {
+			baseURL, apiKey = defaultWhisperBaseURL, cfg.OpenAIAPIKey
+		}
+		return NewWhisperTranscriber(baseURL, apiKey, cfg.TranscriberModel, cfg.TranscriberTimeout)
+	case "local":
+		baseURL := cfg.TranscriberBaseURL
+		if baseURL == "" {
+			baseURL = defaultLocalWhisperBaseURL
+		}
+		return NewWhisperTranscriber(baseURL, "", cfg.TranscriberModel, cfg.TranscriberTimeout)
+	case "fake":
+		return NewFakeTranscriber()
+	default:
+		log.Printf("Unknown transcriber %q, falling back to fake", cfg.TranscriberProvider)
+		return NewFakeTranscriber()
+	}
+}
+
+func transcriberName(cfg *config.Config) string {
+	if cfg.TranscriberProvider != "" {
+		return strings.ToLower(cfg.TranscriberProvider)
+	}
+	if cfg.TranscriberBaseURL != "" || cfg.OpenAIAPIKey != "" {
+		return "whisper"
+	}
+	return "fake"
+}
+
+// WhisperTranscriber talks to any server implementing the OpenAI
+// /audio/transcriptions API: api.openai.com, faster-whisper-server,
+// whisper.cpp's server and similar self-hosted deployments.
+type WhisperTranscriber struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewWhisperTranscriber creates a client for a Whisper-compatible endpoint.
+// No Authorization header is sent when apiKey is empty.
+func NewWhisperTranscriber(baseURL, apiKey, model string, timeout time.Duration) *WhisperTranscriber {
+	if model == "" {
+		model = defaultWhisperModel
+	}
+	if timeout <= 0 {
+		timeout = defaultTranscriberTimeout
+	}
+	return &WhisperTranscriber{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *WhisperTranscriber) Name() string {
+	return "whisper"
+}
+
+func (t *WhisperTranscriber) Transcribe(ctx context.Context, req TranscriptionRequest) (*Transcript, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "audio."+req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, req.Audio); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{
+		"model":                     t.model,
+		"response_format":           "verbose_json",
+		"timestamp_granularities[]": "segment",
+	}
+	if req.Language != "" {
+		fields["language"] = req.Language
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+	if t.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("whisper transcription failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("whisper returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Text     string  `json:"text"`
+		Language string  `json:"language"`
+		Duration float64 `json:"duration"`
+		Segments []struct {
+			Start        float64 `json:"start"`
+			End          float64 `json:"end"`
+			Text         string  `json:"text"`
+			AvgLogprob   float64 `json:"avg_logprob"`
+			NoSpeechProb float64 `json:"no_speech_prob"`
+			Speaker      string  `json:"speaker"`
+		} `json:"segments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("whisper transcription: %w", err)
+	}
+
+	transcript := &Transcript{
+		Text:     strings.TrimSpace(result.Text),
+		Language: languageCode(result.Language),
+		Duration: result.Duration,
+		Segments: []TranscriptSegment{},
+		Provider: t.Name(),
+		Model:    t.model,
+	}
+	for _, segment := range result.Segments {
+		transcript.Segments = append(transcript.Segments, TranscriptSegment{
+			Start:      segment.Start,
+			End:        segment.End,
+			Text:       strings.TrimSpace(segment.Text),
+			Confidence: segmentConfidence(segment.AvgLogprob, segment.NoSpeechProb),
+			Speaker:    segment.Speaker,
+		})
+	}
+	if transcript.Language == "" {
+		transcript.Language = req.Language
+	}
+	return transcript, nil
+}
+
+// segmentConfidence turns Whisper's mean token log-probability into a
+// probability, discounted by the chance the segment is not speech at all.
+func segmentConfidence(avgLogprob, noSpeechProb float64) float64 {
+	confidence := math.Exp(avgLogprob) * (1 - noSpeechProb)
+	return math.Round(math.Max(0, math.Min(1, confidence))*1000) / 1000
+}
+
+// FakeTranscriber returns canned text without reaching a speech backend.
+// Segments are one per sentence, spread evenly over the recording, so the
+// same input always gives the same transcript.
+type FakeTranscriber struct {
+	Text       string
+	Confidence float64
+}
+
+func NewFakeTranscriber() *FakeTranscriber {
+	return &FakeTranscriber{
+		Text:       "This is a sample transcription of the recorded statement. Configure a Whisper-compatible transcriber to transcribe real audio.",
+		Confidence: 0.9,
+	}
+}
+
+func (t *FakeTranscriber) Name() string {
+	return "fake"
+}
+
+func (t *FakeTranscriber) Transcribe(ctx context.Context, req TranscriptionRequest) (*Transcript, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sentences := splitSentences(t.Text)
+	duration := req.Duration.Seconds()
+	if duration <= 0 {
+		duration = float64(len(sentences))
+	}
+	step := duration / float64(max(len(sentences), 1))
+
+	transcript := &Transcript{
+		Text:     t.Text,
+		Language: req.Language,
+		Duration: duration,
+		Segments: []TranscriptSegment{},
+		Provider: t.Name(),
+		Model:    "fake",
+	}
+	if transcript.Language == "" {
+		transcript.Language = "en"
+	}
+	for i, sentence := range sentences {
+		transcript.Segments = append(transcript.Segments, TranscriptSegment{
+			Start:      float64(i) * step,
+			End:        float64(i+1) * step,
+			Text:       sentence,
+			Confidence: t.Confidence,
+		})
+	}
+	return transcript, nil
+}
+
+// splitSentences splits text after each full stop, question mark or
+// exclamation mark.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if r == '.' || r == '?' || r == '!' || r == '।' {
+			if sentence := strings.TrimSpace(text[start : i+len(string(r))]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i + len(string(r))
+		}
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeTranscriber(t *testing.T) {
+	fake := &FakeTranscriber{Text: "My phone was stolen. It was near the bus stop! Who took it?", Confidence: 0.8}
+
+	tests := []struct {
+		name         string
+		req          TranscriptionRequest
+		wantLanguage string
+		wantDuration float64
+		wantStarts   []float64
+	}{
+		{"unknown duration", TranscriptionRequest{}, "en", 3, []float64{0, 1, 2}},
+		{"known duration", TranscriptionRequest{Duration: 6 * time.Second, Language: "hi"}, "hi", 6, []float64{0, 2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript, err := fake.Transcribe(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Transcribe() error = %v", err)
+			}
+			if transcript.Language != tt.wantLanguage || transcript.Duration != tt.wantDuration {
+				t.Errorf("language, duration = %q, %v, want %q, %v", transcript.Language, transcript.Duration, tt.wantLanguage, tt.wantDuration)
+			}
+			var starts []float64
+			for _, segment := range transcript.Segments {
+				starts = append(starts, segment.Start)
+				if segment.Confidence != 0.8 {
+					t.Errorf("segment confidence = %v, want 0.8", segment.Confidence)
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.wantStarts) {
+				t.Errorf("segment starts = %v, want %v", starts, tt.wantStarts)
+			}
+			if last := transcript.Segments[len(transcript.Segments)-1]; last.End != tt.wantDuration {
+				t.Errorf("last segment ends at %v, want %v", last.End, tt.wantDuration)
+			}
+
+			again, _ := fake.Transcribe(context.Background(), tt.req)
+			if !reflect.DeepEqual(again, transcript) {
+				t.Errorf("Transcribe() is not deterministic: %+v, then %+v", transcript, again)
+			}
+		})
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := fake.Transcribe(ctx, TranscriptionRequest{}); err == nil {
+			t.Error("Transcribe() with a cancelled context succeeded")
+		}
+	})
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"One. Two? Three!", []string{"One.", "Two?", "Three!"}},
+		{"No full stop at the end", []string{"No full stop at the end"}},
+		{"मेरा फोन चोरी हो गया। वह बस स्टॉप पर था।", []string{"मेरा फोन चोरी हो गया।", "वह बस स्टॉप पर था।"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := splitSentences(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitSentences(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentConfidence(t *testing.T) {
+	tests := []struct {
+		avgLogprob, noSpeechProb float64
+		want                     float64
+	}{
+		{0, 0, 1},
+		{-0.105, 0, 0.9},
+		{-0.105, 0.5, 0.45},
+		{-5, 0, 0.007},
+		{0, 1, 0},
+		{0.5, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := segmentConfidence(tt.avgLogprob, tt.noSpeechProb); got != tt.want {
+			t.Errorf("segmentConfidence(%v, %v) = %v, want %v", tt.avgLogprob, tt.noSpeechProb, got, tt.want)
+		}
+	}
+}
+
+func TestWhisperTranscriber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer key" {
+			http.Error(w, "bad authorization "+got, http.StatusUnauthorized)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		audio, _ := io.ReadAll(file)
+		if header.Filename != "audio.ogg" || string(audio) != "OggS audio" {
+			http.Error(w, "unexpected file "+header.Filename, http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("model") != "whisper-1" || r.FormValue("language") != "hi" || r.FormValue("response_format") != "verbose_json" {
+			http.Error(w, "unexpected fields", http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"text": " Mera phone chori ho gaya. ", "language": "hindi", "duration": 4.2,
+			"segments": [{"start": 0, "end": 4.2, "text": " Mera phone chori ho gaya.", "avg_logprob": 0, "no_speech_prob": 0.1}]}`)
+	}))
+	defer server.Close()
+
+	transcriber := NewWhisperTranscriber(server.URL+"/v1/", "key", "", time.Second)
+	transcript, err := transcriber.Transcribe(context.Background(), TranscriptionRequest{
+		Audio:    strings.NewReader("OggS audio"),
+		Format:   "ogg",
+		Language: "hi",
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error = %v", err)
+	}
+	want := &Transcript{
+		Text:     "Mera phone chori ho gaya.",
+		Language: "hi",
+		Duration: 4.2,
+		Segments: []TranscriptSegment{{Start: 0, End: 4.2, Text: "Mera phone chori ho gaya.", Confidence: 0.9}},
+		Provider: "whisper",
+		Model:    "whisper-1",
+	}
+	if !reflect.DeepEqual(transcript, want) {
+		t.Errorf("Transcribe() = %+v, want %+v", transcript, want)
+	}
+
+	unauthorized := NewWhisperTranscriber(server.URL+"/v1", "", "", time.Second)
+	_, err = unauthorized.Transcribe(context.Background(), TranscriptionRequest{Audio: strings.NewReader("OggS audio"), Format: "ogg"})
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Transcribe() without a key error = %v, want status 401", err)
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"legalassist-ai-backend/config"
+	"legalassist-ai-backend/jobs"
+	"legalassist-ai-backend/storage"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrUnsupportedAudioFormat is returned for uploads that are not in one
+	// of the recognised audio container formats.
+	ErrUnsupportedAudioFormat = errors.New("unsupported audio format: use WAV, MP3, M4A, OGG, WebM or FLAC")
+	// ErrAudioTooLarge is returned when a recording exceeds the configured
+	// maximum size.
+	ErrAudioTooLarge = errors.New("audio file is too large")
+	// ErrAudioTooLong is returned when a recording exceeds the configured
+	// maximum duration.
+	ErrAudioTooLong = errors.New("audio recording is too long")
+)
+
+// languageCodes maps the language names used on FIRs to the ISO 639-1 codes
+// speech and translation backends expect.
+var languageCodes = map[string]string{
+	"english":   "en",
+	"hindi":     "hi",
+	"marathi":   "mr",
+	"tamil":     "ta",
+	"telugu":    "te",
+	"bengali":   "bn",
+	"gujarati":  "gu",
+	"kannada":   "kn",
+	"malayalam": "ml",
+	"punjabi":   "pa",
+	"odia":      "or",
+	"urdu":      "ur",
+	"assamese":  "as",
+}
+
+// languageCode normalises a language name or code to ISO 639-1. Unknown
+// values are returned lower-cased so backends can still try them.
+func languageCode(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if code, ok := languageCodes[language]; ok {
+		return code
+	}
+	return language
+}
+
+// AudioUpload is a recording sent for transcription. Language is an
+// explicit hint; otherwise the language of FIRID, if given, is used.
+type AudioUpload struct {
+	Audio    io.Reader
+	Language string
+	FIRID    string
+}
+
+// JobTranscribe is the job type that transcribes a stored recording.
+const JobTranscribe = "transcription"
+
+type TranscriptionService struct {
+	transcriber Transcriber
+	diarizer    Diarizer
+	maxSize     int64
+	maxDuration time.Duration
+	firService  *FIRService
+	store       storage.BlobStore
+	queue       *jobs.Queue
+}
+
+func NewTranscriptionService(cfg *config.Config) *TranscriptionService {
+	return &TranscriptionService{
+		transcriber: NewTranscriber(cfg),
+		diarizer:    NewDiarizer(cfg),
+		maxSize:     cfg.TranscribeMaxSize,
+		maxDuration: cfg.TranscribeMaxDuration,
+		firService:  NewFIRService(cfg),
+		store:       storage.NewLocalStore(cfg.AudioStoragePath),
+		queue:       jobs.NewQueue(),
+	}
+}
+
+// MaxSize is the largest recording accepted, in bytes.
+func (s *TranscriptionService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// RegisterJobs registers the transcription job handler with pool.
+func (s *TranscriptionService) RegisterJobs(pool *jobs.Pool) {
+	pool.Register(JobTranscribe, s.runTranscriptionJob)
+}
+
+// Transcribe validates a recording's format, size and duration and sends it
+// to the configured transcriber.
+func (s *TranscriptionService) Transcribe(ctx context.Context, officerID string, upload AudioUpload) (*Transcript, error) {
+	audio, req, err := s.prepare(officerID, upload)
+	if err != nil {
+		return nil, err
+	}
+	return s.transcribe(ctx, audio, req)
+}
+
+// transcriptionPayload describes a recording stored for a transcription
+// job.
+type transcriptionPayload struct {
+	AudioKey string        `json:"audio_key"`
+	Format   string        `json:"format"`
+	Language string        `json:"language,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// TranscribeAsync validates a recording like Transcribe, stores it and
+// queues it for transcription. The transcript becomes the job's result.
+func (s *TranscriptionService) TranscribeAsync(ctx context.Context, officerID string, upload AudioUpload) (*jobs.Job, error) {
+	officerObjectID, err := primitive.ObjectIDFromHex(officerID)
+	if err != nil {
+		return nil, err
+	}
+	audio, req, err := s.prepare(officerID, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("transcriptions/%s.%s", primitive.NewObjectID().Hex(), req.Format)
+	if _, err := s.store.Put(ctx, key, bytes.NewReader(audio)); err != nil {
+		return nil, err
+	}
+
+	job, err := s.queue.Enqueue(ctx, JobTranscribe, officerObjectID, transcriptionPayload{
+		AudioKey: key,
+		Format:   req.Format,
+		Language: req.Language,
+		Duration: req.Duration,
+	})
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return nil, err
+	}
+	return job, nil
+}
+
+// runTranscriptionJob transcribes a stored recording. The recording is
+// deleted once it is transcribed or the job gives up.
+func (s *TranscriptionService) runTranscriptionJob(ctx context.Context, job *jobs.Job) (interface{}, error) {
+	var payload transcriptionPayload
+	if err := job.Decode(&payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	file, err := s.store.Open(ctx, payload.AudioKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, jobs.Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	audio, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	transcript, err := s.transcribe(ctx, audio, TranscriptionRequest{
+		Format:   payload.Format,
+		Language: payload.Language,
+		Duration: payload.Duration,
+	})
+	tooLong := errors.Is(err, ErrAudioTooLong)
+	if err == nil || tooLong || job.LastAttempt() {
+		if err := s.store.Delete(context.Background(), payload.AudioKey); err != nil {
+			log.Printf("Failed to delete transcribed recording %s: %v", payload.AudioKey, err)
+		}
+	}
+	if tooLong {
+		return nil, jobs.Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// prepare reads and validates a recording and works out the language to
+// transcribe it in.
+func (s *TranscriptionService) prepare(officerID string, upload AudioUpload) ([]byte, TranscriptionRequest, error) {
+	audio, err := io.ReadAll(io.LimitReader(upload.Audio, s.maxSize+1))
+	if err != nil {
+		return nil, TranscriptionRequest{}, err
+	}
+	if int64(len(audio)) > s.maxSize {
+		return nil, TranscriptionRequest{}, ErrAudioTooLarge
+	}
+
+	format := detectAudioFormat(audio)
+	if format == "" {
+		return nil, TranscriptionRequest{}, ErrUnsupportedAudioFormat
+	}
+
+	// The duration can only be read up front from WAV headers; other
+	// formats are checked against what the transcriber reports.
+	var duration time.Duration
+	if format == "wav" {
+		if duration, err = wavDuration(audio); err != nil {
+			return nil, TranscriptionRequest{}, &ValidationError{Problems: []string{err.Error()}}
+		}
+		if err := s.checkDuration(duration); err != nil {
+			return nil, TranscriptionRequest{}, err
+		}
+	}
+
+	language := upload.Language
+	if language == "" && upload.FIRID != "" {
+		fir, err := s.firService.GetFIRByID(upload.FIRID, officerID)
+		if err != nil {
+			return nil, TranscriptionRequest{}, err
+		}
+		language = fir.Language
+	}
+
+	return audio, TranscriptionRequest{
+		Format:   format,
+		Language: languageCode(language),
+		Duration: duration,
+	}, nil
+}
+
+// transcribe sends a validated recording to the transcriber, checks the
+// duration it reports, and labels the segments with speakers and groups
+// them into turns. A failed diarization leaves the speakers the
+// transcriber reported rather than failing the transcription.
+func (s *TranscriptionService) transcribe(ctx context.Context, audio []byte, req TranscriptionRequest) (*Transcript, error) {
+	req.Audio = bytes.NewReader(audio)
+	transcript, err := s.transcriber.Transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDuration(time.Duration(transcript.Duration * float64(time.Second))); err != nil {
+		return nil, err
+	}
+
+	if s.diarizer != nil && len(transcript.Segments) > 0 {
+		req.Audio = bytes.NewReader(audio)
+		if err := s.diarizer.Diarize(ctx, req, transcript); err != nil {
+			log.Printf("Diarization with %s failed: %v", s.diarizer.Name(), err)
+		}
+	}
+	transcript.Turns = buildTurns(transcript.Segments)
+	transcript.Speakers = summarizeSpeakers(transcript.Turns)
+	return transcript, nil
+}
+
+func (s *TranscriptionService) checkDuration(duration time.Duration) error {
+	if s.maxDuration > 0 && duration > s.maxDuration {
+		return fmt.Errorf("%w: %s exceeds the %s limit", ErrAudioTooLong, duration.Round(time.Second), s.maxDuration)
+	}
+	return nil
+}
+
+// detectAudioFormat identifies a recording by its magic bytes rather than
+// its file name, since browsers often label MediaRecorder output as WAV.
+// It returns the format's usual file extension, or "" if unrecognised.
+func detectAudioFormat(data []byte) string {
+	switch {
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WAVE":
+		return "wav"
+	case len(data) >= 4 && string(data[0:4]) == "OggS":
+		return "ogg"
+	case len(data) >= 4 && string(data[0:4]) == "fLaC":
+		return "flac"
+	case len(data) >= 4 && bytes.Equal(data[0:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "webm"
+	case len(data) >= 12 && string(data[4:8]) == "ftyp":
+		return "m4a"
+	case len(data) >= 3 && string(data[0:3]) == "ID3":
+		return "mp3"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return "mp3"
+	}
+	return ""
+}
+
+// wavDuration reads the length of a WAV recording from its fmt and data
+// chunks.
+func wavDuration(data []byte) (time.Duration, error) {
+	var byteRate uint32
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := binary.LittleEndian.Uint32(data[offset+4 : offset+8])
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if body+12 > len(data) {
+				return 0, errors.New("WAV fmt chunk is truncated")
+			}
+			byteRate = binary.LittleEndian.Uint32(data[body+8 : body+12])
+		case "data":
+			if byteRate == 0 {
+				return 0, errors.New("WAV file has no valid fmt chunk before its data")
+			}
+			// Streamed recordings may leave the size unset; use what is
+			// actually present.
+			if available := uint32(len(data) - body); size == 0 || size == 0xFFFFFFFF || size > available {
+				size = available
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		}
+		offset = body + int(size) + int(size%2)
+	}
+	return 0, errors.New("WAV file has no data chunk")
+}
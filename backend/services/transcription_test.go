@@ -0,0 +1,131 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testWAV builds a PCM WAV recording of the given length at 16 kHz mono.
+func testWAV(seconds float64) []byte {
+	const byteRate = 32000
+	data := make([]byte, int(seconds*byteRate))
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+len(data)))
+	b.WriteString("WAVE")
+	b.WriteString("fmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, uint16(1))     // PCM
+	binary.Write(&b, binary.LittleEndian, uint16(1))     // mono
+	binary.Write(&b, binary.LittleEndian, uint32(16000)) // sample rate
+	binary.Write(&b, binary.LittleEndian, uint32(byteRate))
+	binary.Write(&b, binary.LittleEndian, uint16(2))  // block align
+	binary.Write(&b, binary.LittleEndian, uint16(16)) // bits per sample
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+func TestDetectAudioFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"wav", testWAV(0.1), "wav"},
+		{"ogg", []byte("OggS\x00\x02"), "ogg"},
+		{"flac", []byte("fLaC\x00"), "flac"},
+		{"webm", []byte{0x1A, 0x45, 0xDF, 0xA3, 0x01}, "webm"},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A "), "m4a"},
+		{"mp3 with ID3", []byte("ID3\x04\x00"), "mp3"},
+		{"mp3 frame", []byte{0xFF, 0xFB, 0x90}, "mp3"},
+		{"text", []byte("hello world"), ""},
+		{"RIFF but not WAVE", []byte("RIFF\x00\x00\x00\x00AVI "), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := detectAudioFormat(tt.data); got != tt.want {
+			t.Errorf("detectAudioFormat(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWAVDuration(t *testing.T) {
+	streamed := testWAV(1.5)
+	binary.LittleEndian.PutUint32(streamed[40:44], 0xFFFFFFFF)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    time.Duration
+		wantErr bool
+	}{
+		{"whole recording", testWAV(2), 2 * time.Second, false},
+		{"streamed without a size", streamed, 1500 * time.Millisecond, false},
+		{"truncated data", testWAV(2)[:44+16000], 500 * time.Millisecond, false},
+		{"no data chunk", testWAV(1)[:36], 0, true},
+		{"no fmt chunk", append([]byte("RIFF\x00\x00\x00\x00WAVEdata\x04\x00\x00\x00"), 0, 0, 0, 0), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wavDuration(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wavDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("wavDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranscribeWithFake(t *testing.T) {
+	service := &TranscriptionService{
+		transcriber: &FakeTranscriber{Text: "My phone was stolen. Near the bus stop.", Confidence: 0.9},
+		maxSize:     1 << 20,
+		maxDuration: 10 * time.Second,
+	}
+
+	tests := []struct {
+		name      string
+		audio     []byte
+		language  string
+		wantErr   error
+		wantTurns int
+	}{
+		{"wav", testWAV(4), "Hindi", nil, 1},
+		{"ogg of unknown length", []byte("OggS recording"), "", nil, 1},
+		{"too long", testWAV(11), "", ErrAudioTooLong, 0},
+		{"too large", make([]byte, 1<<20+1), "", ErrAudioTooLarge, 0},
+		{"not audio", []byte("hello"), "", ErrUnsupportedAudioFormat, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript, err := service.Transcribe(context.Background(), "", AudioUpload{
+				Audio:    bytes.NewReader(tt.audio),
+				Language: tt.language,
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transcribe() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transcribe() error = %v", err)
+			}
+			if len(transcript.Segments) != 2 || len(transcript.Turns) != tt.wantTurns {
+				t.Errorf("got %d segments and %d turns, want 2 and %d", len(transcript.Segments), len(transcript.Turns), tt.wantTurns)
+			}
+			if tt.language == "Hindi" && (transcript.Language != "hi" || transcript.Duration != 4) {
+				t.Errorf("language, duration = %q, %v, want %q, 4", transcript.Language, transcript.Duration, "hi")
+			}
+		})
+	}
+}
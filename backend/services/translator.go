@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"legalassist-ai-backend/config"
+)
+
+// Translator detects the language of text and translates between
+// languages. Languages are ISO 639-1 codes.
+type Translator interface {
+	Name() string
+	// Detect returns the language text is written in. hint is the language
+	// the text was declared to be in, used to choose between languages that
+	// share a script, such as Hindi and Marathi.
+	Detect(ctx context.Context, text, hint string) (string, error)
+	Translate(ctx context.Context, text, from, to string) (string, error)
+}
+
+// NewTranslator builds the translator selected by cfg.TranslatorProvider.
+// When none is configured, the LLM translator is used whenever an LLM is,
+// and the fake otherwise.
+func NewTranslator(cfg *config.Config) Translator {
+	llm := NewLLMProvider(cfg)
+	name := strings.ToLower(cfg.TranslatorProvider)
+	if name == "" {
+		name = "fake"
+		if llm.Name() != "mock" {
+			name = "llm"
+		}
+	}
+
+	switch name {
+	case "llm":
+		return NewLLMTranslator(llm)
+	case "fake":
+		return NewFakeTranslator()
+	default:
+		log.Printf("Unknown translator %q, falling back to fake", cfg.TranslatorProvider)
+		return NewFakeTranslator()
+	}
+}
+
+// languageNames maps ISO 639-1 codes back to the language names used on
+// FIRs.
+var languageNames = func() map[string]string {
+	names := make(map[string]string, len(languageCodes))
+	for name, code := range languageCodes {
+		names[code] = name
+	}
+	return names
+}()
+
+// languageName returns the FIR language name for an ISO 639-1 code, or the
+// code itself if it is not a known language.
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// scriptLanguages lists the languages written in each script, most common
+// first.
+var scriptLanguages = []struct {
+	script    *unicode.RangeTable
+	languages []string
+}{
+	{unicode.Devanagari, []string{"hi", "mr"}},
+	{unicode.Bengali, []string{"bn", "as"}},
+	{unicode.Tamil, []string{"ta"}},
+	{unicode.Telugu, []string{"te"}},
+	{unicode.Gujarati, []string{"gu"}},
+	{unicode.Kannada, []string{"kn"}},
+	{unicode.Malayalam, []string{"ml"}},
+	{unicode.Gurmukhi, []string{"pa"}},
+	{unicode.Oriya, []string{"or"}},
+	{unicode.Arabic, []string{"ur"}},
+	{unicode.Latin, []string{"en"}},
+}
+
+// scriptCandidates returns the languages that may be written in text's
+// dominant script, or nil if it has no letters in a known script.
+func scriptCandidates(text string) []string {
+	counts := make([]int, len(scriptLanguages))
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r) {
+			continue
+		}
+		for i, s := range scriptLanguages {
+			if unicode.Is(s.script, r) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	best := -1
+	for i, count := range counts {
+		if count > 0 && (best < 0 || count > counts[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	return scriptLanguages[best].languages
+}
+
+// detectByScript picks a language from text's script, using hint to choose
+// between languages that share it. It reports false when the script alone
+// cannot settle the language, including Latin text declared to be in an
+// Indian language, which may be romanised.
+func detectByScript(text, hint string) (string, bool) {
+	candidates := scriptCandidates(text)
+	if len(candidates) == 0 {
+		return "", false
+	}
+	for _, language := range candidates {
+		if language == hint {
+			return language, true
+		}
+	}
+	if candidates[0] == "en" && hint != "" {
+		return "en", false
+	}
+	return candidates[0], len(candidates) == 1
+}
+
+const translationSystemPrompt = `You translate First Information Reports and complaints for Indian police officers.
+Translate faithfully and completely. Do not summarise, explain or add anything.
+Keep names, addresses, phone numbers, dates, amounts and legal section numbers exactly as written.
+Preserve line breaks. Reply with the translation only.`
+
+// LLMTranslator translates with the configured language model. Languages
+// that can be told apart by script are detected without calling the model.
+type LLMTranslator struct {
+	llm LLMProvider
+}
+
+func NewLLMTranslator(llm LLMProvider) *LLMTranslator {
+	return &LLMTranslator{llm: llm}
+}
+
+func (t *LLMTranslator) Name() string {
+	return "llm"
+}
+
+func (t *LLMTranslator) Detect(ctx context.Context, text, hint string) (string, error) {
+	language, certain := detectByScript(text, hint)
+	if certain || language == "" {
+		return language, nil
+	}
+
+	prompt := "Text:\n" + text
+	if hint != "" {
+		prompt += "\n\nThe text was declared to be in language " + hint + "."
+	}
+	raw, err := t.llm.Complete(ctx, CompletionRequest{
+		System: "Identify the language of the text, including languages written in another script, such as Hindi in Latin letters. " +
+			`Reply with a JSON object {"language": "<ISO 639-1 code>"}.`,
+		Prompt:      prompt,
+		Temperature: 0,
+		MaxTokens:   20,
+		JSON:        true,
+	})
+	if err != nil {
+		return language, fmt.Errorf("language detection: %w", err)
+	}
+
+	var result struct {
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal([]byte(repairJSON(raw)), &result); err != nil || result.Language == "" {
+		return language, fmt.Errorf("language detection: unusable reply %q", raw)
+	}
+	return languageCode(result.Language), nil
+}
+
+func (t *LLMTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if from == to || strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+
+	translation, err := t.llm.Complete(ctx, CompletionRequest{
+		System:      translationSystemPrompt,
+		Prompt:      fmt.Sprintf("Translate from %s to %s:\n\n%s", languageName(from), languageName(to), text),
+		Temperature: 0,
+	})
+	if err != nil {
+		return "", fmt.Errorf("translation from %s to %s: %w", from, to, err)
+	}
+	translation = strings.TrimSpace(translation)
+	if translation == "" {
+		return "", fmt.Errorf("translation from %s to %s: empty reply", from, to)
+	}
+	return translation, nil
+}
+
+// FakeTranslator detects languages by script alone and "translates" by
+// tagging the text with the language pair, so the multilingual flow can be
+// exercised without a model.
+type FakeTranslator struct{}
+
+func NewFakeTranslator() *FakeTranslator {
+	return &FakeTranslator{}
+}
+
+func (t *FakeTranslator) Name() string {
+	return "fake"
+}
+
+func (t *FakeTranslator) Detect(ctx context.Context, text, hint string) (string, error) {
+	language, _ := detectByScript(text, hint)
+	return language, nil
+}
+
+func (t *FakeTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if from == to || strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	return fmt.Sprintf("[%s→%s] %s", from, to, text), nil
+}
@@ -0,0 +1,54 @@
+// Package storage keeps binary objects, such as evidence files, behind a
+// small blob store interface so the backing store can be swapped without
+// touching the services that use it.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrNotFound is returned when no blob exists under a key.
+	ErrNotFound = errors.New("blob not found")
+	// ErrOffsetMismatch is returned when an append does not start at the
+	// current end of the blob.
+	ErrOffsetMismatch = errors.New("offset does not match blob size")
+	// ErrInvalidKey is returned for keys that are empty or would escape the
+	// store.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore stores blobs under slash-separated keys such as
+// "evidence/<fir>/<id>".
+type BlobStore interface {
+	// Put writes r to key, replacing any existing blob, and returns the
+	// number of bytes written. A failed Put leaves any previous blob intact.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Append writes r to the end of the blob at key, creating it when
+	// offset is 0, and returns the new size. It fails with
+	// ErrOffsetMismatch unless the blob is exactly offset bytes long.
+	Append(ctx context.Context, key string, offset int64, r io.Reader) (int64, error)
+	// Open returns a reader for the blob at key. It can seek, so a blob can
+	// be checked and then read from the same handle.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Size returns the length of the blob at key.
+	Size(ctx context.Context, key string) (int64, error)
+	// Delete removes the blob at key. Deleting a missing blob is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// contextReader stops a copy once its context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readBlob(t *testing.T, store *LocalStore, key string) string {
+	t.Helper()
+	file, err := store.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%q) error = %v", key, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("reading %q: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestLocalStoreKeys(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalStore(filepath.Join(root, "blobs"))
+	ctx := context.Background()
+
+	tests := []struct {
+		key     string
+		wantErr error
+	}{
+		{"evidence/fir/1", nil},
+		{"a/./b", nil},
+		{"", ErrInvalidKey},
+		{"/etc/passwd", ErrInvalidKey},
+		{"../outside", ErrInvalidKey},
+		{"evidence/../../outside", ErrInvalidKey},
+		{"..", ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, err := store.Put(ctx, tt.key, strings.NewReader("data"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Put(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			if _, err := store.Append(ctx, tt.key, 0, strings.NewReader("data")); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Append(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if _, err := store.Open(ctx, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if _, err := store.Size(ctx, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Size(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if err := store.Delete(ctx, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a blob was written outside the store: %v", err)
+	}
+}
+
+func TestLocalStorePut(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	n, err := store.Put(ctx, "evidence/fir/1", strings.NewReader("first"))
+	if err != nil || n != 5 {
+		t.Fatalf("Put() = %d, %v, want 5, nil", n, err)
+	}
+	if _, err := store.Put(ctx, "evidence/fir/1", strings.NewReader("second")); err != nil {
+		t.Fatalf("Put() replacing error = %v", err)
+	}
+	if got := readBlob(t, store, "evidence/fir/1"); got != "second" {
+		t.Errorf("blob = %q, want %q", got, "second")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := store.Put(cancelled, "evidence/fir/1", strings.NewReader("third")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Put() with a cancelled context error = %v, want context.Canceled", err)
+	}
+	if got := readBlob(t, store, "evidence/fir/1"); got != "second" {
+		t.Errorf("blob after a failed Put = %q, want %q", got, "second")
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(store.root, "evidence", "fir"))
+	if len(entries) != 1 {
+		t.Errorf("store directory has %d entries, want the blob alone", len(entries))
+	}
+}
+
+func TestLocalStoreAppend(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	key := "evidence/fir/2"
+
+	tests := []struct {
+		name     string
+		offset   int64
+		chunk    string
+		wantSize int64
+		wantErr  error
+	}{
+		{"creates the blob", 0, "abc", 3, nil},
+		{"appends at the end", 3, "def", 6, nil},
+		{"repeated chunk", 3, "def", 6, ErrOffsetMismatch},
+		{"gap", 9, "ghi", 6, ErrOffsetMismatch},
+		{"restart from zero", 0, "abc", 6, ErrOffsetMismatch},
+		{"next chunk", 6, "ghi", 9, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := store.Append(ctx, key, tt.offset, strings.NewReader(tt.chunk))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Append() error = %v, want %v", err, tt.wantErr)
+			}
+			if size != tt.wantSize {
+				t.Errorf("Append() size = %d, want %d", size, tt.wantSize)
+			}
+		})
+	}
+
+	if got := readBlob(t, store, key); got != "abcdefghi" {
+		t.Errorf("blob = %q, want %q", got, "abcdefghi")
+	}
+	if size, err := store.Size(ctx, key); size != 9 || err != nil {
+		t.Errorf("Size() = %d, %v, want 9, nil", size, err)
+	}
+}
+
+func TestLocalStoreMissing(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	if _, err := store.Open(ctx, "evidence/none"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Size(ctx, "evidence/none"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Size() error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "evidence/none"); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+
+	store.Put(ctx, "evidence/one", strings.NewReader("data"))
+	if err := store.Delete(ctx, "evidence/one"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Open(ctx, "evidence/one"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoreOpenSeeks(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	store.Put(ctx, "evidence/three", strings.NewReader("checked then served"))
+
+	file, err := store.Open(ctx, "evidence/three")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+
+	first, _ := io.ReadAll(file)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+	second, _ := io.ReadAll(file)
+	if string(first) != "checked then served" || string(second) != string(first) {
+		t.Errorf("reads = %q, then %q, want the blob twice", first, second)
+	}
+}
@@ -1,19 +1,10 @@
 package utils
 
 import (
-	"fmt"
-	"math/rand"
 	"strings"
 	"time"
 )
 
-func GenerateFIRNumber() string {
-	rand.Seed(time.Now().UnixNano())
-	year := time.Now().Year()
-	sequence := rand.Intn(9999) + 1
-	return fmt.Sprintf("FIR/%d/%04d", year, sequence)
-}
-
 func ToLower(s string) string {
 	return strings.ToLower(s)
 }
@@ -9,19 +9,27 @@ import (
 
 var jwtSecret = []byte("your-secret-key") // In production, load from config
 
+// Claims are the JWT claims. Permissions are the user's effective
+// permissions when the token was issued; tokens issued before they were
+// added have none. AccessVersion is the user's access version at the time,
+// and the token is refused once the user's version moves on.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID        string   `json:"user_id"`
+	Email         string   `json:"email"`
+	Role          string   `json:"role"`
+	Permissions   []string `json:"permissions,omitempty"`
+	AccessVersion int      `json:"access_version,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(userID, email, role string) (string, error) {
+func GenerateJWT(userID, email, role string, permissions []string, accessVersion int) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:        userID,
+		Email:         email,
+		Role:          role,
+		Permissions:   permissions,
+		AccessVersion: accessVersion,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),